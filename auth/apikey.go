@@ -0,0 +1,61 @@
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// APIKeys authenticate with static API keys in the Authorization header: ApiKey key,
+// the map is from API key to the Principal
+type APIKeys map[string]Principal
+
+// ParseKeys parse the API keys in the format: key1=name1:role1|role2,key2=name2 (roles are optional)
+func ParseKeys(s string) (APIKeys, error) {
+	keys := APIKeys{}
+	for _, kv := range strings.Split(s, ",") {
+		if strings.TrimSpace(kv) == "" {
+			continue
+		}
+		p := strings.SplitN(kv, "=", 2)
+		if len(p) != 2 || strings.TrimSpace(p[0]) == "" || strings.TrimSpace(p[1]) == "" {
+			return nil, fmt.Errorf("invalid API key definition: %s", kv)
+		}
+		name, roles := strings.TrimSpace(p[1]), []string{}
+		if i := strings.Index(name, ":"); i != -1 {
+			for _, r := range strings.Split(name[i+1:], "|") {
+				if r = strings.TrimSpace(r); r != "" {
+					roles = append(roles, r)
+				}
+			}
+			name = strings.TrimSpace(name[:i])
+		}
+		if name == "" {
+			return nil, fmt.Errorf("invalid API key definition: %s", kv)
+		}
+		keys[strings.TrimSpace(p[0])] = Principal{Name: name, Roles: roles, Method: "apikey"}
+	}
+	return keys, nil
+}
+
+// Authenticate impl from Authenticator
+func (keys APIKeys) Authenticate(r *http.Request) (*Principal, error) {
+	k, ok := authorization(r, "ApiKey")
+	if !ok {
+		return nil, ErrNoCredentials
+	}
+
+	// compare all keys in constant time
+	var found *Principal
+	for key, p := range keys {
+		if subtle.ConstantTimeCompare([]byte(key), []byte(k)) == 1 {
+			pc := p
+			found = &pc
+		}
+	}
+	if found == nil {
+		return nil, ErrInvalidCredentials
+	}
+	return found, nil
+}
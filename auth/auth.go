@@ -0,0 +1,160 @@
+// Package auth authenticate the requests (static API keys, HMAC signed tokens and JWT)
+// and put the authenticated Principal in the context of the request.
+package auth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+const (
+	// EnvKeys is the Env-Variable with the static API keys: key1=name1:role1|role2,key2=name2
+	EnvKeys = "HEROES_AUTH_KEYS"
+	// EnvHMACSecret is the Env-Variable with the secret for the HMAC signed tokens
+	EnvHMACSecret = "HEROES_AUTH_HMAC_SECRET"
+	// EnvJWKS is the Env-Variable with the path to the JWKS file for the JWT verification
+	EnvJWKS = "HEROES_AUTH_JWKS"
+	// EnvIssuer is the Env-Variable with the expected JWT issuer (optional)
+	EnvIssuer = "HEROES_AUTH_ISSUER"
+	// EnvAudience is the Env-Variable with the expected JWT audience (optional)
+	EnvAudience = "HEROES_AUTH_AUDIENCE"
+	// EnvPublicRead is the Env-Variable, if it is set to false, read requests need authentication too
+	EnvPublicRead = "HEROES_AUTH_PUBLIC_READ"
+)
+
+var (
+	// ErrNoCredentials if the request contains no credentials for the Authenticator
+	ErrNoCredentials = errors.New("No credentials")
+	// ErrInvalidCredentials if the credentials are not valid
+	ErrInvalidCredentials = errors.New("Invalid credentials")
+)
+
+// Principal is the authenticated user
+type Principal struct {
+	Name  string   `json:"name"`
+	Roles []string `json:"roles"`
+	// Method is the authentication method: apikey, hmac or jwt
+	Method string `json:"method"`
+}
+
+// Authenticator authenticate the request
+type Authenticator interface {
+	// Authenticate returns ErrNoCredentials, if the request contains no credentials for this Authenticator
+	Authenticate(r *http.Request) (*Principal, error)
+}
+
+// Chain try all Authenticators, until the first found credentials
+type Chain []Authenticator
+
+// Authenticate impl from Authenticator
+func (ch Chain) Authenticate(r *http.Request) (*Principal, error) {
+	for _, a := range ch {
+		p, err := a.Authenticate(r)
+		if err != ErrNoCredentials {
+			return p, err
+		}
+	}
+	return nil, ErrNoCredentials
+}
+
+type principalKey struct{}
+
+// WithPrincipal returns a new Context, which carries the Principal (and the user name for the services)
+func WithPrincipal(c context.Context, p *Principal) context.Context {
+	return service.WithUser(context.WithValue(c, principalKey{}, p), p.Name)
+}
+
+// PrincipalFromContext returns the Principal or nil, if the request is not authenticated
+func PrincipalFromContext(c context.Context) *Principal {
+	p, _ := c.Value(principalKey{}).(*Principal)
+	return p
+}
+
+// IsRead is true for the methods, which read only: GET, HEAD and OPTIONS
+func IsRead(r *http.Request) bool {
+	return r.Method == "GET" || r.Method == "HEAD" || r.Method == "OPTIONS"
+}
+
+// Handler authenticate the requests with the Authenticator,
+// requests for which public is true, are allowed without credentials
+func Handler(h http.Handler, a Authenticator, public func(r *http.Request) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		p, err := a.Authenticate(r)
+		if err == ErrNoCredentials && public != nil && public(r) {
+			h.ServeHTTP(w, r)
+			return
+		} else if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="heroes"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+
+		h.ServeHTTP(w, r.WithContext(WithPrincipal(r.Context(), p)))
+	})
+}
+
+// FromEnv create the Authenticator, which is configured by the Env-Variables
+// and the public function for the EnvPublicRead (nil Authenticator: no authentication)
+func FromEnv() (Authenticator, func(r *http.Request) bool, error) {
+	ch := Chain{}
+
+	if keys := os.Getenv(EnvKeys); keys != "" {
+		k, err := ParseKeys(keys)
+		if err != nil {
+			return nil, nil, err
+		}
+		ch = append(ch, k)
+	}
+
+	if secret := os.Getenv(EnvHMACSecret); secret != "" {
+		ch = append(ch, HMAC{Secret: []byte(secret)})
+	}
+
+	if file := os.Getenv(EnvJWKS); file != "" {
+		b, err := ioutil.ReadFile(file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("can not read JWKS file: %v", err)
+		}
+		keys, err := ParseJWKS(b)
+		if err != nil {
+			return nil, nil, err
+		}
+		ch = append(ch, JWT{Keys: keys, Issuer: os.Getenv(EnvIssuer), Audience: os.Getenv(EnvAudience)})
+	}
+
+	if len(ch) == 0 {
+		return nil, nil, nil
+	}
+
+	public := IsRead
+	if pr := os.Getenv(EnvPublicRead); pr != "" {
+		if b, err := strconv.ParseBool(pr); err != nil {
+			return nil, nil, fmt.Errorf("invalid %s: %s", EnvPublicRead, pr)
+		} else if !b {
+			public = nil
+		}
+	}
+
+	return ch, public, nil
+}
+
+// bearerToken returns the token from the Authorization header: Bearer token
+func bearerToken(r *http.Request) (string, bool) {
+	return authorization(r, "Bearer")
+}
+
+func authorization(r *http.Request, scheme string) (string, bool) {
+	a := r.Header.Get("Authorization")
+	if len(a) <= len(scheme)+1 || !strings.EqualFold(a[:len(scheme)], scheme) || a[len(scheme)] != ' ' {
+		return "", false
+	}
+	return strings.TrimSpace(a[len(scheme)+1:]), true
+}
@@ -0,0 +1,127 @@
+package auth
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestAPIKeys(t *testing.T) {
+	keys, err := ParseKeys("k1=mario:editor|admin, k2=jasmin")
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+
+	r := httptest.NewRequest("POST", "http://localhost:8080/api/heroes", nil)
+	_, err = keys.Authenticate(r)
+	if err != ErrNoCredentials {
+		t.Errorf("expect ErrNoCredentials, got: %v", err)
+	}
+
+	r.Header.Set("Authorization", "ApiKey k2")
+	p, err := keys.Authenticate(r)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if p.Name != "jasmin" || p.Method != "apikey" {
+		t.Errorf("expect jasmin by apikey, got: %v", p)
+	}
+
+	r.Header.Set("Authorization", "ApiKey k1")
+	p, _ = keys.Authenticate(r)
+	if p.Name != "mario" || len(p.Roles) != 2 || p.Roles[1] != "admin" {
+		t.Errorf("expect mario as editor and admin, got: %v", p)
+	}
+
+	r.Header.Set("Authorization", "ApiKey k3")
+	_, err = keys.Authenticate(r)
+	if err != ErrInvalidCredentials {
+		t.Errorf("expect ErrInvalidCredentials, got: %v", err)
+	}
+}
+
+func TestParseKeysFail(t *testing.T) {
+	for _, s := range []string{"k1", "k1=", "=mario", "k1=:admin"} {
+		if _, err := ParseKeys(s); err == nil {
+			t.Errorf("err expected for: %v", s)
+		}
+	}
+}
+
+func TestChain(t *testing.T) {
+	ch := Chain{APIKeys{"k1": Principal{Name: "mario"}}, HMAC{Secret: []byte("secret")}}
+
+	token, _ := HMAC{Secret: []byte("secret")}.Sign(Claims{Subject: "jasmin", Expires: time.Now().Add(time.Hour).Unix()})
+	r := httptest.NewRequest("POST", "http://localhost:8080/api/heroes", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+
+	p, err := ch.Authenticate(r)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if p.Name != "jasmin" {
+		t.Errorf("jasmin != %v", p.Name)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(service.UserFromContext(r.Context())))
+	}), APIKeys{"k1": Principal{Name: "mario"}}, IsRead)
+
+	for _, tc := range []struct {
+		method string
+		key    string
+		status int
+		user   string
+	}{
+		{"GET", "", http.StatusOK, "anonymous"},
+		{"GET", "k1", http.StatusOK, "mario"},
+		{"POST", "", http.StatusUnauthorized, ""},
+		{"DELETE", "wrong", http.StatusUnauthorized, ""},
+		{"PUT", "k1", http.StatusOK, "mario"},
+	} {
+		r := httptest.NewRequest(tc.method, "http://localhost:8080/api/heroes", nil)
+		if tc.key != "" {
+			r.Header.Set("Authorization", "ApiKey "+tc.key)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tc.status {
+			t.Errorf("%v != %v for: %v", tc.status, w.Code, tc)
+		}
+		if tc.status == http.StatusOK && w.Body.String() != tc.user {
+			t.Errorf("%v != %v", tc.user, w.Body.String())
+		}
+		if tc.status == http.StatusUnauthorized && w.Header().Get("WWW-Authenticate") == "" {
+			t.Errorf("expect WWW-Authenticate header")
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	defer os.Unsetenv(EnvKeys)
+	defer os.Unsetenv(EnvPublicRead)
+
+	a, _, err := FromEnv()
+	if a != nil || err != nil {
+		t.Errorf("expect no Authenticator and no err, got: %v", err)
+	}
+
+	os.Setenv(EnvKeys, "k1=mario")
+	a, public, err := FromEnv()
+	if a == nil || public == nil || err != nil {
+		t.Errorf("expect Authenticator with public reads, got: %v", err)
+	}
+
+	os.Setenv(EnvPublicRead, "false")
+	_, public, _ = FromEnv()
+	if public != nil {
+		t.Errorf("expect no public reads")
+	}
+}
@@ -0,0 +1,52 @@
+package auth
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHMAC(t *testing.T) {
+	h := HMAC{Secret: []byte("secret")}
+
+	token, err := h.Sign(Claims{Subject: "mario", Roles: []string{"editor"}, Expires: time.Now().Add(time.Hour).Unix()})
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+
+	r := httptest.NewRequest("POST", "http://localhost:8080/api/heroes", nil)
+	r.Header.Set("Authorization", "Bearer "+token)
+	p, err := h.Authenticate(r)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if p.Name != "mario" || len(p.Roles) != 1 || p.Method != "hmac" {
+		t.Errorf("expect mario as editor by hmac, got: %v", p)
+	}
+}
+
+func TestHMACInvalid(t *testing.T) {
+	h := HMAC{Secret: []byte("secret")}
+
+	expired, _ := h.Sign(Claims{Subject: "mario", Expires: time.Now().Add(-time.Hour).Unix()})
+	noExpires, _ := h.Sign(Claims{Subject: "mario"})
+	other, _ := HMAC{Secret: []byte("other")}.Sign(Claims{Subject: "mario", Expires: time.Now().Add(time.Hour).Unix()})
+	noSubject, _ := h.Sign(Claims{Expires: time.Now().Add(time.Hour).Unix()})
+
+	for _, token := range []string{expired, noExpires, other, noSubject, "abc.def"} {
+		r := httptest.NewRequest("POST", "http://localhost:8080/api/heroes", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		_, err := h.Authenticate(r)
+		if err == nil || err == ErrNoCredentials {
+			t.Errorf("expect invalid credentials for: %v, got: %v", token, err)
+		}
+	}
+
+	// a JWT is not a HMAC token
+	r := httptest.NewRequest("POST", "http://localhost:8080/api/heroes", nil)
+	r.Header.Set("Authorization", "Bearer a.b.c")
+	_, err := h.Authenticate(r)
+	if err != ErrNoCredentials {
+		t.Errorf("expect ErrNoCredentials, got: %v", err)
+	}
+}
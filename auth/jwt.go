@@ -0,0 +1,214 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// JWT authenticate with JSON Web Tokens in the Authorization header: Bearer token,
+// the signature (RS256 or ES256) is verified with the keys from a local configured JWKS
+type JWT struct {
+	// Keys from the JWKS, the map is from key ID (kid) to the public key
+	Keys map[string]crypto.PublicKey
+	// Issuer and Audience are checked, if they are not empty
+	Issuer   string
+	Audience string
+}
+
+type jwtHeader struct {
+	Alg string `json:"alg"`
+	Kid string `json:"kid"`
+}
+
+type jwtClaims struct {
+	Subject   string          `json:"sub"`
+	Issuer    string          `json:"iss"`
+	Audience  json.RawMessage `json:"aud"`
+	Expires   int64           `json:"exp"`
+	NotBefore int64           `json:"nbf"`
+	Roles     []string        `json:"roles"`
+}
+
+// Authenticate impl from Authenticator
+func (j JWT) Authenticate(r *http.Request) (*Principal, error) {
+	token, ok := bearerToken(r)
+	if !ok || strings.Count(token, ".") != 2 {
+		return nil, ErrNoCredentials
+	}
+
+	p, err := j.Verify(token)
+	if err != nil {
+		return nil, fmt.Errorf("%v: %v", ErrInvalidCredentials, err)
+	}
+	return p, nil
+}
+
+// Verify the signature and the claims of the token
+func (j JWT) Verify(token string) (*Principal, error) {
+	parts := strings.Split(token, ".")
+
+	h := jwtHeader{}
+	if err := decodeSegment(parts[0], &h); err != nil {
+		return nil, fmt.Errorf("invalid header: %v", err)
+	}
+
+	key, err := j.key(h.Kid)
+	if err != nil {
+		return nil, err
+	}
+
+	sig, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("invalid signature: %v", err)
+	}
+	if err = verifySignature(h.Alg, key, parts[0]+"."+parts[1], sig); err != nil {
+		return nil, err
+	}
+
+	c := jwtClaims{}
+	if err = decodeSegment(parts[1], &c); err != nil {
+		return nil, fmt.Errorf("invalid claims: %v", err)
+	}
+
+	now := time.Now().Unix()
+	switch {
+	case c.Subject == "":
+		return nil, errors.New("no subject")
+	case c.Expires == 0 || now >= c.Expires:
+		return nil, errors.New("token is expired")
+	case c.NotBefore != 0 && now < c.NotBefore:
+		return nil, errors.New("token is not valid yet")
+	case j.Issuer != "" && c.Issuer != j.Issuer:
+		return nil, fmt.Errorf("invalid issuer: %s", c.Issuer)
+	case j.Audience != "" && !hasAudience(c.Audience, j.Audience):
+		return nil, errors.New("invalid audience")
+	}
+
+	return &Principal{Name: c.Subject, Roles: c.Roles, Method: "jwt"}, nil
+}
+
+func (j JWT) key(kid string) (crypto.PublicKey, error) {
+	if kid == "" && len(j.Keys) == 1 {
+		for _, k := range j.Keys {
+			return k, nil
+		}
+	}
+	k, ok := j.Keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown key ID: %s", kid)
+	}
+	return k, nil
+}
+
+func verifySignature(alg string, key crypto.PublicKey, signed string, sig []byte) error {
+	hash := sha256.Sum256([]byte(signed))
+
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		if alg != "RS256" {
+			return fmt.Errorf("invalid algorithm: %s for RSA key", alg)
+		}
+		if err := rsa.VerifyPKCS1v15(k, crypto.SHA256, hash[:], sig); err != nil {
+			return errors.New("invalid signature")
+		}
+	case *ecdsa.PublicKey:
+		if alg != "ES256" || len(sig) != 64 {
+			return fmt.Errorf("invalid algorithm: %s for EC key", alg)
+		}
+		r, s := new(big.Int).SetBytes(sig[:32]), new(big.Int).SetBytes(sig[32:])
+		if !ecdsa.Verify(k, hash[:], r, s) {
+			return errors.New("invalid signature")
+		}
+	default:
+		return fmt.Errorf("unsupported key type: %T", key)
+	}
+	return nil
+}
+
+// the audience is a string or a list of strings
+func hasAudience(raw json.RawMessage, aud string) bool {
+	var one string
+	if json.Unmarshal(raw, &one) == nil {
+		return one == aud
+	}
+	var list []string
+	if json.Unmarshal(raw, &list) == nil {
+		for _, a := range list {
+			if a == aud {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func decodeSegment(seg string, v interface{}) error {
+	b, err := base64.RawURLEncoding.DecodeString(seg)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(b, v)
+}
+
+// ParseJWKS parse a JSON Web Key Set with RSA and EC (P-256) keys
+func ParseJWKS(b []byte) (map[string]crypto.PublicKey, error) {
+	set := struct {
+		Keys []struct {
+			Kty string `json:"kty"`
+			Kid string `json:"kid"`
+			Crv string `json:"crv"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		} `json:"keys"`
+	}{}
+	if err := json.Unmarshal(b, &set); err != nil {
+		return nil, fmt.Errorf("can not parse JWKS: %v", err)
+	}
+
+	keys := map[string]crypto.PublicKey{}
+	for _, k := range set.Keys {
+		switch k.Kty {
+		case "RSA":
+			n, err1 := decodeBigInt(k.N)
+			e, err2 := decodeBigInt(k.E)
+			if err1 != nil || err2 != nil {
+				return nil, fmt.Errorf("invalid RSA key: %s", k.Kid)
+			}
+			keys[k.Kid] = &rsa.PublicKey{N: n, E: int(e.Int64())}
+		case "EC":
+			x, err1 := decodeBigInt(k.X)
+			y, err2 := decodeBigInt(k.Y)
+			if k.Crv != "P-256" || err1 != nil || err2 != nil || !elliptic.P256().IsOnCurve(x, y) {
+				return nil, fmt.Errorf("invalid EC key: %s", k.Kid)
+			}
+			keys[k.Kid] = &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+		default:
+			return nil, fmt.Errorf("unsupported key type: %s", k.Kty)
+		}
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("no keys in JWKS")
+	}
+	return keys, nil
+}
+
+func decodeBigInt(s string) (*big.Int, error) {
+	b, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	return new(big.Int).SetBytes(b), nil
+}
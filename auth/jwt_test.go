@@ -0,0 +1,103 @@
+package auth
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+var (
+	rsaKey, _ = rsa.GenerateKey(rand.Reader, 2048)
+	ecKey, _  = ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+)
+
+func jwks() []byte {
+	b64 := base64.RawURLEncoding.EncodeToString
+	return []byte(fmt.Sprintf(`{"keys": [
+		{"kty": "RSA", "kid": "rsa1", "n": "%s", "e": "%s"},
+		{"kty": "EC", "kid": "ec1", "crv": "P-256", "x": "%s", "y": "%s"}
+	]}`,
+		b64(rsaKey.N.Bytes()), b64(big.NewInt(int64(rsaKey.E)).Bytes()),
+		b64(ecKey.X.FillBytes(make([]byte, 32))), b64(ecKey.Y.FillBytes(make([]byte, 32)))))
+}
+
+func sign(alg, kid string, claims map[string]interface{}) string {
+	b64 := base64.RawURLEncoding.EncodeToString
+	h, _ := json.Marshal(map[string]string{"alg": alg, "kid": kid, "typ": "JWT"})
+	c, _ := json.Marshal(claims)
+	signed := b64(h) + "." + b64(c)
+	hash := sha256.Sum256([]byte(signed))
+
+	var sig []byte
+	switch alg {
+	case "RS256":
+		sig, _ = rsa.SignPKCS1v15(rand.Reader, rsaKey, crypto.SHA256, hash[:])
+	case "ES256":
+		r, s, _ := ecdsa.Sign(rand.Reader, ecKey, hash[:])
+		sig = append(r.FillBytes(make([]byte, 32)), s.FillBytes(make([]byte, 32))...)
+	}
+	return signed + "." + b64(sig)
+}
+
+func TestJWT(t *testing.T) {
+	keys, err := ParseJWKS(jwks())
+	if err != nil {
+		t.Fatalf("no err expected: %v", err)
+	}
+	j := JWT{Keys: keys, Issuer: "https://auth.example.com", Audience: "heroes"}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	for _, alg := range []struct{ alg, kid string }{{"RS256", "rsa1"}, {"ES256", "ec1"}} {
+		token := sign(alg.alg, alg.kid, map[string]interface{}{
+			"sub": "mario", "iss": "https://auth.example.com", "aud": []string{"heroes"}, "exp": exp, "roles": []string{"admin"},
+		})
+
+		r := httptest.NewRequest("DELETE", "http://localhost:8080/api/heroes/1", nil)
+		r.Header.Set("Authorization", "Bearer "+token)
+		p, err := j.Authenticate(r)
+		if err != nil {
+			t.Errorf("no err expected for %v: %v", alg.alg, err)
+			continue
+		}
+		if p.Name != "mario" || p.Roles[0] != "admin" || p.Method != "jwt" {
+			t.Errorf("expect mario as admin by jwt, got: %v", p)
+		}
+	}
+}
+
+func TestJWTInvalid(t *testing.T) {
+	keys, _ := ParseJWKS(jwks())
+	j := JWT{Keys: keys, Audience: "heroes"}
+	exp := time.Now().Add(time.Hour).Unix()
+
+	for name, token := range map[string]string{
+		"expired":   sign("RS256", "rsa1", map[string]interface{}{"sub": "mario", "aud": "heroes", "exp": time.Now().Add(-time.Hour).Unix()}),
+		"no exp":    sign("RS256", "rsa1", map[string]interface{}{"sub": "mario", "aud": "heroes"}),
+		"audience":  sign("RS256", "rsa1", map[string]interface{}{"sub": "mario", "aud": "other", "exp": exp}),
+		"wrong kid": sign("RS256", "ec1", map[string]interface{}{"sub": "mario", "aud": "heroes", "exp": exp}),
+		"unknown":   sign("RS256", "rsa2", map[string]interface{}{"sub": "mario", "aud": "heroes", "exp": exp}),
+		"none":      sign("none", "rsa1", map[string]interface{}{"sub": "mario", "aud": "heroes", "exp": exp}),
+	} {
+		if _, err := j.Verify(token); err == nil {
+			t.Errorf("expect err for: %v", name)
+		}
+	}
+}
+
+func TestParseJWKSFail(t *testing.T) {
+	for _, s := range []string{`{"keys": []}`, `{"keys": [{"kty": "oct"}]}`, `{"keys": [{"kty": "EC", "crv": "P-256", "x": "AA", "y": "AA"}]}`, `no json`} {
+		if _, err := ParseJWKS([]byte(s)); err == nil {
+			t.Errorf("err expected for: %v", s)
+		}
+	}
+}
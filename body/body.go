@@ -0,0 +1,151 @@
+// Package body limit the size and check the Content-Type of the request bodies (per route)
+// and read the bodies strict: JSON without unknown fields and with exactly one value.
+package body
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"mime"
+	"net/http"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// DefaultMaxBytes is the max size of a request body for routes without Rule
+const DefaultMaxBytes = 64 << 10
+
+var (
+	// ErrTooLarge if the body is larger than the max size of the route
+	ErrTooLarge = errors.New("Request body is too large")
+	// ErrMultipleValues if the body contains more than one JSON value
+	ErrMultipleValues = errors.New("Request body must contain a single JSON value")
+)
+
+// Rule for the request body of a route
+type Rule struct {
+	MaxBytes int64
+	// ContentTypes are the supported media types (empty: all), a request without Content-Type is allowed
+	ContentTypes []string
+}
+
+// Text is a Rule for a small text/plain body
+func Text(maxBytes int64) Rule {
+	return Rule{MaxBytes: maxBytes, ContentTypes: []string{"text/plain"}}
+}
+
+// JSON is a Rule for a application/json body
+func JSON(maxBytes int64) Rule {
+	return Rule{MaxBytes: maxBytes, ContentTypes: []string{"application/json"}}
+}
+
+// Limiter check the request bodies with the Rules of the routes (by route name)
+type Limiter struct {
+	Rules map[string]Rule
+	// Default Rule for all routes without Rule
+	Default Rule
+}
+
+// NewLimiter create a Limiter with the Rules and a Default Rule with DefaultMaxBytes
+func NewLimiter(rules map[string]Rule) *Limiter {
+	return &Limiter{Rules: rules, Default: Rule{MaxBytes: DefaultMaxBytes}}
+}
+
+func (l *Limiter) rule(r *http.Request) Rule {
+	if route := mux.CurrentRoute(r); route != nil {
+		if rule, ok := l.Rules[route.GetName()]; ok {
+			return rule
+		}
+	}
+	return l.Default
+}
+
+// Middleware answer with 415 (Unsupported Media Type), if the Content-Type is not supported
+// and 413 (Request Entity Too Large), if the Content-Length is larger than the max size.
+// The body is limited to the max size (without Content-Length), see: ReadAll, DecodeJSON.
+func (l *Limiter) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rule := l.rule(r)
+
+		if ct := r.Header.Get("Content-Type"); ct != "" && len(rule.ContentTypes) > 0 && r.ContentLength != 0 {
+			mt, _, err := mime.ParseMediaType(ct)
+			if err != nil || !contains(rule.ContentTypes, mt) {
+				http.Error(w, fmt.Sprintf("unsupported Content-Type: %s (supported: %s)", ct, strings.Join(rule.ContentTypes, ", ")),
+					http.StatusUnsupportedMediaType)
+				return
+			}
+		}
+
+		if rule.MaxBytes > 0 {
+			if r.ContentLength > rule.MaxBytes {
+				http.Error(w, fmt.Sprintf("%v (max: %d bytes)", ErrTooLarge, rule.MaxBytes), http.StatusRequestEntityTooLarge)
+				return
+			}
+			r.Body = http.MaxBytesReader(w, r.Body, rule.MaxBytes)
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// ReadAll read the body and close it
+func ReadAll(r *http.Request) ([]byte, error) {
+	defer r.Body.Close()
+
+	b, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		return nil, readErr(err)
+	}
+	return b, nil
+}
+
+// DecodeJSON decode the body in v and close it: unknown fields and more than one JSON value are errors
+func DecodeJSON(r *http.Request, v interface{}) error {
+	defer r.Body.Close()
+
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(v); err != nil {
+		return readErr(err)
+	}
+
+	var extra json.RawMessage
+	if err := dec.Decode(&extra); err != io.EOF {
+		if err != nil {
+			return readErr(err)
+		}
+		return ErrMultipleValues
+	}
+	return nil
+}
+
+// Status is the http status code for the errors of ReadAll and DecodeJSON: 413 or 400
+func Status(err error) int {
+	if err == ErrTooLarge {
+		return http.StatusRequestEntityTooLarge
+	}
+	return http.StatusBadRequest
+}
+
+// readErr is ErrTooLarge, if the http.MaxBytesReader failed
+func readErr(err error) error {
+	if tooLarge(err) {
+		return ErrTooLarge
+	}
+	if err == io.EOF {
+		return errors.New("Request body is empty")
+	}
+	return fmt.Errorf("invalid request body: %v", err)
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+package body
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+type hero struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+func TestDecodeJSON(t *testing.T) {
+	for _, tc := range []struct {
+		body string
+		err  bool
+	}{
+		{` { "id": 1, "name": "Jasmin" } `, false},
+		{`{ "id": 1, "name": "Jasmin", "power": 9 }`, true},
+		{`{ "id": 1 } { "id": 2 }`, true},
+		{`{ "id": 1 } x`, true},
+		{`{ "id": 1 `, true},
+		{``, true},
+	} {
+		h := hero{}
+		r := httptest.NewRequest("PUT", "http://localhost:8080/api/heroes", strings.NewReader(tc.body))
+		err := DecodeJSON(r, &h)
+		if (err != nil) != tc.err {
+			t.Errorf("expect err: %v for: %v, got: %v", tc.err, tc.body, err)
+		}
+		if err != nil && Status(err) != http.StatusBadRequest {
+			t.Errorf("expect 400 for: %v, got: %v", tc.body, Status(err))
+		}
+	}
+}
+
+func TestReadAllTooLarge(t *testing.T) {
+	w := httptest.NewRecorder()
+	r := httptest.NewRequest("POST", "http://localhost:8080/api/heroes", strings.NewReader("Jasmin"))
+	r.Body = http.MaxBytesReader(w, r.Body, 3)
+
+	_, err := ReadAll(r)
+	if err != ErrTooLarge || Status(err) != http.StatusRequestEntityTooLarge {
+		t.Errorf("expect ErrTooLarge with 413, got: %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/heroes", func(w http.ResponseWriter, r *http.Request) {
+		if _, err := ReadAll(r); err != nil {
+			http.Error(w, err.Error(), Status(err))
+		}
+	}).Methods("POST").Name("add")
+	router.HandleFunc("/heroes", func(w http.ResponseWriter, r *http.Request) {
+		h := hero{}
+		if err := DecodeJSON(r, &h); err != nil {
+			http.Error(w, err.Error(), Status(err))
+		}
+	}).Methods("PUT").Name("update")
+	router.Use(NewLimiter(map[string]Rule{"add": Text(10), "update": JSON(100)}).Middleware)
+
+	for _, tc := range []struct {
+		method, contentType, body string
+		chunked                   bool
+		status                    int
+	}{
+		{"POST", "text/plain; charset=utf-8", "Jasmin", false, http.StatusOK},
+		{"POST", "", "Jasmin", false, http.StatusOK},
+		{"POST", "application/json", `"Jasmin"`, false, http.StatusUnsupportedMediaType},
+		{"POST", "text/plain", "Jasmin the Hero", false, http.StatusRequestEntityTooLarge},
+		{"POST", "text/plain", "Jasmin the Hero", true, http.StatusRequestEntityTooLarge},
+		{"PUT", "application/json", `{"id": 1, "name": "Jasmin"}`, false, http.StatusOK},
+		{"PUT", "text/xml", `<hero/>`, false, http.StatusUnsupportedMediaType},
+		{"PUT", "application/json", `{"id": 1, "power": 9}`, false, http.StatusBadRequest},
+	} {
+		r := httptest.NewRequest(tc.method, "http://localhost:8080/heroes", strings.NewReader(tc.body))
+		if tc.contentType != "" {
+			r.Header.Set("Content-Type", tc.contentType)
+		}
+		if tc.chunked {
+			// unknown length: the body is limited while reading
+			r.ContentLength = -1
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if w.Code != tc.status {
+			t.Errorf("%v != %v for: %v %v %v (%v)", tc.status, w.Code, tc.method, tc.contentType, tc.body, w.Body.String())
+		}
+	}
+}
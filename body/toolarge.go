@@ -0,0 +1,14 @@
+//go:build !appengine
+// +build !appengine
+
+package body
+
+import (
+	"errors"
+	"net/http"
+)
+
+// tooLarge is true, if the http.MaxBytesReader failed
+func tooLarge(err error) bool {
+	return errors.As(err, new(*http.MaxBytesError))
+}
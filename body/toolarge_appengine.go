@@ -0,0 +1,12 @@
+//go:build appengine
+// +build appengine
+
+package body
+
+import "strings"
+
+// tooLarge is true, if the http.MaxBytesReader failed
+// (the Go version of the App Engine runtime has no http.MaxBytesError)
+func tooLarge(err error) bool {
+	return strings.Contains(err.Error(), "request body too large")
+}
@@ -0,0 +1,373 @@
+// Package client is a typed Go client for the heroes API (v2),
+// the errors of the API are decoded into the service errors (e.g. service.ErrHeroNotFound).
+package client
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// the defaults of a new HeroesClient
+const (
+	DefaultRetries = 2
+	DefaultBackoff = 100 * time.Millisecond
+)
+
+// the service errors, which are recognized in the message of an error response
+var serviceErrors = []error{
+	service.ErrHeroNotFound,
+	service.ErrPosNotFound,
+	service.ErrNoContent,
+	service.ErrRevisionNotFound,
+	service.ErrTeamNotFound,
+	service.ErrHeroInTeam,
+}
+
+// Error is an error response of the API, which is not a service error
+type Error struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%d %s: %s", e.StatusCode, http.StatusText(e.StatusCode), e.Message)
+}
+
+// HeroesClient call the heroes API
+type HeroesClient struct {
+	// BaseURL of the server, e.g. https://heroes.example.com
+	BaseURL string
+	// HTTP is the used http.Client (default: http.DefaultClient)
+	HTTP *http.Client
+	// Header is added to every request, e.g. Authorization or X-Tenant
+	Header http.Header
+	// Retries of a failed request: network errors and 502, 503, 504 (only GET, PUT and DELETE)
+	// and 429 (all methods, waits the Retry-After)
+	Retries int
+	// Backoff before the first retry, it is doubled for every next retry
+	Backoff time.Duration
+}
+
+// New create a HeroesClient with the default retries
+func New(baseURL string) *HeroesClient {
+	return &HeroesClient{
+		BaseURL: strings.TrimSuffix(baseURL, "/"),
+		Header:  http.Header{},
+		Retries: DefaultRetries,
+		Backoff: DefaultBackoff,
+	}
+}
+
+// the Hero of the API v2
+type hero struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	ScoreData scoreData `json:"scoreData"`
+}
+
+type scoreData struct {
+	Name    string `json:"name"`
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+func newHero(h service.Hero) hero {
+	return hero{ID: h.ID, Name: h.Name, ScoreData: scoreData(h.ScoreData)}
+}
+
+func (h hero) Hero() *service.Hero {
+	return &service.Hero{ID: h.ID, Name: h.Name, ScoreData: service.ScoreData(h.ScoreData)}
+}
+
+// List the Heroes, filtered by name (empty: all Heroes)
+func (hc *HeroesClient) List(c context.Context, name string) ([]service.Hero, error) {
+	path := "/heroes"
+	if name != "" {
+		path += "?name=" + url.QueryEscape(name)
+	}
+	heroes := []hero{}
+	if err := hc.do(c, "GET", path, "", nil, &heroes); err != nil {
+		return nil, err
+	}
+
+	result := make([]service.Hero, len(heroes))
+	for i, h := range heroes {
+		result[i] = *h.Hero()
+	}
+	return result, nil
+}
+
+// Get the Hero by ID
+func (hc *HeroesClient) Get(c context.Context, id int64) (*service.Hero, error) {
+	return hc.hero(c, "GET", fmt.Sprintf("/heroes/%d", id), "", nil)
+}
+
+// Add a new Hero with the name
+func (hc *HeroesClient) Add(c context.Context, name string) (*service.Hero, error) {
+	return hc.hero(c, "POST", "/heroes", "text/plain", []byte(name))
+}
+
+// Update the Hero
+func (hc *HeroesClient) Update(c context.Context, h service.Hero) (*service.Hero, error) {
+	b, err := json.Marshal(newHero(h))
+	if err != nil {
+		return nil, err
+	}
+	return hc.hero(c, "PUT", "/heroes", "application/json", b)
+}
+
+// Move the Hero to the position pos
+func (hc *HeroesClient) Move(c context.Context, h service.Hero, pos int64) (*service.Hero, error) {
+	b, err := json.Marshal(newHero(h))
+	if err != nil {
+		return nil, err
+	}
+	return hc.hero(c, "PUT", fmt.Sprintf("/heroes?pos=%d", pos), "application/json", b)
+}
+
+// Delete the Hero by ID
+func (hc *HeroesClient) Delete(c context.Context, id int64) (*service.Hero, error) {
+	return hc.hero(c, "DELETE", fmt.Sprintf("/heroes/%d", id), "", nil)
+}
+
+// Scores of the Heroes (key: Hero ID)
+func (hc *HeroesClient) Scores(c context.Context) (map[int64]int, error) {
+	scores := map[int64]int{}
+	if err := hc.do(c, "GET", "/heroes/scores", "", nil, &scores); err != nil {
+		return nil, err
+	}
+	return scores, nil
+}
+
+// Protocols of the changes (the first page, the newest Protocols)
+func (hc *HeroesClient) Protocols(c context.Context) ([]service.Protocol, error) {
+	protocols := []service.Protocol{}
+	if err := hc.do(c, "GET", "/heroes/protocol", "", nil, &protocols); err != nil {
+		return nil, err
+	}
+	return protocols, nil
+}
+
+// Event is a change of the Heroes of the event stream
+type Event struct {
+	ID       int64
+	Protocol service.Protocol
+	// Hero is the state after the change (nil after Delete and Reset)
+	Hero *service.Hero
+	// Reset is true, if the events after the last ID are lost (the client must reload the Heroes)
+	Reset bool
+}
+
+// the Event of the API v2
+type event struct {
+	ID       int64            `json:"id"`
+	Protocol service.Protocol `json:"protocol"`
+	Hero     *hero            `json:"hero"`
+}
+
+// Events stream the changes of the Heroes after the event lastID (0: only the new changes) to fn,
+// until the context is done or fn returns an error. A closed stream is resumed with the Last-Event-ID.
+func (hc *HeroesClient) Events(c context.Context, lastID int64, fn func(Event) error) error {
+	backoff := hc.Backoff
+	for attempt := 0; ; attempt++ {
+		before := lastID
+		resume, err := hc.events(c, &lastID, fn)
+		if c.Err() != nil {
+			return nil
+		}
+		if !resume {
+			return err
+		}
+		// the stream was open (e.g. closed by the server), so the retries start again
+		if lastID != before || err == nil {
+			attempt, backoff = 0, hc.Backoff
+		}
+		if attempt >= hc.Retries && err != nil {
+			return err
+		}
+
+		select {
+		case <-c.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// events read one event stream, it returns true, if the stream can be resumed with the lastID
+func (hc *HeroesClient) events(c context.Context, lastID *int64, fn func(Event) error) (bool, error) {
+	httpClient := hc.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+
+	req, err := http.NewRequest("GET", hc.BaseURL+"/api/v2/heroes/events", nil)
+	if err != nil {
+		return false, err
+	}
+	req = req.WithContext(c)
+	for k, vs := range hc.Header {
+		req.Header[k] = vs
+	}
+	req.Header.Set("Accept", "text/event-stream")
+	if *lastID > 0 {
+		req.Header.Set("Last-Event-ID", strconv.FormatInt(*lastID, 10))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		_, retry := retryAfter(resp, "GET")
+		b, _ := ioutil.ReadAll(resp.Body)
+		return retry, ErrorFromResponse(resp.StatusCode, string(b))
+	}
+
+	// the fields of the Server-Sent Events, an empty line dispatch the event
+	name, data := "", ""
+	scanner := bufio.NewScanner(resp.Body)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case line == "":
+			var err error
+			if name == "reset" {
+				err = fn(Event{Reset: true})
+			} else if data != "" {
+				e := event{}
+				if err = json.Unmarshal([]byte(data), &e); err != nil {
+					return false, err
+				}
+				*lastID = e.ID
+				err = fn(newEvent(e))
+			}
+			if err != nil {
+				return false, err
+			}
+			name, data = "", ""
+		case strings.HasPrefix(line, "event:"):
+			name = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data += strings.TrimSpace(strings.TrimPrefix(line, "data:"))
+		}
+	}
+	return true, scanner.Err()
+}
+
+func newEvent(e event) Event {
+	result := Event{ID: e.ID, Protocol: e.Protocol}
+	if e.Hero != nil {
+		result.Hero = e.Hero.Hero()
+	}
+	return result
+}
+
+func (hc *HeroesClient) hero(c context.Context, method, path, contentType string, b []byte) (*service.Hero, error) {
+	h := hero{}
+	if err := hc.do(c, method, path, contentType, b, &h); err != nil {
+		return nil, err
+	}
+	return h.Hero(), nil
+}
+
+// do send the request (with retries) and decode the JSON response in v
+func (hc *HeroesClient) do(c context.Context, method, path, contentType string, b []byte, v interface{}) error {
+	httpClient := hc.HTTP
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	backoff := hc.Backoff
+
+	for attempt := 0; ; attempt++ {
+		req, err := http.NewRequest(method, hc.BaseURL+"/api/v2"+path, bytes.NewReader(b))
+		if err != nil {
+			return err
+		}
+		req = req.WithContext(c)
+		for k, vs := range hc.Header {
+			req.Header[k] = vs
+		}
+		req.Header.Set("Accept", "application/json")
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+
+		resp, err := httpClient.Do(req)
+		if err != nil {
+			if c.Err() != nil || attempt >= hc.Retries || !idempotent(method) {
+				return err
+			}
+		} else {
+			wait, retry := retryAfter(resp, method)
+			if !retry || attempt >= hc.Retries {
+				return decode(resp, v)
+			}
+			io.Copy(ioutil.Discard, resp.Body)
+			resp.Body.Close()
+			if wait > backoff {
+				backoff = wait
+			}
+		}
+
+		select {
+		case <-c.Done():
+			return c.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+}
+
+// retryAfter returns true, if the response is retryable and the duration of the Retry-After header
+func retryAfter(resp *http.Response, method string) (time.Duration, bool) {
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests:
+		// the request was rejected, before it was processed
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return time.Duration(secs) * time.Second, true
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return 0, idempotent(method)
+	}
+	return 0, false
+}
+
+func idempotent(method string) bool {
+	return method == "GET" || method == "PUT" || method == "DELETE"
+}
+
+// decode the response in v or in an error
+func decode(resp *http.Response, v interface{}) error {
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		b, _ := ioutil.ReadAll(resp.Body)
+		return ErrorFromResponse(resp.StatusCode, string(b))
+	}
+	return json.NewDecoder(resp.Body).Decode(v)
+}
+
+// ErrorFromResponse returns the service error of the message or an *Error
+func ErrorFromResponse(statusCode int, msg string) error {
+	msg = strings.TrimSpace(msg)
+	for _, err := range serviceErrors {
+		if msg == err.Error() {
+			return err
+		}
+	}
+	return &Error{StatusCode: statusCode, Message: msg}
+}
@@ -0,0 +1,151 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestErrorFromResponse(t *testing.T) {
+	if err := ErrorFromResponse(http.StatusBadRequest, "Hero not Found\n"); err != service.ErrHeroNotFound {
+		t.Errorf("expect ErrHeroNotFound, got: %v", err)
+	}
+
+	err := ErrorFromResponse(http.StatusForbidden, "forbidden\n")
+	e, ok := err.(*Error)
+	if !ok || e.StatusCode != http.StatusForbidden || e.Message != "forbidden" {
+		t.Errorf("expect *Error, got: %#v", err)
+	}
+	if err.Error() != "403 Forbidden: forbidden" {
+		t.Errorf("unexpected message: %v", err)
+	}
+}
+
+func TestRetries(t *testing.T) {
+	for _, tc := range []struct {
+		method   string
+		status   int
+		expected int
+	}{
+		{"GET", http.StatusServiceUnavailable, 3},
+		{"DELETE", http.StatusBadGateway, 3},
+		{"POST", http.StatusServiceUnavailable, 1},
+		{"POST", http.StatusTooManyRequests, 3},
+		{"GET", http.StatusInternalServerError, 1},
+		{"GET", http.StatusBadRequest, 1},
+	} {
+		calls := 0
+		ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls++
+			w.Header().Set("Retry-After", "0")
+			http.Error(w, "failed", tc.status)
+		}))
+
+		hc := New(ts.URL)
+		hc.Backoff = time.Millisecond
+		var err error
+		if tc.method == "POST" {
+			_, err = hc.Add(context.Background(), "Jasmin")
+		} else if tc.method == "DELETE" {
+			_, err = hc.Delete(context.Background(), 1)
+		} else {
+			_, err = hc.Get(context.Background(), 1)
+		}
+		ts.Close()
+
+		if e, ok := err.(*Error); !ok || e.StatusCode != tc.status {
+			t.Errorf("expect *Error with status: %v, got: %v", tc.status, err)
+		}
+		if calls != tc.expected {
+			t.Errorf("%v %v: expect %v calls, got: %v", tc.method, tc.status, tc.expected, calls)
+		}
+	}
+}
+
+func TestRetrySucceed(t *testing.T) {
+	calls := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if calls == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		if r.URL.Path != "/api/v2/heroes/1" || r.Header.Get("X-Tenant") != "a" {
+			t.Errorf("unexpected request: %v %v", r.URL, r.Header)
+		}
+		w.Write([]byte(`{"id": 1, "name": "Jasmin", "scoreData": {"name": "jasmin", "city": "Nuremberg", "country": "de"}}`))
+	}))
+	defer ts.Close()
+
+	hc := New(ts.URL)
+	hc.Backoff = time.Millisecond
+	hc.Header.Set("X-Tenant", "a")
+	h, err := hc.Get(context.Background(), 1)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+		return
+	}
+	if h.Name != "Jasmin" || h.ScoreData.City != "Nuremberg" || calls != 2 {
+		t.Errorf("unexpected Hero: %v after %v calls", h, calls)
+	}
+}
+
+func TestContextCancel(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer ts.Close()
+
+	hc := New(ts.URL)
+	hc.Backoff = time.Hour
+	c, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := hc.List(c, ""); err != context.DeadlineExceeded {
+		t.Errorf("expect DeadlineExceeded, got: %v", err)
+	}
+}
+
+func TestEvents(t *testing.T) {
+	lastIDs := []string{}
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		lastIDs = append(lastIDs, r.Header.Get("Last-Event-ID"))
+		w.Header().Set("Content-Type", "text/event-stream")
+		// the first stream is closed after the first event, the second is resumed
+		if len(lastIDs) == 1 {
+			fmt.Fprint(w, ": keep-alive\n\n")
+			fmt.Fprint(w, `id: 7`+"\n"+`data: {"id": 7, "protocol": {"action": "Add", "heroid": 8}, "hero": {"id": 8, "name": "Tail"}}`+"\n\n")
+			return
+		}
+		fmt.Fprint(w, `id: 8`+"\n"+`data: {"id": 8, "protocol": {"action": "Delete", "heroid": 8}}`+"\n\n")
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}))
+	defer ts.Close()
+
+	hc := New(ts.URL)
+	hc.Backoff = time.Millisecond
+	received := []Event{}
+	stop := errors.New("stop")
+	err := hc.Events(context.Background(), 0, func(e Event) error {
+		received = append(received, e)
+		if e.Reset {
+			return stop
+		}
+		return nil
+	})
+	if err != stop {
+		t.Errorf("expect the err of fn, got: %v", err)
+	}
+
+	if len(received) != 3 || received[0].Hero.Name != "Tail" || received[1].Protocol.Action != "Delete" || received[1].Hero != nil || !received[2].Reset {
+		t.Errorf("unexpected events: %v", received)
+	}
+	if len(lastIDs) != 2 || lastIDs[0] != "" || lastIDs[1] != "7" {
+		t.Errorf("expect the resume with the Last-Event-ID: 7, got: %v", lastIDs)
+	}
+}
@@ -0,0 +1,88 @@
+package main
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/client"
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/events"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestClient(t *testing.T) {
+	resetTestHeroes(t)
+
+	c := context.Background()
+	hc := client.New(server.URL)
+
+	h, err := hc.Add(c, "Client")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	defer hc.Delete(c, h.ID)
+
+	h.ScoreData = service.ScoreData{Name: "client", City: "Berlin", Country: "de"}
+	if _, err = hc.Update(c, *h); err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+
+	got, err := hc.Get(c, h.ID)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if got.Name != "Client" || got.ScoreData != h.ScoreData {
+		t.Errorf("%v != %v", h, got)
+	}
+
+	heroes, err := hc.List(c, "Client")
+	if err != nil || len(heroes) != 1 || heroes[0].ID != h.ID {
+		t.Errorf("expect the Hero: %v, got: %v (%v)", h, heroes, err)
+	}
+
+	if _, err = hc.Move(c, *h, 0); err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if _, err = hc.Move(c, *h, 9999); err != service.ErrPosNotFound {
+		t.Errorf("expect ErrPosNotFound, got: %v", err)
+	}
+
+	if _, err = hc.Get(c, 9999); err != service.ErrHeroNotFound {
+		t.Errorf("expect ErrHeroNotFound, got: %v", err)
+	}
+
+	protocols, err := hc.Protocols(c)
+	if err != nil || len(protocols) == 0 {
+		t.Errorf("expect Protocols, got: %v (%v)", protocols, err)
+	}
+}
+
+func TestClientEvents(t *testing.T) {
+	svc := app.ProtocolHeroService
+	app.ProtocolHeroService = events.NewHeroService(db.NewMemService(), app.events)
+	defer func() { app.ProtocolHeroService = svc }()
+	// the reset is published, so the lastID is not 0 (only the new events)
+	resetTestHeroes(t)
+
+	c, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	hc := client.New(server.URL)
+
+	// the events after lastID are replayed, so the Add is not lost before the stream is open
+	lastID := app.events.LastID()
+	h, err := hc.Add(c, "Events")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+
+	var received client.Event
+	err = hc.Events(c, lastID, func(e client.Event) error {
+		received = e
+		cancel()
+		return nil
+	})
+	if err != nil || received.Protocol.Action != "Add" || received.Hero == nil || received.Hero.ID != h.ID {
+		t.Errorf("expect the Add of the Hero: %v, got: %v (%v)", h.ID, received, err)
+	}
+}
@@ -0,0 +1,94 @@
+package main
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// EnvConfig is the Env-Variable with the path to the config file (default: ~/.heroesctl.yaml)
+	EnvConfig = "HEROESCTL_CONFIG"
+	// EnvProfile is the Env-Variable with the name of the used profile
+	EnvProfile = "HEROESCTL_PROFILE"
+)
+
+// Profile is the configuration of one environment
+type Profile struct {
+	// URL of the heroes service, e.g. https://heroes.example.com
+	URL string `yaml:"url"`
+	// Local is the path to a JSON or YAML fixture file, which is used instead of the REST API
+	Local string `yaml:"local"`
+	// APIKey for the header: Authorization: ApiKey key
+	APIKey string `yaml:"apiKey"`
+	// Token for the header: Authorization: Bearer token
+	Token string `yaml:"token"`
+	// Tenant for the header: X-Tenant
+	Tenant string `yaml:"tenant"`
+	// Output format: table (default) or json
+	Output string `yaml:"output"`
+}
+
+// Config contains the Profiles, e.g.:
+//
+//	default: dev
+//	profiles:
+//	  dev:
+//	    url: http://localhost:8080
+//	  prod:
+//	    url: https://heroes.example.com
+//	    apiKey: secret
+type Config struct {
+	Default  string             `yaml:"default"`
+	Profiles map[string]Profile `yaml:"profiles"`
+}
+
+// defaultProfile is used without config file
+var defaultProfile = Profile{URL: "http://localhost:8080"}
+
+func configFile() string {
+	if file := os.Getenv(EnvConfig); file != "" {
+		return file
+	}
+	home := os.Getenv("HOME")
+	if home == "" {
+		return ""
+	}
+	return filepath.Join(home, ".heroesctl.yaml")
+}
+
+// LoadConfig read the config file, a missing file is an empty Config
+func LoadConfig(file string) (Config, error) {
+	cfg := Config{}
+	if file == "" {
+		return cfg, nil
+	}
+	b, err := ioutil.ReadFile(file)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	} else if err != nil {
+		return cfg, err
+	}
+	if err := yaml.UnmarshalStrict(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("can not parse config file: %s: %v", file, err)
+	}
+	return cfg, nil
+}
+
+// Profile returns the Profile by name (empty: the default profile)
+func (cfg Config) Profile(name string) (Profile, error) {
+	if name == "" {
+		name = cfg.Default
+	}
+	if name == "" {
+		return defaultProfile, nil
+	}
+	p, ok := cfg.Profiles[name]
+	if !ok {
+		return p, fmt.Errorf("unknown profile: %s", name)
+	}
+	return p, nil
+}
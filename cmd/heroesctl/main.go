@@ -0,0 +1,319 @@
+// heroesctl is the command-line admin tool for the heroes service,
+// it talks to the REST API or directly to a local fixture file.
+//
+//	heroesctl [-profile name] [-url url | -local file] [-o table|json] command [args]
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"sort"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/lima1909/goheroes-appengine/client"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+const usage = `usage: heroesctl [flags] command [args]
+
+commands:
+  list [name]          list the Heroes (filtered by name)
+  get id               get the Hero by ID
+  add name             add a new Hero
+  update id name       rename the Hero
+  move id pos          move the Hero to the position pos
+  delete id            delete the Hero
+  scores               the Scores of the Heroes
+  protocols [-f]       the Protocols (-f: follow the new Protocols)
+  export file          write the Heroes in a JSON or YAML roster file
+  import file          add or update the Heroes of a JSON or YAML roster file
+
+flags:
+`
+
+func main() {
+	if err := run(context.Background(), os.Args[1:], os.Stdout); err != nil {
+		fmt.Fprintln(os.Stderr, "heroesctl:", err)
+		os.Exit(1)
+	}
+}
+
+func run(c context.Context, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet("heroesctl", flag.ContinueOnError)
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
+	}
+	config := flags.String("config", configFile(), "path to the config file with the profiles (Env: "+EnvConfig+")")
+	profile := flags.String("profile", os.Getenv(EnvProfile), "name of the profile (Env: "+EnvProfile+")")
+	url := flags.String("url", "", "URL of the heroes service (overwrite the profile)")
+	local := flags.String("local", "", "JSON or YAML fixture file, which is used instead of the REST API")
+	output := flags.String("o", "", "output format: table or json")
+	verbose := flags.Bool("v", false, "show the log messages")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if !*verbose {
+		log.SetOutput(ioutil.Discard)
+	}
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return fmt.Errorf("missing command")
+	}
+
+	cfg, err := LoadConfig(*config)
+	if err != nil {
+		return err
+	}
+	p, err := cfg.Profile(*profile)
+	if err != nil {
+		return err
+	}
+	if *url != "" {
+		p.URL, p.Local = *url, ""
+	}
+	if *local != "" {
+		p.Local = *local
+	}
+	if *output != "" {
+		p.Output = *output
+	}
+	if p.Output == "" {
+		p.Output = "table"
+	}
+	if p.Output != "table" && p.Output != "json" {
+		return fmt.Errorf("invalid output format: %s (only table or json)", p.Output)
+	}
+
+	store, err := NewStore(p)
+	if err != nil {
+		return err
+	}
+	cmd := command{store: store, out: out, format: p.Output}
+	return cmd.run(c, flags.Arg(0), flags.Args()[1:])
+}
+
+type command struct {
+	store  Store
+	out    io.Writer
+	format string
+}
+
+func (cmd command) run(c context.Context, name string, args []string) error {
+	switch name {
+	case "list":
+		heroes, err := cmd.store.List(c, arg(args, 0))
+		if err != nil {
+			return err
+		}
+		return cmd.heroes(heroes...)
+	case "get", "delete":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: %s id", name)
+		}
+		id, err := strconv.ParseInt(args[0], 10, 64)
+		if err != nil {
+			return fmt.Errorf("invalid id: %s", args[0])
+		}
+		get := cmd.store.Get
+		if name == "delete" {
+			get = cmd.store.Delete
+		}
+		h, err := get(c, id)
+		if err != nil {
+			return err
+		}
+		return cmd.heroes(*h)
+	case "add":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: add name")
+		}
+		h, err := cmd.store.Add(c, args[0])
+		if err != nil {
+			return err
+		}
+		return cmd.heroes(*h)
+	case "update", "move":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: update id name | move id pos")
+		}
+		return cmd.update(c, name, args[0], args[1])
+	case "scores":
+		return cmd.scores(c)
+	case "protocols":
+		return cmd.protocols(c, arg(args, 0) == "-f")
+	case "export":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: export file")
+		}
+		heroes, err := cmd.store.List(c, "")
+		if err != nil {
+			return err
+		}
+		if err := WriteRoster(args[0], heroes); err != nil {
+			return err
+		}
+		fmt.Fprintf(cmd.out, "%d Heroes exported to: %s\n", len(heroes), args[0])
+		return nil
+	case "import":
+		if len(args) != 1 {
+			return fmt.Errorf("usage: import file")
+		}
+		return cmd.importRoster(c, args[0])
+	}
+	return fmt.Errorf("unknown command: %s", name)
+}
+
+func arg(args []string, i int) string {
+	if i < len(args) {
+		return args[i]
+	}
+	return ""
+}
+
+func (cmd command) update(c context.Context, name, varID, value string) error {
+	id, err := strconv.ParseInt(varID, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid id: %s", varID)
+	}
+	h, err := cmd.store.Get(c, id)
+	if err != nil {
+		return err
+	}
+
+	if name == "update" {
+		h.Name = value
+		h, err = cmd.store.Update(c, *h)
+	} else {
+		pos, perr := strconv.ParseInt(value, 10, 64)
+		if perr != nil {
+			return fmt.Errorf("invalid pos: %s", value)
+		}
+		h, err = cmd.store.Move(c, *h, pos)
+	}
+	if err != nil {
+		return err
+	}
+	return cmd.heroes(*h)
+}
+
+// importRoster update the Heroes with an existing ID, all other Heroes are added (with a new ID)
+func (cmd command) importRoster(c context.Context, file string) error {
+	heroes, err := ReadRoster(file)
+	if err != nil {
+		return err
+	}
+
+	added, updated := 0, 0
+	for _, h := range heroes {
+		_, err := cmd.store.Get(c, h.ID)
+		if err == service.ErrHeroNotFound {
+			n, err := cmd.store.Add(c, h.Name)
+			if err != nil {
+				return err
+			}
+			h.ID = n.ID
+			added++
+		} else if err != nil {
+			return err
+		} else {
+			updated++
+		}
+		if _, err := cmd.store.Update(c, h); err != nil {
+			return err
+		}
+	}
+	fmt.Fprintf(cmd.out, "%d Heroes added, %d Heroes updated from: %s\n", added, updated, file)
+	return nil
+}
+
+func (cmd command) scores(c context.Context) error {
+	scores, err := cmd.store.Scores(c)
+	if err != nil {
+		return err
+	}
+	if cmd.format == "json" {
+		return cmd.json(scores)
+	}
+
+	ids := make([]int, 0, len(scores))
+	for id := range scores {
+		ids = append(ids, int(id))
+	}
+	sort.Ints(ids)
+	w := tabwriter.NewWriter(cmd.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tSCORE")
+	for _, id := range ids {
+		fmt.Fprintf(w, "%d\t%d\n", id, scores[int64(id)])
+	}
+	return w.Flush()
+}
+
+// protocols print the Protocols (sorted by time), with follow, the new Protocols of the event stream
+// (only the REST API), until the context is done
+func (cmd command) protocols(c context.Context, follow bool) error {
+	protocols, err := cmd.store.Protocols(c)
+	if err != nil {
+		return err
+	}
+	sort.Slice(protocols, func(i, j int) bool { return protocols[i].Time.Before(protocols[j].Time) })
+	if err := cmd.printProtocols(protocols); err != nil {
+		return err
+	}
+	if !follow {
+		return nil
+	}
+
+	hc, ok := cmd.store.(*client.HeroesClient)
+	if !ok {
+		return fmt.Errorf("protocols -f needs the REST API (no local file)")
+	}
+	return hc.Events(c, 0, func(e client.Event) error {
+		// the events after the last event are no longer in the replay buffer (e.g. after a restart)
+		if e.Reset {
+			return fmt.Errorf("the event stream is reset, Protocols may be lost")
+		}
+		return cmd.printProtocols([]service.Protocol{e.Protocol})
+	})
+}
+
+func (cmd command) printProtocols(protocols []service.Protocol) error {
+	if cmd.format == "json" {
+		return cmd.json(protocols)
+	}
+	w := tabwriter.NewWriter(cmd.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tACTION\tHERO\tNOTE")
+	for _, p := range protocols {
+		fmt.Fprintf(w, "%s\t%s\t%d\t%s\n", p.GetTimeString(), p.Action, p.HeroID, p.Note)
+	}
+	return w.Flush()
+}
+
+func (cmd command) heroes(heroes ...service.Hero) error {
+	if cmd.format == "json" {
+		return cmd.json(heroes)
+	}
+	w := tabwriter.NewWriter(cmd.out, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(w, "ID\tNAME\tSCORE NAME\tCITY\tCOUNTRY")
+	for _, h := range heroes {
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\t%s\n", h.ID, h.Name, h.ScoreData.Name, h.ScoreData.City, h.ScoreData.Country)
+	}
+	return w.Flush()
+}
+
+// json print the value, the Heroes with the ScoreData (like the API v2)
+func (cmd command) json(v interface{}) error {
+	if heroes, ok := v.([]service.Hero); ok {
+		v = toFixtures(heroes)
+	}
+	enc := json.NewEncoder(cmd.out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,139 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/db"
+)
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "heroesctl")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	return dir
+}
+
+func TestConfigProfile(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	file := filepath.Join(dir, "config.yaml")
+	ioutil.WriteFile(file, []byte(`
+default: dev
+profiles:
+  dev:
+    url: http://localhost:8080
+  prod:
+    url: https://heroes.example.com
+    apiKey: secret
+    output: json
+`), 0644)
+
+	cfg, err := LoadConfig(file)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if p, _ := cfg.Profile(""); p.URL != "http://localhost:8080" {
+		t.Errorf("expect the default profile dev, got: %v", p)
+	}
+	if p, _ := cfg.Profile("prod"); p.APIKey != "secret" || p.Output != "json" {
+		t.Errorf("unexpected profile prod: %v", p)
+	}
+	if _, err := cfg.Profile("test"); err == nil {
+		t.Errorf("expect err for an unknown profile")
+	}
+
+	if cfg, err := LoadConfig(filepath.Join(dir, "missing.yaml")); err != nil || len(cfg.Profiles) != 0 {
+		t.Errorf("expect an empty Config for a missing file: %v, %v", cfg, err)
+	}
+
+	ioutil.WriteFile(file, []byte("profiles:\n  dev:\n    uri: http://localhost\n"), 0644)
+	if _, err := LoadConfig(file); err == nil {
+		t.Errorf("expect err for an unknown field")
+	}
+}
+
+func heroesctl(t *testing.T, args ...string) string {
+	out := &bytes.Buffer{}
+	if err := run(context.Background(), append([]string{"-config", ""}, args...), out); err != nil {
+		t.Fatalf("%v: No err expected: %v", args, err)
+	}
+	return out.String()
+}
+
+func TestLocalStore(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	local := filepath.Join(dir, "heroes.yaml")
+	if err := WriteRoster(local, db.DefaultHeroes()); err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+
+	out := heroesctl(t, "-local", local, "get", "1")
+	if !strings.Contains(out, "Jasmin") || !strings.Contains(out, "Nuremberg") {
+		t.Errorf("expect the table with Jasmin, got: %v", out)
+	}
+
+	heroesctl(t, "-local", local, "add", "Alex H")
+	heroesctl(t, "-local", local, "update", "1", "Jasmin R")
+	heroesctl(t, "-local", local, "move", "8", "0")
+	heroesctl(t, "-local", local, "delete", "2")
+
+	// the changes are saved in the local file
+	heroes, err := db.LoadFixtures(local)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if len(heroes) != 7 || heroes[0].Name != "Alex H" || heroes[1].Name != "Jasmin R" || heroes[1].ScoreData.City != "Nuremberg" {
+		t.Errorf("unexpected Heroes: %v", heroes)
+	}
+
+	fixtures := []db.Fixture{}
+	if err := json.Unmarshal([]byte(heroesctl(t, "-local", local, "-o", "json", "list", "Jasmin")), &fixtures); err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if len(fixtures) != 1 || fixtures[0].ScoreData.Name != "jasmin-roeper" {
+		t.Errorf("unexpected JSON: %v", fixtures)
+	}
+
+	if out := heroesctl(t, "-local", local, "protocols"); !strings.Contains(out, "ACTION") {
+		t.Errorf("expect the Protocols, got: %v", out)
+	}
+
+	err = run(context.Background(), []string{"-config", "", "-local", local, "get", "99"}, &bytes.Buffer{})
+	if err == nil || err.Error() != "Hero not Found" {
+		t.Errorf("expect ErrHeroNotFound, got: %v", err)
+	}
+}
+
+func TestExportImport(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	local := filepath.Join(dir, "heroes.json")
+	WriteRoster(local, db.DefaultHeroes()[:2])
+	roster := filepath.Join(dir, "roster.yaml")
+
+	heroesctl(t, "-local", local, "export", roster)
+	heroesctl(t, "-local", local, "update", "1", "Jasmin R")
+	heroesctl(t, "-local", local, "delete", "2")
+
+	out := heroesctl(t, "-local", local, "import", roster)
+	if out != "1 Heroes added, 1 Heroes updated from: "+roster+"\n" {
+		t.Errorf("unexpected output: %v", out)
+	}
+
+	heroes, _ := db.LoadFixtures(local)
+	if len(heroes) != 2 || heroes[0].Name != "Jasmin" || heroes[1].Name != "Mario" || heroes[1].ScoreData.Name != "mario-linke" {
+		t.Errorf("unexpected Heroes: %v", heroes)
+	}
+}
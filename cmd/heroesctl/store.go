@@ -0,0 +1,136 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strings"
+
+	"github.com/lima1909/goheroes-appengine/client"
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/score"
+	"github.com/lima1909/goheroes-appengine/service"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// Store is the REST API (client.HeroesClient) or a local fixture file (localStore)
+type Store interface {
+	List(c context.Context, name string) ([]service.Hero, error)
+	Get(c context.Context, id int64) (*service.Hero, error)
+	Add(c context.Context, name string) (*service.Hero, error)
+	Update(c context.Context, h service.Hero) (*service.Hero, error)
+	Move(c context.Context, h service.Hero, pos int64) (*service.Hero, error)
+	Delete(c context.Context, id int64) (*service.Hero, error)
+	Scores(c context.Context) (map[int64]int, error)
+	Protocols(c context.Context) ([]service.Protocol, error)
+}
+
+// NewStore create the Store of the Profile
+func NewStore(p Profile) (Store, error) {
+	if p.Local != "" {
+		return openLocal(p.Local)
+	}
+	if p.URL == "" {
+		return nil, fmt.Errorf("the profile needs an url or a local file")
+	}
+
+	hc := client.New(p.URL)
+	if p.APIKey != "" {
+		hc.Header.Set("Authorization", "ApiKey "+p.APIKey)
+	} else if p.Token != "" {
+		hc.Header.Set("Authorization", "Bearer "+p.Token)
+	}
+	if p.Tenant != "" {
+		hc.Header.Set("X-Tenant", p.Tenant)
+	}
+	return hc, nil
+}
+
+// localStore is a MemService, which is loaded from and saved in a fixture file
+type localStore struct {
+	*db.MemService
+	file string
+}
+
+func openLocal(file string) (*localStore, error) {
+	heroes, err := db.LoadFixtures(file)
+	if err != nil {
+		return nil, err
+	}
+	return &localStore{MemService: db.NewMemServiceWithHeroes(heroes), file: file}, nil
+}
+
+func (l *localStore) Get(c context.Context, id int64) (*service.Hero, error) {
+	return l.GetByID(c, id)
+}
+
+func (l *localStore) Add(c context.Context, name string) (*service.Hero, error) {
+	return l.save(l.MemService.Add(c, name))
+}
+
+func (l *localStore) Update(c context.Context, h service.Hero) (*service.Hero, error) {
+	return l.save(l.MemService.Update(c, h))
+}
+
+func (l *localStore) Move(c context.Context, h service.Hero, pos int64) (*service.Hero, error) {
+	return l.save(l.UpdatePosition(c, h, pos))
+}
+
+func (l *localStore) Delete(c context.Context, id int64) (*service.Hero, error) {
+	return l.save(l.MemService.Delete(c, id))
+}
+
+func (l *localStore) Scores(c context.Context) (map[int64]int, error) {
+	return score.Default().Scores(c, l.MemService)
+}
+
+// save the Heroes in the fixture file, after a successful change
+func (l *localStore) save(h *service.Hero, err error) (*service.Hero, error) {
+	if err != nil {
+		return nil, err
+	}
+	heroes, err := l.List(context.Background(), "")
+	if err != nil {
+		return nil, err
+	}
+	return h, WriteRoster(l.file, heroes)
+}
+
+// ReadRoster read the Heroes from a JSON or YAML file (the format of the fixture files)
+func ReadRoster(file string) ([]service.Hero, error) {
+	return db.LoadFixtures(file)
+}
+
+// toFixtures convert the Heroes to Fixtures (with the ScoreData)
+func toFixtures(heroes []service.Hero) []db.Fixture {
+	fixtures := make([]db.Fixture, len(heroes))
+	for i, h := range heroes {
+		fixtures[i].ID = h.ID
+		fixtures[i].Name = h.Name
+		fixtures[i].ScoreData.Name = h.ScoreData.Name
+		fixtures[i].ScoreData.City = h.ScoreData.City
+		fixtures[i].ScoreData.Country = h.ScoreData.Country
+	}
+	return fixtures
+}
+
+// WriteRoster write the Heroes in a JSON (.json) or YAML (.yaml, .yml) file
+func WriteRoster(file string, heroes []service.Hero) error {
+	fixtures := toFixtures(heroes)
+	var b []byte
+	var err error
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		b, err = json.MarshalIndent(fixtures, "", "\t")
+	case ".yaml", ".yml":
+		b, err = yaml.Marshal(fixtures)
+	default:
+		return fmt.Errorf("unsupported roster file: %s (only .json, .yaml or .yml)", file)
+	}
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(file, b, 0644)
+}
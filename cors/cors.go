@@ -0,0 +1,240 @@
+// Package cors handle the Cross-Origin Resource Sharing (CORS) of the requests:
+// the preflight (OPTIONS) requests and the CORS headers of the simple (actual) requests.
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// EnvOrigins is the Env-Variable with the allowed origins: https://a.com,https://*.b.com (default: *)
+	EnvOrigins = "HEROES_CORS_ORIGINS"
+	// EnvCredentials is the Env-Variable, if it is true, credentials (cookies, Authorization) are allowed
+	EnvCredentials = "HEROES_CORS_CREDENTIALS"
+	// EnvMaxAge is the Env-Variable with the duration, how long the preflight response can be cached (e.g. 10m)
+	EnvMaxAge = "HEROES_CORS_MAX_AGE"
+	// EnvExpose is the Env-Variable with the headers, which the client can read (default: ETag, Link)
+	EnvExpose = "HEROES_CORS_EXPOSE"
+)
+
+// Options of the CORS policy
+type Options struct {
+	// AllowedOrigins: * (all), the origin (https://a.com) or a wildcard subdomain (https://*.a.com)
+	AllowedOrigins   []string
+	AllowedMethods   []string
+	AllowedHeaders   []string
+	ExposedHeaders   []string
+	AllowCredentials bool
+	// MaxAge of the preflight response in the client cache (0: no Access-Control-Max-Age header)
+	MaxAge time.Duration
+}
+
+// Default Options: all origins, the methods of the API, the headers Content-Type and Authorization
+func Default() *Options {
+	return &Options{
+		AllowedOrigins: []string{"*"},
+		AllowedMethods: []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowedHeaders: []string{"Content-Type", "Authorization"},
+		ExposedHeaders: []string{"ETag", "Link"},
+	}
+}
+
+// FromEnv create the Default Options, changed by the Env-Variables
+func FromEnv() (*Options, error) {
+	o := Default()
+
+	if origins := os.Getenv(EnvOrigins); origins != "" {
+		o.AllowedOrigins = split(origins)
+	}
+	if expose := os.Getenv(EnvExpose); expose != "" {
+		o.ExposedHeaders = split(expose)
+	}
+	if cred := os.Getenv(EnvCredentials); cred != "" {
+		b, err := strconv.ParseBool(cred)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", EnvCredentials, cred)
+		}
+		o.AllowCredentials = b
+	}
+	if maxAge := os.Getenv(EnvMaxAge); maxAge != "" {
+		d, err := time.ParseDuration(maxAge)
+		if err != nil || d < 0 {
+			return nil, fmt.Errorf("invalid %s: %s", EnvMaxAge, maxAge)
+		}
+		o.MaxAge = d
+	}
+	if o.AllowCredentials && contains(o.AllowedOrigins, "*") {
+		return nil, fmt.Errorf("%s needs the allowed origins in %s (not *)", EnvCredentials, EnvOrigins)
+	}
+
+	return o, nil
+}
+
+// AllowOrigin is true, if the origin match one of the AllowedOrigins,
+// with credentials * match no origin (the credentials are only for the listed origins)
+func (o *Options) AllowOrigin(origin string) bool {
+	origin = strings.ToLower(origin)
+	for _, pattern := range o.AllowedOrigins {
+		if pattern == "*" && o.AllowCredentials {
+			continue
+		}
+		if matchOrigin(strings.ToLower(pattern), origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// https://*.a.com match https://b.a.com and https://c.b.a.com, but not https://a.com
+func matchOrigin(pattern, origin string) bool {
+	if pattern == "*" || pattern == origin {
+		return true
+	}
+	i := strings.Index(pattern, "*.")
+	if i == -1 {
+		return false
+	}
+	prefix, suffix := pattern[:i], pattern[i+1:]
+	return len(origin) > len(prefix)+len(suffix) &&
+		strings.HasPrefix(origin, prefix) && strings.HasSuffix(origin, suffix) &&
+		!strings.ContainsAny(origin[len(prefix):len(origin)-len(suffix)], "/:")
+}
+
+func (o *Options) allowMethod(method string) bool {
+	return contains(o.AllowedMethods, method)
+}
+
+func (o *Options) allowHeaders(headers []string) bool {
+	for _, h := range headers {
+		if !contains(o.AllowedHeaders, h) {
+			return false
+		}
+	}
+	return true
+}
+
+// allowOriginValue is * only for all origins without credentials, else the origin of the request
+func (o *Options) allowOriginValue(origin string) string {
+	if !o.AllowCredentials && len(o.AllowedOrigins) == 1 && o.AllowedOrigins[0] == "*" {
+		return "*"
+	}
+	return origin
+}
+
+// Handler handle the preflight requests and set the CORS headers of the simple requests.
+// A preflight request is answered with 204 (No Content), if the origin, the method and the headers
+// are allowed and match is true for the request with the requested method (e.g. an existing route),
+// else with 403 (Forbidden) or 404 (Not Found). Other OPTIONS requests are answered with 200 (OK)
+// and the Allow header with the matching methods.
+func Handler(h http.Handler, o *Options, match func(r *http.Request) bool) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		origin := r.Header.Get("Origin")
+		reqMethod := r.Header.Get("Access-Control-Request-Method")
+
+		// the response depends on the origin, if not all origins get: *
+		all := o.allowOriginValue("") == "*"
+		if !all {
+			w.Header().Add("Vary", "Origin")
+		}
+
+		if r.Method != "OPTIONS" || reqMethod == "" || origin == "" {
+			if all || (origin != "" && o.AllowOrigin(origin)) {
+				w.Header().Set("Access-Control-Allow-Origin", o.allowOriginValue(origin))
+				if o.AllowCredentials {
+					w.Header().Set("Access-Control-Allow-Credentials", "true")
+				}
+				if len(o.ExposedHeaders) > 0 {
+					w.Header().Set("Access-Control-Expose-Headers", strings.Join(o.ExposedHeaders, ", "))
+				}
+			}
+			if r.Method == "OPTIONS" {
+				options(w, r, o, match)
+				return
+			}
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		w.Header().Add("Vary", "Access-Control-Request-Method")
+		w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+		reqHeaders := split(r.Header.Get("Access-Control-Request-Headers"))
+		switch {
+		case !o.AllowOrigin(origin):
+			http.Error(w, "origin is not allowed: "+origin, http.StatusForbidden)
+			return
+		case !o.allowMethod(reqMethod):
+			http.Error(w, "method is not allowed: "+reqMethod, http.StatusForbidden)
+			return
+		case !o.allowHeaders(reqHeaders):
+			http.Error(w, "headers are not allowed: "+strings.Join(reqHeaders, ", "), http.StatusForbidden)
+			return
+		case !matchMethod(r, reqMethod, match):
+			http.NotFound(w, r)
+			return
+		}
+
+		w.Header().Set("Access-Control-Allow-Origin", o.allowOriginValue(origin))
+		w.Header().Set("Access-Control-Allow-Methods", strings.Join(o.AllowedMethods, ", "))
+		if len(o.AllowedHeaders) > 0 {
+			w.Header().Set("Access-Control-Allow-Headers", strings.Join(o.AllowedHeaders, ", "))
+		}
+		if o.AllowCredentials {
+			w.Header().Set("Access-Control-Allow-Credentials", "true")
+		}
+		if o.MaxAge > 0 {
+			w.Header().Set("Access-Control-Max-Age", strconv.Itoa(int(o.MaxAge.Seconds())))
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+}
+
+// options answer a OPTIONS request (without preflight) with the allowed methods of the URL
+func options(w http.ResponseWriter, r *http.Request, o *Options, match func(r *http.Request) bool) {
+	allow := []string{}
+	for _, m := range o.AllowedMethods {
+		if m != "OPTIONS" && matchMethod(r, m, match) {
+			allow = append(allow, m)
+		}
+	}
+	if len(allow) == 0 {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Allow", strings.Join(append(allow, "OPTIONS"), ", "))
+	w.WriteHeader(http.StatusOK)
+}
+
+// matchMethod call match with a copy of the request with the method (nil match: true)
+func matchMethod(r *http.Request, method string, match func(r *http.Request) bool) bool {
+	if match == nil {
+		return true
+	}
+	mr := r.WithContext(r.Context())
+	mr.Method = method
+	return match(mr)
+}
+
+func split(s string) []string {
+	values := []string{}
+	for _, v := range strings.Split(s, ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+	return values
+}
+
+func contains(values []string, v string) bool {
+	for _, s := range values {
+		if strings.EqualFold(s, v) {
+			return true
+		}
+	}
+	return false
+}
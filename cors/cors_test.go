@@ -0,0 +1,196 @@
+package cors
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAllowOrigin(t *testing.T) {
+	o := &Options{AllowedOrigins: []string{"https://heroes.com", "https://*.example.com"}}
+
+	for _, tc := range []struct {
+		origin string
+		allow  bool
+	}{
+		{"https://heroes.com", true},
+		{"https://HEROES.com", true},
+		{"http://heroes.com", false},
+		{"https://app.example.com", true},
+		{"https://a.b.example.com", true},
+		{"https://example.com", false},
+		{"https://.example.com", false},
+		{"https://evil.com/.example.com", false},
+		{"https://evilexample.com", false},
+		{"https://app.example.com:8080", false},
+	} {
+		if o.AllowOrigin(tc.origin) != tc.allow {
+			t.Errorf("expect %v for origin: %v", tc.allow, tc.origin)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	defer func() {
+		os.Unsetenv(EnvOrigins)
+		os.Unsetenv(EnvCredentials)
+		os.Unsetenv(EnvMaxAge)
+	}()
+
+	os.Setenv(EnvOrigins, "https://a.com, https://*.b.com")
+	os.Setenv(EnvCredentials, "true")
+	os.Setenv(EnvMaxAge, "10m")
+	o, err := FromEnv()
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if len(o.AllowedOrigins) != 2 || !o.AllowCredentials || o.MaxAge != 10*time.Minute {
+		t.Errorf("unexpected options: %v", o)
+	}
+
+	os.Setenv(EnvOrigins, "*")
+	if _, err = FromEnv(); err == nil {
+		t.Errorf("err expected for the credentials with all origins")
+	}
+	os.Setenv(EnvOrigins, "https://a.com")
+
+	os.Setenv(EnvMaxAge, "ten")
+	if _, err = FromEnv(); err == nil {
+		t.Errorf("err expected for invalid max age")
+	}
+}
+
+func newHandler(o *Options) http.Handler {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})
+	// only the route: GET, PUT /heroes exists
+	match := func(r *http.Request) bool {
+		return r.URL.Path == "/heroes" && (r.Method == "GET" || r.Method == "PUT")
+	}
+	return Handler(ok, o, match)
+}
+
+func request(method, url string, header map[string]string) *http.Request {
+	r := httptest.NewRequest(method, "http://localhost:8080"+url, nil)
+	for k, v := range header {
+		r.Header.Set(k, v)
+	}
+	return r
+}
+
+func TestPreflight(t *testing.T) {
+	o := Default()
+	o.AllowedOrigins = []string{"https://*.example.com"}
+	o.AllowCredentials = true
+	o.MaxAge = time.Hour
+	h := newHandler(o)
+
+	for _, tc := range []struct {
+		url, origin, method, headers string
+		status                       int
+	}{
+		{"/heroes", "https://app.example.com", "PUT", "content-type, authorization", http.StatusNoContent},
+		{"/heroes", "https://evil.com", "PUT", "", http.StatusForbidden},
+		{"/heroes", "https://app.example.com", "PATCH", "", http.StatusForbidden},
+		{"/heroes", "https://app.example.com", "PUT", "X-Unknown", http.StatusForbidden},
+		{"/heroes", "https://app.example.com", "DELETE", "", http.StatusNotFound},
+		{"/unknown", "https://app.example.com", "GET", "", http.StatusNotFound},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, request("OPTIONS", tc.url, map[string]string{
+			"Origin":                         tc.origin,
+			"Access-Control-Request-Method":  tc.method,
+			"Access-Control-Request-Headers": tc.headers,
+		}))
+
+		if w.Code != tc.status {
+			t.Errorf("%v != %v for: %v %v %v %v", tc.status, w.Code, tc.url, tc.origin, tc.method, tc.headers)
+		}
+		if len(w.Header()["Vary"]) != 3 {
+			t.Errorf("expect Vary: Origin, Access-Control-Request-Method, Access-Control-Request-Headers: %v", w.Header()["Vary"])
+		}
+		if tc.status != http.StatusNoContent {
+			continue
+		}
+
+		for k, v := range map[string]string{
+			"Access-Control-Allow-Origin":      tc.origin,
+			"Access-Control-Allow-Methods":     "GET, POST, PUT, DELETE, OPTIONS",
+			"Access-Control-Allow-Headers":     "Content-Type, Authorization",
+			"Access-Control-Allow-Credentials": "true",
+			"Access-Control-Max-Age":           "3600",
+		} {
+			if w.Header().Get(k) != v {
+				t.Errorf("expect %v: %v, got: %v", k, v, w.Header().Get(k))
+			}
+		}
+	}
+}
+
+func TestSimpleRequest(t *testing.T) {
+	o := Default()
+	o.AllowedOrigins = []string{"https://heroes.com"}
+	h := newHandler(o)
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, request("GET", "/heroes", map[string]string{"Origin": "https://heroes.com"}))
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://heroes.com" {
+		t.Errorf("expect the origin, got: %v", w.Header().Get("Access-Control-Allow-Origin"))
+	}
+	if w.Header().Get("Access-Control-Expose-Headers") != "ETag, Link" {
+		t.Errorf("expect ETag, Link, got: %v", w.Header().Get("Access-Control-Expose-Headers"))
+	}
+	if w.Header().Get("Vary") != "Origin" {
+		t.Errorf("expect Vary: Origin, got: %v", w.Header().Get("Vary"))
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, request("GET", "/heroes", map[string]string{"Origin": "https://evil.com"}))
+	if w.Header().Get("Access-Control-Allow-Origin") != "" || w.Code != http.StatusOK {
+		t.Errorf("expect no CORS header, got: %v (%v)", w.Header().Get("Access-Control-Allow-Origin"), w.Code)
+	}
+}
+
+func TestAllOrigins(t *testing.T) {
+	h := newHandler(Default())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, request("GET", "/heroes", map[string]string{"Origin": "https://heroes.com"}))
+	if w.Header().Get("Access-Control-Allow-Origin") != "*" || w.Header().Get("Vary") != "" {
+		t.Errorf("expect * without Vary, got: %v (Vary: %v)", w.Header().Get("Access-Control-Allow-Origin"), w.Header().Get("Vary"))
+	}
+
+	// with credentials * is not reflected
+	o := Default()
+	o.AllowCredentials = true
+	w = httptest.NewRecorder()
+	newHandler(o).ServeHTTP(w, request("GET", "/heroes", map[string]string{"Origin": "https://heroes.com"}))
+	if w.Header().Get("Access-Control-Allow-Origin") != "" || w.Header().Get("Access-Control-Allow-Credentials") != "" {
+		t.Errorf("expect no CORS headers for * with credentials, got: %v", w.Header())
+	}
+
+	// with credentials the allowed origin is returned
+	o.AllowedOrigins = []string{"https://heroes.com"}
+	w = httptest.NewRecorder()
+	newHandler(o).ServeHTTP(w, request("GET", "/heroes", map[string]string{"Origin": "https://heroes.com"}))
+	if w.Header().Get("Access-Control-Allow-Origin") != "https://heroes.com" || w.Header().Get("Vary") != "Origin" {
+		t.Errorf("expect the origin with Vary, got: %v (Vary: %v)", w.Header().Get("Access-Control-Allow-Origin"), w.Header().Get("Vary"))
+	}
+}
+
+func TestOptions(t *testing.T) {
+	h := newHandler(Default())
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, request("OPTIONS", "/heroes", nil))
+	if w.Code != http.StatusOK || w.Header().Get("Allow") != "GET, PUT, OPTIONS" {
+		t.Errorf("expect 200 with Allow: GET, PUT, OPTIONS, got: %v (%v)", w.Code, w.Header().Get("Allow"))
+	}
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, request("OPTIONS", "/unknown", nil))
+	if w.Code != http.StatusNotFound {
+		t.Errorf("expect 404 for unknown route, got: %v", w.Code)
+	}
+}
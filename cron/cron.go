@@ -0,0 +1,86 @@
+// Package cron protect the worker routes: only requests from the App Engine cron service
+// or requests with the shared secret (standalone mode) are accepted.
+package cron
+
+import (
+	"crypto/subtle"
+	"errors"
+	"log"
+	"net/http"
+	"os"
+	"sync/atomic"
+
+	"github.com/lima1909/goheroes-appengine/auth"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+const (
+	// EnvSecret is the Env-Variable with the shared secret for the worker requests (standalone mode)
+	EnvSecret = "HEROES_WORKER_SECRET"
+	// HeaderCron is set by App Engine for cron requests (and removed from all external requests)
+	HeaderCron = "X-Appengine-Cron"
+	// HeaderSecret contains the shared secret
+	HeaderSecret = "X-Worker-Secret"
+	// Role of the Principal of the accepted worker requests
+	Role = "worker"
+)
+
+var (
+	// ErrNotCron if the request is not from the cron service and contains no secret
+	ErrNotCron = errors.New("No cron request")
+	// ErrInvalidSecret if the secret of the request is not valid
+	ErrInvalidSecret = errors.New("Invalid worker secret")
+)
+
+// Guard check the worker requests and counts the rejected requests
+type Guard struct {
+	// TrustCron is true, if the X-Appengine-Cron header is trusted (only in App Engine)
+	TrustCron bool
+	// Secret is the shared secret (empty: no secret requests)
+	Secret string
+
+	rejected int64
+}
+
+// GuardFromEnv create a Guard, which trust the cron header in the cloud and the EnvSecret
+func GuardFromEnv() *Guard {
+	return &Guard{TrustCron: service.RunInCloud(), Secret: os.Getenv(EnvSecret)}
+}
+
+// Verify the request and returns the method (cron or secret) of the accepted request
+func (g *Guard) Verify(r *http.Request) (string, error) {
+	if g.TrustCron && r.Header.Get(HeaderCron) == "true" {
+		return "cron", nil
+	}
+
+	secret := r.Header.Get(HeaderSecret)
+	if secret == "" {
+		return "", ErrNotCron
+	}
+	if g.Secret == "" || subtle.ConstantTimeCompare([]byte(secret), []byte(g.Secret)) != 1 {
+		return "", ErrInvalidSecret
+	}
+	return "secret", nil
+}
+
+// Rejected returns the number of the rejected requests
+func (g *Guard) Rejected() int64 {
+	return atomic.LoadInt64(&g.rejected)
+}
+
+// Handler accept only the verified requests (with the Principal: cron and the Role: worker),
+// all other requests are logged and rejected with 403 (Forbidden)
+func Handler(h http.Handler, g *Guard) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		method, err := g.Verify(r)
+		if err != nil {
+			n := atomic.AddInt64(&g.rejected, 1)
+			log.Printf("reject worker request (%d) from: %s %s %s: %v\n", n, r.RemoteAddr, r.Method, r.URL.Path, err)
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+
+		p := &auth.Principal{Name: "cron", Roles: []string{Role}, Method: method}
+		h.ServeHTTP(w, r.WithContext(auth.WithPrincipal(r.Context(), p)))
+	})
+}
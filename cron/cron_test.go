@@ -0,0 +1,70 @@
+package cron
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/auth"
+)
+
+func TestHandler(t *testing.T) {
+	var principal *auth.Principal
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		principal = auth.PrincipalFromContext(r.Context())
+	}), &Guard{TrustCron: true, Secret: "s3cret"})
+
+	for _, tc := range []struct {
+		header, value string
+		status        int
+		method        string
+	}{
+		{HeaderCron, "true", http.StatusOK, "cron"},
+		{HeaderSecret, "s3cret", http.StatusOK, "secret"},
+		{HeaderSecret, "wrong", http.StatusForbidden, ""},
+		{"", "", http.StatusForbidden, ""},
+	} {
+		principal = nil
+		r := httptest.NewRequest("GET", "http://localhost:8080/worker/protocol", nil)
+		if tc.header != "" {
+			r.Header.Set(tc.header, tc.value)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tc.status {
+			t.Errorf("%v != %v for header: %v", tc.status, w.Code, tc.header)
+		}
+		if tc.method != "" && (principal == nil || principal.Method != tc.method || principal.Roles[0] != Role) {
+			t.Errorf("expect principal with method: %v, got: %v", tc.method, principal)
+		}
+	}
+}
+
+func TestGuardNotInCloud(t *testing.T) {
+	g := &Guard{}
+
+	r := httptest.NewRequest("GET", "http://localhost:8080/worker/protocol", nil)
+	r.Header.Set(HeaderCron, "true")
+	if _, err := g.Verify(r); err != ErrNotCron {
+		t.Errorf("the cron header is not trusted outside the cloud: %v", err)
+	}
+
+	// no secret configured: every secret is invalid
+	r.Header.Set(HeaderSecret, "x")
+	if _, err := g.Verify(r); err != ErrInvalidSecret {
+		t.Errorf("expect ErrInvalidSecret: %v", err)
+	}
+}
+
+func TestRejected(t *testing.T) {
+	g := &Guard{Secret: "s3cret"}
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}), g)
+
+	for i := 0; i < 3; i++ {
+		h.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest("GET", "http://localhost:8080/worker/protocol", nil))
+	}
+	if g.Rejected() != 3 {
+		t.Errorf("3 != %v", g.Rejected())
+	}
+}
@@ -0,0 +1,108 @@
+package db
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/lima1909/goheroes-appengine/service"
+	yaml "gopkg.in/yaml.v2"
+)
+
+const (
+	// EnvFixture is the Env-Variable with the path to a JSON or YAML file with the seed Heroes
+	EnvFixture = "HEROES_FIXTURE"
+	// EnvEmpty is the Env-Variable, if it is set to true, the MemService starts without Heroes
+	EnvEmpty = "HEROES_EMPTY"
+)
+
+// Fixture is the file representation of a Hero (with ScoreData, which is not visible in the Hero JSON)
+type Fixture struct {
+	ID        int64  `json:"id" yaml:"id"`
+	Name      string `json:"name" yaml:"name"`
+	ScoreData struct {
+		Name    string `json:"name" yaml:"name"`
+		City    string `json:"city" yaml:"city"`
+		Country string `json:"country" yaml:"country"`
+	} `json:"scoreData" yaml:"scoreData"`
+}
+
+// Hero convert the Fixture to a service.Hero
+func (f Fixture) Hero() service.Hero {
+	return service.Hero{
+		ID:   f.ID,
+		Name: f.Name,
+		ScoreData: service.ScoreData{
+			Name:    f.ScoreData.Name,
+			City:    f.ScoreData.City,
+			Country: f.ScoreData.Country,
+		},
+	}
+}
+
+// DefaultHeroes are the Heroes, which are used, if no fixture file is configured
+func DefaultHeroes() []service.Hero {
+	return []service.Hero{
+		service.Hero{ID: 1, Name: "Jasmin", ScoreData: service.ScoreData{Name: "jasmin-roeper", City: "Nuremberg", Country: "de"}},
+		service.Hero{ID: 2, Name: "Mario", ScoreData: service.ScoreData{Name: "mario-linke", City: "Nürnberg", Country: "de"}},
+		service.Hero{ID: 3, Name: "Alex M"},
+		service.Hero{ID: 4, Name: "Adam O"},
+		service.Hero{ID: 5, Name: "Shauna C"},
+		service.Hero{ID: 6, Name: "Lena H"},
+		service.Hero{ID: 7, Name: "Chris S"},
+	}
+}
+
+// LoadFixtures read the Heroes from a JSON (.json) or YAML (.yaml, .yml) file
+func LoadFixtures(file string) ([]service.Hero, error) {
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("can not read fixture file: %v", err)
+	}
+
+	fixtures := []Fixture{}
+	switch strings.ToLower(filepath.Ext(file)) {
+	case ".json":
+		err = json.Unmarshal(b, &fixtures)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(b, &fixtures)
+	default:
+		return nil, fmt.Errorf("unsupported fixture file: %s (only .json, .yaml or .yml)", file)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("can not parse fixture file: %s: %v", file, err)
+	}
+
+	heroes := make([]service.Hero, len(fixtures))
+	ids := map[int64]bool{}
+	for i, f := range fixtures {
+		if f.ID <= 0 {
+			return nil, fmt.Errorf("invalid ID: %v for Hero: %s in fixture file: %s", f.ID, f.Name, file)
+		}
+		if ids[f.ID] {
+			return nil, fmt.Errorf("duplicate ID: %v in fixture file: %s", f.ID, file)
+		}
+		ids[f.ID] = true
+		heroes[i] = f.Hero()
+	}
+
+	return heroes, nil
+}
+
+// SeedFromEnv returns the seed Heroes, which are configured by the Env-Variables:
+// EnvEmpty (no Heroes), EnvFixture (Heroes from file) or the DefaultHeroes
+func SeedFromEnv() ([]service.Hero, error) {
+	if empty, _ := strconv.ParseBool(os.Getenv(EnvEmpty)); empty {
+		return []service.Hero{}, nil
+	}
+
+	if file := os.Getenv(EnvFixture); file != "" {
+		return LoadFixtures(file)
+	}
+
+	return DefaultHeroes(), nil
+}
@@ -0,0 +1,66 @@
+package db
+
+import (
+	"os"
+	"testing"
+)
+
+func TestLoadFixturesJSON(t *testing.T) {
+	heroes, err := LoadFixtures("testdata/heroes.json")
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if len(heroes) != 3 {
+		t.Errorf("3 != %v", len(heroes))
+	}
+	if heroes[0].ScoreData.Name != "jasmin-roeper" {
+		t.Errorf("jasmin-roeper != %v", heroes[0].ScoreData.Name)
+	}
+}
+
+func TestLoadFixturesYAML(t *testing.T) {
+	heroes, err := LoadFixtures("testdata/heroes.yaml")
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if len(heroes) != 2 {
+		t.Errorf("2 != %v", len(heroes))
+	}
+	if heroes[0].ScoreData.City != "Nuremberg" {
+		t.Errorf("Nuremberg != %v", heroes[0].ScoreData.City)
+	}
+	if heroes[1].Name != "Mario" {
+		t.Errorf("Mario != %v", heroes[1].Name)
+	}
+}
+
+func TestLoadFixturesFail(t *testing.T) {
+	for _, f := range []string{"testdata/not-exist.json", "testdata/duplicate.json", "fixture.go"} {
+		_, err := LoadFixtures(f)
+		if err == nil {
+			t.Errorf("expected err for file: %v", f)
+		}
+	}
+}
+
+func TestSeedFromEnv(t *testing.T) {
+	defer os.Unsetenv(EnvEmpty)
+	defer os.Unsetenv(EnvFixture)
+
+	heroes, _ := SeedFromEnv()
+	if len(heroes) != len(DefaultHeroes()) {
+		t.Errorf("%v != %v", len(DefaultHeroes()), len(heroes))
+	}
+
+	os.Setenv(EnvFixture, "testdata/heroes.yaml")
+	heroes, _ = SeedFromEnv()
+	if len(heroes) != 2 {
+		t.Errorf("2 != %v", len(heroes))
+	}
+
+	os.Setenv(EnvEmpty, "true")
+	heroes, _ = SeedFromEnv()
+	if len(heroes) != 0 {
+		t.Errorf("0 != %v", len(heroes))
+	}
+}
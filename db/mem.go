@@ -4,35 +4,84 @@ import (
 	"context"
 	"log"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/lima1909/goheroes-appengine/service"
 )
 
-// MemService is a Impl from service.HeroService
+// MemService is a Impl from service.HeroService, it is safe for concurrent use
 type MemService struct {
+	mu     sync.RWMutex
 	heroes []service.Hero
 	maxID  int64
+
+	// seed are the Heroes for the Reset
+	seed []service.Hero
+
+	// trash contains the deleted Heroes, until they are purged (after the retention)
+	trash     []service.DeletedHero
+	retention time.Duration
 }
 
-// NewMemService create a new instance of MemService
+// NewMemService create a new instance of MemService with the DefaultHeroes
 func NewMemService() *MemService {
-	heroes := []service.Hero{
-		service.Hero{ID: 1, Name: "Jasmin", ScoreData: service.ScoreData{Name: "jasmin-roeper", City: "Nuremberg", Country: "de"}},
-		service.Hero{ID: 2, Name: "Mario", ScoreData: service.ScoreData{Name: "mario-linke", City: "Nürnberg", Country: "de"}},
-		service.Hero{ID: 3, Name: "Alex M"},
-		service.Hero{ID: 4, Name: "Adam O"},
-		service.Hero{ID: 5, Name: "Shauna C"},
-		service.Hero{ID: 6, Name: "Lena H"},
-		service.Hero{ID: 7, Name: "Chris S"},
+	return NewMemServiceWithHeroes(DefaultHeroes())
+}
+
+// NewMemServiceWithHeroes create a new instance of MemService with the given seed Heroes
+func NewMemServiceWithHeroes(seed []service.Hero) *MemService {
+	m := &MemService{seed: copyHeroes(seed), retention: DefaultRetention}
+	m.reset()
+	return m
+}
+
+// NewMemServiceFromEnv create a new instance of MemService with the seed Heroes from SeedFromEnv
+// and the trash retention from RetentionFromEnv
+func NewMemServiceFromEnv() (*MemService, error) {
+	seed, err := SeedFromEnv()
+	if err != nil {
+		return nil, err
+	}
+	retention, err := RetentionFromEnv()
+	if err != nil {
+		return nil, err
+	}
+
+	m := NewMemServiceWithHeroes(seed)
+	m.retention = retention
+	return m, nil
+}
+
+// Reset impl from ResetService, restore the seed Heroes
+func (m *MemService) Reset(c context.Context) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.reset()
+	log.Printf("reset heroes to seed with len: %v\n", len(m.heroes))
+	return nil
+}
+
+func (m *MemService) reset() {
+	m.heroes = copyHeroes(m.seed)
+	m.trash = nil
+	m.maxID = 0
+	for _, h := range m.heroes {
+		if h.ID > m.maxID {
+			m.maxID = h.ID
+		}
 	}
-	maxID := int64(7)
+}
 
-	return &MemService{heroes: heroes, maxID: maxID}
+func copyHeroes(heroes []service.Hero) []service.Hero {
+	c := make([]service.Hero, len(heroes))
+	copy(c, heroes)
+	return c
 }
 
 // Protocols impl from ProtocolService
-func (MemService) Protocols(c context.Context) ([]service.Protocol, error) {
+func (*MemService) Protocols(c context.Context) ([]service.Protocol, error) {
 	t := time.Now()
 
 	dummyProtocols := make([]service.Protocol, 8)
@@ -49,10 +98,31 @@ func (MemService) Protocols(c context.Context) ([]service.Protocol, error) {
 	return dummyProtocols, nil
 }
 
+// QueryProtocols impl from ProtocolService
+func (m *MemService) QueryProtocols(c context.Context, q service.ProtocolQuery) ([]service.Protocol, error) {
+	protocols, err := m.Protocols(c)
+	if err != nil {
+		return nil, err
+	}
+	return service.QueryProtocols(protocols, q), nil
+}
+
+// CountProtocols impl from ProtocolService
+func (m *MemService) CountProtocols(c context.Context, q service.ProtocolQuery) (int, error) {
+	protocols, err := m.Protocols(c)
+	if err != nil {
+		return 0, err
+	}
+	return service.CountProtocols(protocols, q), nil
+}
+
 // List all Heroes, there are saved in the heroes array
-func (m MemService) List(c context.Context, name string) ([]service.Hero, error) {
+func (m *MemService) List(c context.Context, name string) ([]service.Hero, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	if name == "" {
-		return m.heroes, nil
+		return copyHeroes(m.heroes), nil
 	}
 
 	hs := make([]service.Hero, 0)
@@ -66,7 +136,10 @@ func (m MemService) List(c context.Context, name string) ([]service.Hero, error)
 }
 
 // GetByID get Hero by the ID
-func (m MemService) GetByID(c context.Context, id int64) (*service.Hero, error) {
+func (m *MemService) GetByID(c context.Context, id int64) (*service.Hero, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
 	for _, h := range m.heroes {
 		if h.ID == id {
 			return &h, nil
@@ -77,6 +150,9 @@ func (m MemService) GetByID(c context.Context, id int64) (*service.Hero, error)
 
 // Add an Hero
 func (m *MemService) Add(c context.Context, name string) (*service.Hero, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
 	m.maxID++
 
 	h := service.Hero{Name: name, ID: m.maxID}
@@ -87,52 +163,72 @@ func (m *MemService) Add(c context.Context, name string) (*service.Hero, error)
 
 // Update an Hero
 func (m *MemService) Update(c context.Context, h service.Hero) (*service.Hero, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
 	for i, hero := range m.heroes {
 		if hero.ID == h.ID {
 			m.heroes[i] = h
 			log.Printf("update hero from: %v to: %v\n", hero, h)
-			return &m.heroes[i], nil
+			return &h, nil
 		}
 	}
 
 	return nil, service.ErrHeroNotFound
 }
 
-// UpdatePosition of Hero
+// UpdatePosition of Hero, pos is the new index in the list (0 <= pos < len)
 func (m *MemService) UpdatePosition(c context.Context, h service.Hero, pos int64) (*service.Hero, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
 
-	if pos > int64(len(m.heroes)+1) {
-		return nil, service.ErrPosNotFound
-	}
-
-	oldPos := 0
-	//need to get the hero on the server because of additional datas like scoreData
-	heroOnServer := service.Hero{}
+	oldPos := -1
 	for i, hero := range m.heroes {
 		if hero.ID == h.ID {
 			oldPos = i
-			heroOnServer = hero
 			break
 		}
 	}
+	if oldPos == -1 {
+		return nil, service.ErrHeroNotFound
+	}
 
-	newHeroesSlice := append(m.heroes[:oldPos], m.heroes[oldPos+1:]...)
-	m.heroes = append(newHeroesSlice[:pos], append([]service.Hero{heroOnServer}, newHeroesSlice[pos:]...)...)
+	if pos < 0 || pos >= int64(len(m.heroes)) {
+		return nil, service.ErrPosNotFound
+	}
 
-	//just for debugging and logging
-	for i, hero := range m.heroes {
-		if hero.ID == h.ID {
-			log.Printf("update pos of %v from: %v to: %v\n", hero.Name, oldPos, i)
-			break
+	m.heroes = move(m.heroes, oldPos, int(pos))
+	log.Printf("update pos of %v from: %v to: %v\n", m.heroes[pos].Name, oldPos, pos)
+
+	//need to return the hero on the server because of additional datas like scoreData
+	hero := m.heroes[pos]
+	return &hero, nil
+}
+
+// move returns a new slice, where the Hero on index from is moved to the index to
+func move(heroes []service.Hero, from, to int) []service.Hero {
+	moved := make([]service.Hero, 0, len(heroes))
+	for i, h := range heroes {
+		if i == from {
+			continue
+		}
+		if len(moved) == to {
+			moved = append(moved, heroes[from])
 		}
+		moved = append(moved, h)
 	}
-
-	return &m.heroes[pos], nil
+	if len(moved) == to {
+		moved = append(moved, heroes[from])
+	}
+	return moved
 }
 
-// Delete an Hero
+// Delete an Hero (move the Hero to the trash)
 func (m *MemService) Delete(c context.Context, id int64) (*service.Hero, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.purgeExpired()
 	hero := service.Hero{ID: -1}
 
 	for i, h := range m.heroes {
@@ -141,6 +237,7 @@ func (m *MemService) Delete(c context.Context, id int64) (*service.Hero, error)
 			//remove from List
 			log.Printf("delete hero: %v\n", hero)
 			m.heroes = append(m.heroes[:i], m.heroes[i+1:]...)
+			m.trash = append(m.trash, service.DeletedHero{Hero: hero, Pos: int64(i), DeletedAt: time.Now()})
 
 			return &hero, nil
 		}
@@ -2,6 +2,7 @@ package db
 
 import (
 	"context"
+	"sync"
 	"testing"
 
 	"github.com/lima1909/goheroes-appengine/service"
@@ -181,3 +182,82 @@ func TestSwitchPositions(t *testing.T) {
 	}
 
 }
+
+func TestNewMemServiceWithHeroes(t *testing.T) {
+	heroes, _ := LoadFixtures("testdata/heroes.json")
+	m := NewMemServiceWithHeroes(heroes)
+
+	// maxID is the greatest ID, not the length
+	if m.maxID != 5 {
+		t.Errorf("5 != %v", m.maxID)
+	}
+
+	h, _ := m.Add(context.TODO(), "Test")
+	if h.ID != 6 {
+		t.Errorf("6 != %v", h.ID)
+	}
+}
+
+func TestEmptyMemService(t *testing.T) {
+	m := NewMemServiceWithHeroes(nil)
+
+	fh, _ := m.List(context.TODO(), "")
+	if 0 != len(fh) {
+		t.Errorf("%v != %v", 0, len(fh))
+	}
+
+	h, _ := m.Add(context.TODO(), "Test")
+	if h.ID != 1 {
+		t.Errorf("1 != %v", h.ID)
+	}
+}
+
+func TestReset(t *testing.T) {
+	m := NewMemService()
+	size := len(m.heroes)
+
+	_, _ = m.Add(context.TODO(), "Test")
+	_, _ = m.Delete(context.TODO(), 1)
+	_, _ = m.Update(context.TODO(), service.Hero{ID: 2, Name: "Test"})
+
+	err := m.Reset(context.TODO())
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if len(m.heroes) != size {
+		t.Errorf("%v != %v", size, len(m.heroes))
+	}
+	if m.maxID != 7 {
+		t.Errorf("7 != %v", m.maxID)
+	}
+
+	h, _ := m.GetByID(context.TODO(), 2)
+	if h.Name != "Mario" {
+		t.Errorf("Mario != %v", h.Name)
+	}
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	m := NewMemService()
+	c := context.TODO()
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			h, _ := m.Add(c, "Hero")
+			m.Update(c, service.Hero{ID: h.ID, Name: "Changed"})
+			m.UpdatePosition(c, *h, 0)
+			m.List(c, "")
+			m.Delete(c, h.ID)
+			m.Trash(c)
+		}()
+	}
+	wg.Wait()
+
+	heroes, _ := m.List(c, "")
+	if len(heroes) != 7 {
+		t.Errorf("expect the 7 Heroes, got: %v", len(heroes))
+	}
+}
@@ -0,0 +1,148 @@
+package db
+
+import (
+	"context"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestUpdatePositionNotFound(t *testing.T) {
+	m := NewMemService()
+
+	_, err := m.UpdatePosition(context.TODO(), service.Hero{ID: 99}, 1)
+	if err != service.ErrHeroNotFound {
+		t.Errorf("expect ErrHeroNotFound, got: %v", err)
+	}
+	// the first Hero must not be moved
+	if m.heroes[0].ID != 1 {
+		t.Errorf("1 != %v", m.heroes[0].ID)
+	}
+}
+
+func TestUpdatePositionOutOfRange(t *testing.T) {
+	m := NewMemService()
+
+	for _, pos := range []int64{-1, int64(len(m.heroes)), int64(len(m.heroes) + 1)} {
+		_, err := m.UpdatePosition(context.TODO(), service.Hero{ID: 1}, pos)
+		if err != service.ErrPosNotFound {
+			t.Errorf("expect ErrPosNotFound for pos: %v, got: %v", pos, err)
+		}
+	}
+}
+
+func TestUpdatePositionFirstAndLast(t *testing.T) {
+	m := NewMemService()
+	last := int64(len(m.heroes) - 1)
+
+	h, err := m.UpdatePosition(context.TODO(), service.Hero{ID: 1}, last)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if h.ScoreData.Name != "jasmin-roeper" {
+		t.Errorf("expect Hero with ScoreData, got: %v", h)
+	}
+	if m.heroes[last].ID != 1 {
+		t.Errorf("1 != %v", m.heroes[last].ID)
+	}
+
+	_, _ = m.UpdatePosition(context.TODO(), service.Hero{ID: 1}, 0)
+	if m.heroes[0].ID != 1 {
+		t.Errorf("1 != %v", m.heroes[0].ID)
+	}
+}
+
+// UpdatePosition does not change the List, which was returned before
+func TestUpdatePositionNoAlias(t *testing.T) {
+	m := NewMemService()
+
+	before, _ := m.List(context.TODO(), "")
+	_, _ = m.UpdatePosition(context.TODO(), service.Hero{ID: 7}, 0)
+
+	if before[0].ID != 1 {
+		t.Errorf("1 != %v", before[0].ID)
+	}
+}
+
+// for any sequence of moves: no Hero is lost or duplicated and the moved Hero is on the new position
+func TestUpdatePositionInvariants(t *testing.T) {
+	f := func(seed int64, moves uint8) bool {
+		r := rand.New(rand.NewSource(seed))
+		m := NewMemService()
+		adds := r.Intn(10)
+		for i := 0; i < adds; i++ {
+			_, _ = m.Add(context.TODO(), "Test")
+		}
+		ids := heroIDs(m.heroes)
+
+		for i := 0; i < int(moves); i++ {
+			h := m.heroes[r.Intn(len(m.heroes))]
+			// with invalid positions
+			pos := int64(r.Intn(len(m.heroes)+4) - 2)
+
+			moved, err := m.UpdatePosition(context.TODO(), h, pos)
+			if pos < 0 || pos >= int64(len(m.heroes)) {
+				if err != service.ErrPosNotFound {
+					return false
+				}
+			} else if err != nil || moved.ID != h.ID || m.heroes[pos].ID != h.ID {
+				return false
+			}
+
+			if len(m.heroes) != len(ids) || !reflect.DeepEqual(heroIDs(m.heroes), ids) {
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// move keeps the order of all other Heroes
+func TestMoveKeepOrder(t *testing.T) {
+	f := func(n, from, to uint8) bool {
+		size := int(n%20) + 1
+		heroes := make([]service.Hero, size)
+		for i := range heroes {
+			heroes[i] = service.Hero{ID: int64(i)}
+		}
+		fr, tp := int(from)%size, int(to)%size
+
+		moved := move(heroes, fr, tp)
+		if moved[tp].ID != int64(fr) {
+			return false
+		}
+
+		others := []int64{}
+		for _, h := range moved {
+			if h.ID != int64(fr) {
+				others = append(others, h.ID)
+			}
+		}
+		for i := 1; i < len(others); i++ {
+			if others[i-1] >= others[i] {
+				return false
+			}
+		}
+		return true
+	}
+
+	if err := quick.Check(f, nil); err != nil {
+		t.Error(err)
+	}
+}
+
+// heroIDs returns a set of the Hero IDs
+func heroIDs(heroes []service.Hero) map[int64]int {
+	ids := map[int64]int{}
+	for _, h := range heroes {
+		ids[h.ID]++
+	}
+	return ids
+}
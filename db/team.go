@@ -0,0 +1,236 @@
+package db
+
+import (
+	"context"
+	"log"
+	"sync"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// TeamService is a Impl from service.TeamService, the Heroes are resolved by the HeroService
+type TeamService struct {
+	hs service.HeroService
+
+	mu      sync.Mutex
+	teams   []service.Team
+	members map[int64][]int64
+	maxID   int64
+}
+
+// NewTeamService create a new instance of TeamService without Teams
+func NewTeamService(hs service.HeroService) *TeamService {
+	return &TeamService{hs: hs, members: map[int64][]int64{}}
+}
+
+// Teams list all Teams
+func (ts *TeamService) Teams(c context.Context) ([]service.Team, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	teams := make([]service.Team, len(ts.teams))
+	copy(teams, ts.teams)
+	return teams, nil
+}
+
+// GetTeam get Team by the ID
+func (ts *TeamService) GetTeam(c context.Context, id int64) (*service.Team, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	i := ts.indexOf(id)
+	if i == -1 {
+		return nil, service.ErrTeamNotFound
+	}
+	t := ts.teams[i]
+	return &t, nil
+}
+
+// AddTeam add a Team without Heroes
+func (ts *TeamService) AddTeam(c context.Context, name string) (*service.Team, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	ts.maxID++
+	t := service.Team{ID: ts.maxID, Name: name}
+	ts.teams = append(ts.teams, t)
+	log.Printf("add team: %v\n", t)
+	return &t, nil
+}
+
+// UpdateTeam update the Name of the Team
+func (ts *TeamService) UpdateTeam(c context.Context, t service.Team) (*service.Team, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	i := ts.indexOf(t.ID)
+	if i == -1 {
+		return nil, service.ErrTeamNotFound
+	}
+	log.Printf("update team from: %v to: %v\n", ts.teams[i], t)
+	ts.teams[i] = t
+	return &t, nil
+}
+
+// DeleteTeam delete a Team (the Heroes are not deleted)
+func (ts *TeamService) DeleteTeam(c context.Context, id int64) (*service.Team, error) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	i := ts.indexOf(id)
+	if i == -1 {
+		return nil, service.ErrTeamNotFound
+	}
+	t := ts.teams[i]
+	ts.teams = append(ts.teams[:i:i], ts.teams[i+1:]...)
+	delete(ts.members, id)
+	log.Printf("delete team: %v\n", t)
+	return &t, nil
+}
+
+// TeamHeroes list the Heroes of the Team in the Team order,
+// Heroes which are not found by the HeroService (e.g. deleted) are skipped
+func (ts *TeamService) TeamHeroes(c context.Context, teamID int64) ([]service.Hero, error) {
+	ts.mu.Lock()
+	if ts.indexOf(teamID) == -1 {
+		ts.mu.Unlock()
+		return nil, service.ErrTeamNotFound
+	}
+	ids := make([]int64, len(ts.members[teamID]))
+	copy(ids, ts.members[teamID])
+	ts.mu.Unlock()
+
+	heroes := make([]service.Hero, 0, len(ids))
+	for _, id := range ids {
+		h, err := ts.hs.GetByID(c, id)
+		if err == service.ErrHeroNotFound {
+			continue
+		} else if err != nil {
+			return nil, err
+		}
+		heroes = append(heroes, *h)
+	}
+	return heroes, nil
+}
+
+// AddTeamHero add the Hero on the end of the Team
+func (ts *TeamService) AddTeamHero(c context.Context, teamID, heroID int64) (*service.Hero, error) {
+	h, err := ts.hs.GetByID(c, heroID)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.indexOf(teamID) == -1 {
+		return nil, service.ErrTeamNotFound
+	}
+	if indexOfID(ts.members[teamID], heroID) != -1 {
+		return nil, service.ErrHeroInTeam
+	}
+	ts.members[teamID] = append(ts.members[teamID], heroID)
+	log.Printf("add hero: %v to team: %v\n", heroID, teamID)
+	return h, nil
+}
+
+// UpdateTeamHeroPosition move the Hero inside the Team, pos is the new index in the TeamHeroes (0 <= pos < len),
+// the deleted Heroes (they are not in the TeamHeroes) keep their places
+func (ts *TeamService) UpdateTeamHeroPosition(c context.Context, teamID, heroID, pos int64) (*service.Hero, error) {
+	h, err := ts.hs.GetByID(c, heroID)
+	if err != nil {
+		return nil, err
+	}
+
+	ts.mu.Lock()
+	ids := make([]int64, len(ts.members[teamID]))
+	copy(ids, ts.members[teamID])
+	ts.mu.Unlock()
+
+	deleted := map[int64]bool{}
+	for _, id := range ids {
+		if _, err := ts.hs.GetByID(c, id); err == service.ErrHeroNotFound {
+			deleted[id] = true
+		} else if err != nil {
+			return nil, err
+		}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.indexOf(teamID) == -1 {
+		return nil, service.ErrTeamNotFound
+	}
+	ids = ts.members[teamID]
+	from := indexOfID(ids, heroID)
+	if from == -1 {
+		return nil, service.ErrHeroNotFound
+	}
+
+	// the visible Heroes (like TeamHeroes) in the new order
+	visible := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if !deleted[id] && id != heroID {
+			visible = append(visible, id)
+		}
+	}
+	if pos < 0 || pos > int64(len(visible)) {
+		return nil, service.ErrPosNotFound
+	}
+	visible = append(visible[:pos], append([]int64{heroID}, visible[pos:]...)...)
+
+	moved := make([]int64, len(ids))
+	for i, id := range ids {
+		if deleted[id] {
+			moved[i] = id
+			continue
+		}
+		moved[i], visible = visible[0], visible[1:]
+	}
+	ts.members[teamID] = moved
+	log.Printf("update pos of hero: %v in team: %v from: %v to: %v\n", heroID, teamID, from, pos)
+	return h, nil
+}
+
+// RemoveTeamHero remove the Hero from the Team (the Hero is not deleted)
+func (ts *TeamService) RemoveTeamHero(c context.Context, teamID, heroID int64) (*service.Hero, error) {
+	h, err := ts.hs.GetByID(c, heroID)
+	if err != nil {
+		// the Hero can be removed from the Team, if the Hero is already deleted
+		h = &service.Hero{ID: heroID}
+	}
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	if ts.indexOf(teamID) == -1 {
+		return nil, service.ErrTeamNotFound
+	}
+	ids := ts.members[teamID]
+	i := indexOfID(ids, heroID)
+	if i == -1 {
+		return nil, service.ErrHeroNotFound
+	}
+	ts.members[teamID] = append(ids[:i:i], ids[i+1:]...)
+	log.Printf("remove hero: %v from team: %v\n", heroID, teamID)
+	return h, nil
+}
+
+func (ts *TeamService) indexOf(id int64) int {
+	for i, t := range ts.teams {
+		if t.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+func indexOfID(ids []int64, id int64) int {
+	for i, v := range ids {
+		if v == id {
+			return i
+		}
+	}
+	return -1
+}
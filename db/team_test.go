@@ -0,0 +1,154 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestAddTeamAndHeroes(t *testing.T) {
+	ts := NewTeamService(NewMemService())
+
+	team, err := ts.AddTeam(context.TODO(), "Boulder")
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+
+	_, _ = ts.AddTeamHero(context.TODO(), team.ID, 3)
+	_, _ = ts.AddTeamHero(context.TODO(), team.ID, 1)
+
+	heroes, err := ts.TeamHeroes(context.TODO(), team.ID)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if len(heroes) != 2 || heroes[0].ID != 3 || heroes[1].ID != 1 {
+		t.Errorf("expect Heroes 3 and 1, got: %v", heroes)
+	}
+
+	_, err = ts.AddTeamHero(context.TODO(), team.ID, 1)
+	if err != service.ErrHeroInTeam {
+		t.Errorf("expect ErrHeroInTeam, got: %v", err)
+	}
+	_, err = ts.AddTeamHero(context.TODO(), team.ID, 99)
+	if err != service.ErrHeroNotFound {
+		t.Errorf("expect ErrHeroNotFound, got: %v", err)
+	}
+	_, err = ts.AddTeamHero(context.TODO(), 99, 1)
+	if err != service.ErrTeamNotFound {
+		t.Errorf("expect ErrTeamNotFound, got: %v", err)
+	}
+}
+
+func TestHeroInMoreTeams(t *testing.T) {
+	ts := NewTeamService(NewMemService())
+
+	t1, _ := ts.AddTeam(context.TODO(), "Boulder")
+	t2, _ := ts.AddTeam(context.TODO(), "Lead")
+	_, _ = ts.AddTeamHero(context.TODO(), t1.ID, 1)
+	_, _ = ts.AddTeamHero(context.TODO(), t1.ID, 2)
+	_, _ = ts.AddTeamHero(context.TODO(), t2.ID, 2)
+	_, _ = ts.AddTeamHero(context.TODO(), t2.ID, 1)
+
+	// per-team ordering
+	_, err := ts.UpdateTeamHeroPosition(context.TODO(), t1.ID, 2, 0)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+
+	h1, _ := ts.TeamHeroes(context.TODO(), t1.ID)
+	h2, _ := ts.TeamHeroes(context.TODO(), t2.ID)
+	if h1[0].ID != 2 || h2[0].ID != 2 || h2[1].ID != 1 {
+		t.Errorf("expect Hero 2 first in both Teams, got: %v and %v", h1, h2)
+	}
+
+	_, err = ts.UpdateTeamHeroPosition(context.TODO(), t1.ID, 2, 2)
+	if err != service.ErrPosNotFound {
+		t.Errorf("expect ErrPosNotFound, got: %v", err)
+	}
+}
+
+func TestRemoveTeamHero(t *testing.T) {
+	ts := NewTeamService(NewMemService())
+
+	team, _ := ts.AddTeam(context.TODO(), "Boulder")
+	_, _ = ts.AddTeamHero(context.TODO(), team.ID, 1)
+
+	_, err := ts.RemoveTeamHero(context.TODO(), team.ID, 1)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	_, err = ts.RemoveTeamHero(context.TODO(), team.ID, 1)
+	if err != service.ErrHeroNotFound {
+		t.Errorf("expect ErrHeroNotFound, got: %v", err)
+	}
+}
+
+func TestTeamHeroesSkipDeleted(t *testing.T) {
+	m := NewMemService()
+	ts := NewTeamService(m)
+
+	team, _ := ts.AddTeam(context.TODO(), "Boulder")
+	_, _ = ts.AddTeamHero(context.TODO(), team.ID, 1)
+	_, _ = ts.AddTeamHero(context.TODO(), team.ID, 2)
+	_, _ = m.Delete(context.TODO(), 1)
+
+	heroes, _ := ts.TeamHeroes(context.TODO(), team.ID)
+	if len(heroes) != 1 || heroes[0].ID != 2 {
+		t.Errorf("expect Hero 2, got: %v", heroes)
+	}
+}
+
+func TestMoveWithDeletedHero(t *testing.T) {
+	m := NewMemService()
+	ts := NewTeamService(m)
+
+	team, _ := ts.AddTeam(context.TODO(), "Boulder")
+	for _, id := range []int64{1, 2, 3, 4} {
+		_, _ = ts.AddTeamHero(context.TODO(), team.ID, id)
+	}
+	_, _ = m.Delete(context.TODO(), 1)
+
+	// the positions of the visible Heroes: 2, 3, 4
+	if _, err := ts.UpdateTeamHeroPosition(context.TODO(), team.ID, 4, 3); err != service.ErrPosNotFound {
+		t.Errorf("expect ErrPosNotFound, got: %v", err)
+	}
+	if _, err := ts.UpdateTeamHeroPosition(context.TODO(), team.ID, 2, 2); err != nil {
+		t.Fatalf("no err expected: %v", err)
+	}
+	heroes, _ := ts.TeamHeroes(context.TODO(), team.ID)
+	if len(heroes) != 3 || heroes[0].ID != 3 || heroes[1].ID != 4 || heroes[2].ID != 2 {
+		t.Errorf("expect the Heroes 3, 4, 2, got: %v", heroes)
+	}
+
+	// the restored Hero is on the old place
+	_, _ = m.Restore(context.TODO(), 1)
+	heroes, _ = ts.TeamHeroes(context.TODO(), team.ID)
+	if len(heroes) != 4 || heroes[0].ID != 1 || heroes[3].ID != 2 {
+		t.Errorf("expect the Heroes 1, 3, 4, 2, got: %v", heroes)
+	}
+}
+
+func TestUpdateAndDeleteTeam(t *testing.T) {
+	ts := NewTeamService(NewMemService())
+
+	team, _ := ts.AddTeam(context.TODO(), "Boulder")
+	_, err := ts.UpdateTeam(context.TODO(), service.Team{ID: team.ID, Name: "Lead"})
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	tu, _ := ts.GetTeam(context.TODO(), team.ID)
+	if tu.Name != "Lead" {
+		t.Errorf("Lead != %v", tu.Name)
+	}
+
+	_, _ = ts.DeleteTeam(context.TODO(), team.ID)
+	teams, _ := ts.Teams(context.TODO())
+	if len(teams) != 0 {
+		t.Errorf("0 != %v", len(teams))
+	}
+	_, err = ts.TeamHeroes(context.TODO(), team.ID)
+	if err != service.ErrTeamNotFound {
+		t.Errorf("expect ErrTeamNotFound, got: %v", err)
+	}
+}
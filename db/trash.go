@@ -0,0 +1,103 @@
+package db
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+const (
+	// EnvRetention is the Env-Variable for the duration (e.g. 24h), how long deleted Heroes are kept in the trash
+	EnvRetention = "HEROES_TRASH_RETENTION"
+	// DefaultRetention of deleted Heroes, if EnvRetention is not set
+	DefaultRetention = 7 * 24 * time.Hour
+)
+
+// RetentionFromEnv returns the trash retention from EnvRetention or the DefaultRetention
+func RetentionFromEnv() (time.Duration, error) {
+	r := os.Getenv(EnvRetention)
+	if r == "" {
+		return DefaultRetention, nil
+	}
+
+	d, err := time.ParseDuration(r)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("invalid trash retention: %s=%s", EnvRetention, r)
+	}
+	return d, nil
+}
+
+// Trash impl from TrashService, list all deleted Heroes, which are not expired
+func (m *MemService) Trash(c context.Context) ([]service.DeletedHero, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.purgeExpired()
+
+	trash := make([]service.DeletedHero, len(m.trash))
+	copy(trash, m.trash)
+	return trash, nil
+}
+
+// Restore impl from TrashService, move the Hero from the trash to the previous position
+func (m *MemService) Restore(c context.Context, id int64) (*service.Hero, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.purgeExpired()
+
+	for i, d := range m.trash {
+		if d.ID == id {
+			m.trash = append(m.trash[:i], m.trash[i+1:]...)
+
+			pos := int(d.Pos)
+			if pos > len(m.heroes) {
+				pos = len(m.heroes)
+			}
+			heroes := make([]service.Hero, 0, len(m.heroes)+1)
+			heroes = append(heroes, m.heroes[:pos]...)
+			heroes = append(heroes, d.Hero)
+			m.heroes = append(heroes, m.heroes[pos:]...)
+
+			log.Printf("restore hero: %v to pos: %v\n", d.Hero, pos)
+			hero := d.Hero
+			return &hero, nil
+		}
+	}
+
+	return nil, service.ErrHeroNotFound
+}
+
+// Purge impl from TrashService, remove all Heroes from the trash, which are deleted before the given time
+func (m *MemService) Purge(c context.Context, before time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.purge(before), nil
+}
+
+// purge remove the Heroes from the trash, which are deleted before the given time (m.mu must be locked)
+func (m *MemService) purge(before time.Time) int {
+	trash := make([]service.DeletedHero, 0, len(m.trash))
+	for _, d := range m.trash {
+		if d.DeletedAt.After(before) {
+			trash = append(trash, d)
+		}
+	}
+
+	count := len(m.trash) - len(trash)
+	m.trash = trash
+	if count > 0 {
+		log.Printf("purge heroes from trash: %v\n", count)
+	}
+	return count
+}
+
+// remove all Heroes from the trash, which are older than the retention (m.mu must be locked)
+func (m *MemService) purgeExpired() {
+	m.purge(time.Now().Add(-m.retention))
+}
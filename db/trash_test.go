@@ -0,0 +1,129 @@
+package db
+
+import (
+	"context"
+	"os"
+	"testing"
+	"time"
+)
+
+func TestDeleteMoveToTrash(t *testing.T) {
+	m := NewMemService()
+
+	_, _ = m.Delete(context.TODO(), 3)
+
+	trash, err := m.Trash(context.TODO())
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if len(trash) != 1 {
+		t.Errorf("1 != %v", len(trash))
+	}
+	if trash[0].ID != 3 || trash[0].Pos != 2 {
+		t.Errorf("expect ID 3 on Pos 2, got: %v", trash[0])
+	}
+	if trash[0].DeletedAt.IsZero() {
+		t.Errorf("expect DeletedAt")
+	}
+}
+
+func TestRestore(t *testing.T) {
+	m := NewMemService()
+	size := len(m.heroes)
+
+	_, _ = m.Delete(context.TODO(), 3)
+	h, err := m.Restore(context.TODO(), 3)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if h.ID != 3 {
+		t.Errorf("3 != %v", h.ID)
+	}
+
+	// restore on the previous position
+	if len(m.heroes) != size {
+		t.Errorf("%v != %v", size, len(m.heroes))
+	}
+	if m.heroes[2].ID != 3 {
+		t.Errorf("3 != %v", m.heroes[2].ID)
+	}
+	if len(m.trash) != 0 {
+		t.Errorf("0 != %v", len(m.trash))
+	}
+
+	// the second time, the Hero is not in the trash
+	_, err = m.Restore(context.TODO(), 3)
+	if err == nil {
+		t.Errorf("err expected")
+	}
+}
+
+func TestRestoreOutOfRange(t *testing.T) {
+	m := NewMemService()
+
+	_, _ = m.Delete(context.TODO(), 7)
+	_, _ = m.Delete(context.TODO(), 6)
+	_, _ = m.Delete(context.TODO(), 5)
+
+	// previous position 6 is greater than the list, restore on the end
+	_, err := m.Restore(context.TODO(), 7)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if m.heroes[len(m.heroes)-1].ID != 7 {
+		t.Errorf("7 != %v", m.heroes[len(m.heroes)-1].ID)
+	}
+}
+
+func TestPurge(t *testing.T) {
+	m := NewMemService()
+
+	_, _ = m.Delete(context.TODO(), 1)
+	_, _ = m.Delete(context.TODO(), 2)
+	m.trash[0].DeletedAt = time.Now().Add(-2 * time.Hour)
+
+	count, err := m.Purge(context.TODO(), time.Now().Add(-time.Hour))
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("1 != %v", count)
+	}
+	if len(m.trash) != 1 || m.trash[0].ID != 2 {
+		t.Errorf("expect Hero 2 in trash, got: %v", m.trash)
+	}
+}
+
+func TestPurgeExpired(t *testing.T) {
+	m := NewMemService()
+	m.retention = time.Hour
+
+	_, _ = m.Delete(context.TODO(), 1)
+	m.trash[0].DeletedAt = time.Now().Add(-2 * time.Hour)
+
+	trash, _ := m.Trash(context.TODO())
+	if len(trash) != 0 {
+		t.Errorf("0 != %v", len(trash))
+	}
+}
+
+func TestRetentionFromEnv(t *testing.T) {
+	defer os.Unsetenv(EnvRetention)
+
+	r, _ := RetentionFromEnv()
+	if r != DefaultRetention {
+		t.Errorf("%v != %v", DefaultRetention, r)
+	}
+
+	os.Setenv(EnvRetention, "36h")
+	r, _ = RetentionFromEnv()
+	if r != 36*time.Hour {
+		t.Errorf("36h != %v", r)
+	}
+
+	os.Setenv(EnvRetention, "no duration")
+	_, err := RetentionFromEnv()
+	if err == nil {
+		t.Errorf("err expected")
+	}
+}
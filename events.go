@@ -0,0 +1,94 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/events"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// keepAlive is the interval of the comments, which keep the event stream open (e.g. by proxies)
+var keepAlive = 30 * time.Second
+
+// eventV2 is the Event of the API v2 with the Hero of v2
+type eventV2 struct {
+	events.Event
+	Hero *heroV2 `json:"hero,omitempty"`
+}
+
+// heroEvents stream the changes of the Heroes as Server-Sent Events, the client can resume with the
+// header Last-Event-ID (or the query lastEventId), if the events are not longer in the replay buffer,
+// the first event is: reset (the client must reload the Heroes)
+func heroEvents(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	lastID := int64(0)
+	if last := r.Header.Get("Last-Event-ID"); last != "" || r.URL.Query().Get("lastEventId") != "" {
+		if last == "" {
+			last = r.URL.Query().Get("lastEventId")
+		}
+		var err error
+		if lastID, err = strconv.ParseInt(last, 10, 64); err != nil || lastID < 0 {
+			http.Error(w, "invalid Last-Event-ID: "+last, http.StatusBadRequest)
+			return
+		}
+	}
+
+	replay, ch, cancel, complete := app.events.Subscribe(service.TenantFromContext(r.Context()), lastID)
+	defer cancel()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+
+	if !complete {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, e := range replay {
+		if err := writeEvent(w, r, e); err != nil {
+			return
+		}
+	}
+	flusher.Flush()
+
+	ticker := time.NewTicker(keepAlive)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				// too slow, the client reconnects with the Last-Event-ID
+				return
+			}
+			if err := writeEvent(w, r, e); err != nil {
+				return
+			}
+		case <-ticker.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+		}
+		flusher.Flush()
+	}
+}
+
+// writeEvent write the Event in the representation of the API version
+func writeEvent(w http.ResponseWriter, r *http.Request, e events.Event) error {
+	b, err := json.Marshal(versioned(r, e))
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "id: %d\ndata: %s\n\n", e.ID, b)
+	return err
+}
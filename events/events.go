@@ -0,0 +1,134 @@
+// Package events publish the changes of the Heroes (the Protocol and the Hero after the change)
+// to the subscribers, e.g. the Server-Sent Events of the API. The last events are kept in a bounded
+// replay buffer, so a subscriber can resume after the last received event (Last-Event-ID).
+package events
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// EnvBufferSize is the Env-Variable with the size of the replay buffer (default: DefaultBufferSize)
+const EnvBufferSize = "HEROES_EVENTS_BUFFER"
+
+// DefaultBufferSize is the default size of the replay buffer
+const DefaultBufferSize = 256
+
+// the size of the channel of a subscriber, a subscriber which is too slow is dropped
+const subscriberBuffer = 64
+
+// Event is a change of a Hero
+type Event struct {
+	ID       int64            `json:"id"`
+	Protocol service.Protocol `json:"protocol"`
+	// Hero is the state after the change (nil after Delete and Reset)
+	Hero *service.Hero `json:"hero,omitempty"`
+}
+
+// Broker impl from service.Publisher, it sends the events to the subscribers of the tenant
+type Broker struct {
+	mu     sync.Mutex
+	size   int
+	lastID int64
+	// buffer are the last events (oldest first)
+	buffer []Event
+	subs   map[chan Event]string
+}
+
+// NewBroker create a Broker with the size of the replay buffer
+func NewBroker(size int) *Broker {
+	if size < 1 {
+		size = DefaultBufferSize
+	}
+	return &Broker{size: size, subs: map[chan Event]string{}}
+}
+
+// FromEnv create the Broker with the size of the replay buffer of the Env-Variable EnvBufferSize
+func FromEnv() (*Broker, error) {
+	size := DefaultBufferSize
+	if s := os.Getenv(EnvBufferSize); s != "" {
+		var err error
+		if size, err = strconv.Atoi(s); err != nil || size < 1 {
+			return nil, fmt.Errorf("invalid %s: %s", EnvBufferSize, s)
+		}
+	}
+	return NewBroker(size), nil
+}
+
+// Publish impl from service.Publisher, the tenant of the Protocol is the tenant of the context
+func (b *Broker) Publish(c context.Context, p service.Protocol, h *service.Hero) {
+	p.Tenant = service.TenantFromContext(c)
+	if h != nil {
+		hero := *h
+		h = &hero
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lastID++
+	e := Event{ID: b.lastID, Protocol: p, Hero: h}
+	b.buffer = append(b.buffer, e)
+	if len(b.buffer) > b.size {
+		b.buffer = append([]Event{}, b.buffer[len(b.buffer)-b.size:]...)
+	}
+
+	for ch, tenant := range b.subs {
+		if tenant != p.Tenant {
+			continue
+		}
+		select {
+		case ch <- e:
+		default:
+			// the subscriber is too slow, he can resume with the last received event
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+}
+
+// LastID returns the ID of the last published event (0: no event)
+func (b *Broker) LastID() int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.lastID
+}
+
+// Subscribe the events of the tenant after the event with the lastID (0: only the new events),
+// it returns the events of the replay buffer after lastID and the channel for the new events,
+// which is closed by cancel or if the subscriber is too slow.
+// complete is false, if events after lastID are no longer in the replay buffer.
+func (b *Broker) Subscribe(tenant string, lastID int64) (replay []Event, events <-chan Event, cancel func(), complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete = true
+	if lastID > 0 {
+		// the events are lost or the ID is unknown (e.g. after a restart)
+		if (len(b.buffer) > 0 && b.buffer[0].ID > lastID+1) || lastID > b.lastID {
+			complete = false
+		}
+		for _, e := range b.buffer {
+			if e.ID > lastID && e.Protocol.Tenant == tenant {
+				replay = append(replay, e)
+			}
+		}
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	b.subs[ch] = tenant
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[ch]; ok {
+			delete(b.subs, ch)
+			close(ch)
+		}
+	}
+	return replay, ch, cancel, complete
+}
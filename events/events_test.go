@@ -0,0 +1,144 @@
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestPublishAndSubscribe(t *testing.T) {
+	b := NewBroker(3)
+	c := context.Background()
+
+	replay, ch, cancel, complete := b.Subscribe("", 0)
+	defer cancel()
+	if len(replay) != 0 || !complete {
+		t.Errorf("expect no replay, got: %v (%v)", replay, complete)
+	}
+
+	b.Publish(c, service.NewProtocol("Add", 1, "add"), &service.Hero{ID: 1, Name: "Jasmin"})
+	b.Publish(service.WithTenant(c, "other"), service.NewProtocol("Add", 2, "add"), &service.Hero{ID: 2})
+
+	e := <-ch
+	if e.ID != 1 || e.Hero.Name != "Jasmin" || e.Protocol.Action != "Add" {
+		t.Errorf("unexpected event: %v", e)
+	}
+	select {
+	case e = <-ch:
+		t.Errorf("expect no event of the other tenant, got: %v", e)
+	default:
+	}
+}
+
+func TestReplay(t *testing.T) {
+	b := NewBroker(3)
+	c := context.Background()
+	for i := int64(1); i <= 5; i++ {
+		b.Publish(c, service.NewProtocol("Update", i, "update"), &service.Hero{ID: i})
+	}
+
+	for _, tc := range []struct {
+		lastID   int64
+		ids      []int64
+		complete bool
+	}{
+		{0, nil, true},
+		{4, []int64{5}, true},
+		{2, []int64{3, 4, 5}, true},
+		// the events 2 and 3 are lost
+		{1, []int64{3, 4, 5}, false},
+		// unknown ID, e.g. after a restart
+		{99, nil, false},
+	} {
+		replay, _, cancel, complete := b.Subscribe("", tc.lastID)
+		cancel()
+		if complete != tc.complete || len(replay) != len(tc.ids) {
+			t.Errorf("lastID %v: expect %v (%v), got: %v (%v)", tc.lastID, tc.ids, tc.complete, replay, complete)
+			continue
+		}
+		for i, e := range replay {
+			if e.ID != tc.ids[i] {
+				t.Errorf("lastID %v: %v != %v", tc.lastID, tc.ids[i], e.ID)
+			}
+		}
+	}
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	b := NewBroker(1)
+	_, ch, cancel, _ := b.Subscribe("", 0)
+	defer cancel()
+
+	for i := 0; i <= subscriberBuffer; i++ {
+		b.Publish(context.Background(), service.NewProtocol("Add", 1, "add"), nil)
+	}
+
+	count := 0
+	for range ch {
+		count++
+	}
+	if count != subscriberBuffer {
+		t.Errorf("expect %v events and the closed channel, got: %v", subscriberBuffer, count)
+	}
+}
+
+func TestHeroService(t *testing.T) {
+	b := NewBroker(DefaultBufferSize)
+	hs := NewHeroService(db.NewMemService(), b)
+	c := context.Background()
+	_, ch, cancel, _ := b.Subscribe("", 0)
+	defer cancel()
+
+	h, err := hs.Add(c, "Events")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	h.Name = "Events2"
+	hs.Update(c, *h)
+	hs.UpdatePosition(c, *h, 0)
+	hs.Delete(c, h.ID)
+	// errors and reads are not published
+	hs.Delete(c, 9999)
+	hs.List(c, "")
+
+	received := []Event{}
+	actions := []string{}
+	for len(ch) > 0 {
+		e := <-ch
+		received = append(received, e)
+		actions = append(actions, e.Protocol.Action)
+	}
+	if len(received) != 4 || actions[0] != "Add" || actions[1] != "Update" || actions[2] != "UpdatePosition" || actions[3] != "Delete" {
+		t.Fatalf("unexpected events: %v", actions)
+	}
+	if received[1].Hero == nil || received[1].Hero.Name != "Events2" || received[3].Hero != nil {
+		t.Errorf("unexpected Heroes: %v", received)
+	}
+}
+
+type fixedScores map[int64]int
+
+func (f fixedScores) Scores(context.Context, service.HeroService) (map[int64]int, error) {
+	return f, nil
+}
+
+func TestScoreEvents(t *testing.T) {
+	scores := fixedScores{1: 10, 2: 20}
+	broker := NewBroker(DefaultBufferSize)
+	ss := NewScoreService(scores, broker)
+	_, ch, cancel, _ := broker.Subscribe("", 0)
+	defer cancel()
+
+	ss.Scores(context.Background(), nil)
+	scores[2] = 25
+	ss.Scores(context.Background(), nil)
+
+	if len(ch) != 1 {
+		t.Fatalf("expect one Score event, got: %v", len(ch))
+	}
+	if e := <-ch; e.Protocol.Action != "Score" || e.Protocol.HeroID != 2 || e.Hero != nil {
+		t.Errorf("unexpected event: %v", e)
+	}
+}
@@ -0,0 +1,54 @@
+package events
+
+import (
+	"context"
+	"sort"
+	"sync"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// ScoreService decorate a ScoreService, the changed scores (since the last call) are published
+type ScoreService struct {
+	ss  service.ScoreService
+	pub service.Publisher
+
+	mu sync.Mutex
+	// last scores by tenant
+	last map[string]map[int64]int
+}
+
+// NewScoreService create a new instance
+func NewScoreService(ss service.ScoreService, pub service.Publisher) *ScoreService {
+	return &ScoreService{ss: ss, pub: pub, last: map[string]map[int64]int{}}
+}
+
+// Scores impl from ScoreService, the first score of a Hero is not published
+func (s *ScoreService) Scores(c context.Context, svc service.HeroService) (map[int64]int, error) {
+	scores, err := s.ss.Scores(c, svc)
+	if err != nil {
+		return scores, err
+	}
+
+	tenant := service.TenantFromContext(c)
+	changed := []service.Protocol{}
+	s.mu.Lock()
+	last, ok := s.last[tenant]
+	if !ok {
+		last = map[int64]int{}
+		s.last[tenant] = last
+	}
+	for id, score := range scores {
+		if old, ok := last[id]; ok && old != score {
+			changed = append(changed, service.NewProtocolf("Score", id, "Score of Hero: %v changed from: %v to: %v", id, old, score))
+		}
+		last[id] = score
+	}
+	s.mu.Unlock()
+
+	sort.Slice(changed, func(i, j int) bool { return changed[i].HeroID < changed[j].HeroID })
+	for _, p := range changed {
+		s.pub.Publish(c, p, nil)
+	}
+	return scores, nil
+}
@@ -0,0 +1,126 @@
+package events
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// HeroService decorate a ProtocolHeroService, every successful change is published
+// (for the standalone mode, in the cloud the gcloud.HeroService publish the changes)
+type HeroService struct {
+	hs  service.ProtocolHeroService
+	pub service.Publisher
+}
+
+// NewHeroService create a new instance
+func NewHeroService(hs service.ProtocolHeroService, pub service.Publisher) *HeroService {
+	return &HeroService{hs: hs, pub: pub}
+}
+
+// Protocols delegate to HeroService
+func (hs *HeroService) Protocols(c context.Context) ([]service.Protocol, error) {
+	return hs.hs.Protocols(c)
+}
+
+// QueryProtocols delegate to HeroService
+func (hs *HeroService) QueryProtocols(c context.Context, q service.ProtocolQuery) ([]service.Protocol, error) {
+	return hs.hs.QueryProtocols(c, q)
+}
+
+// CountProtocols delegate to HeroService
+func (hs *HeroService) CountProtocols(c context.Context, q service.ProtocolQuery) (int, error) {
+	return hs.hs.CountProtocols(c, q)
+}
+
+// List delegate to HeroService
+func (hs *HeroService) List(c context.Context, name string) ([]service.Hero, error) {
+	return hs.hs.List(c, name)
+}
+
+// GetByID delegate to HeroService
+func (hs *HeroService) GetByID(c context.Context, id int64) (*service.Hero, error) {
+	return hs.hs.GetByID(c, id)
+}
+
+// Add delegate to HeroService and publish the new Hero
+func (hs *HeroService) Add(c context.Context, n string) (*service.Hero, error) {
+	h, err := hs.hs.Add(c, n)
+	if err == nil {
+		hs.pub.Publish(c, service.NewProtocolf("Add", h.ID, "Add Hero: %v with Name: %s", h, n), h)
+	}
+	return h, err
+}
+
+// Update delegate to HeroService and publish the updated Hero
+func (hs *HeroService) Update(c context.Context, h service.Hero) (*service.Hero, error) {
+	hero, err := hs.hs.Update(c, h)
+	if err == nil {
+		hs.pub.Publish(c, service.NewProtocolf("Update", h.ID, "Update Hero: %v", h), hero)
+	}
+	return hero, err
+}
+
+// UpdatePosition delegate to HeroService and publish the moved Hero
+func (hs *HeroService) UpdatePosition(c context.Context, h service.Hero, pos int64) (*service.Hero, error) {
+	hero, err := hs.hs.UpdatePosition(c, h, pos)
+	if err == nil {
+		hs.pub.Publish(c, service.NewProtocolf("UpdatePosition", h.ID, "UpdatePosition Hero: %v with new Pos: %v", hero, pos), hero)
+	}
+	return hero, err
+}
+
+// Delete delegate to HeroService and publish the deleted Hero ID
+func (hs *HeroService) Delete(c context.Context, id int64) (*service.Hero, error) {
+	h, err := hs.hs.Delete(c, id)
+	if err == nil {
+		hs.pub.Publish(c, service.NewProtocolf("Delete", id, "Delete Hero: %v with ID: %v", h, id), nil)
+	}
+	return h, err
+}
+
+// Trash delegate to HeroService, if it is a TrashService
+func (hs *HeroService) Trash(c context.Context) ([]service.DeletedHero, error) {
+	ts, ok := hs.hs.(service.TrashService)
+	if !ok {
+		return nil, fmt.Errorf("trash is not supported by: %T", hs.hs)
+	}
+	return ts.Trash(c)
+}
+
+// Restore delegate to HeroService, if it is a TrashService, and publish the restored Hero
+func (hs *HeroService) Restore(c context.Context, id int64) (*service.Hero, error) {
+	ts, ok := hs.hs.(service.TrashService)
+	if !ok {
+		return nil, fmt.Errorf("trash is not supported by: %T", hs.hs)
+	}
+	h, err := ts.Restore(c, id)
+	if err == nil {
+		hs.pub.Publish(c, service.NewProtocolf("Restore", id, "Restore Hero: %v with ID: %v", h, id), h)
+	}
+	return h, err
+}
+
+// Purge delegate to HeroService, if it is a TrashService
+func (hs *HeroService) Purge(c context.Context, before time.Time) (int, error) {
+	ts, ok := hs.hs.(service.TrashService)
+	if !ok {
+		return 0, fmt.Errorf("trash is not supported by: %T", hs.hs)
+	}
+	return ts.Purge(c, before)
+}
+
+// Reset delegate to HeroService, if it is a ResetService, and publish the Reset (without Hero)
+func (hs *HeroService) Reset(c context.Context) error {
+	rs, ok := hs.hs.(service.ResetService)
+	if !ok {
+		return fmt.Errorf("reset is not supported by: %T", hs.hs)
+	}
+	err := rs.Reset(c)
+	if err == nil {
+		hs.pub.Publish(c, service.NewProtocol("Reset", 0, "Reset Heroes"), nil)
+	}
+	return err
+}
@@ -0,0 +1,107 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/events"
+)
+
+// readEvent read the next Server-Sent Event: the fields (id, event, data) without the comments
+func readEvent(t *testing.T, r *bufio.Reader) map[string]string {
+	e := map[string]string{}
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			t.Fatalf("No err expected: %v", err)
+		}
+		line = strings.TrimRight(line, "\n")
+		if line == "" {
+			if len(e) > 0 {
+				return e
+			}
+			continue
+		}
+		if strings.HasPrefix(line, ":") {
+			continue
+		}
+		kv := strings.SplitN(line, ": ", 2)
+		if len(kv) == 2 {
+			e[kv[0]] = kv[1]
+		}
+	}
+}
+
+func getEvents(t *testing.T, lastID string) (*http.Response, *bufio.Reader) {
+	req, _ := http.NewRequest("GET", server.URL+"/api/v2/heroes/events", nil)
+	req.Header.Set("Accept", "text/event-stream")
+	if lastID != "" {
+		req.Header.Set("Last-Event-ID", lastID)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK || resp.Header.Get("Content-Type") != "text/event-stream" {
+		t.Fatalf("unexpected response: %v %v", resp.StatusCode, resp.Header.Get("Content-Type"))
+	}
+	return resp, bufio.NewReader(resp.Body)
+}
+
+func TestHeroEvents(t *testing.T) {
+	svc := app.ProtocolHeroService
+	app.ProtocolHeroService = events.NewHeroService(db.NewMemService(), app.events)
+	defer func() { app.ProtocolHeroService = svc }()
+
+	resp, r := getEvents(t, "")
+	h, err := app.Add(context.TODO(), "Events")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	app.Delete(context.TODO(), h.ID)
+
+	add := readEvent(t, r)
+	resp.Body.Close()
+	payload := struct {
+		Protocol struct{ Action string }
+		Hero     *struct {
+			ID        int64
+			ScoreData *struct{ City string }
+		}
+	}{}
+	if err := json.Unmarshal([]byte(add["data"]), &payload); err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if payload.Protocol.Action != "Add" || payload.Hero == nil || payload.Hero.ID != h.ID || payload.Hero.ScoreData == nil {
+		t.Errorf("unexpected event (v2 Hero with scoreData): %v", add)
+	}
+
+	// resume after the Add
+	resp, r = getEvents(t, add["id"])
+	del := readEvent(t, r)
+	resp.Body.Close()
+	if !strings.Contains(del["data"], `"action":"Delete"`) || strings.Contains(del["data"], `"hero"`) {
+		t.Errorf("expect the Delete without Hero, got: %v", del)
+	}
+
+	// unknown ID: reset
+	resp, r = getEvents(t, "999999")
+	reset := readEvent(t, r)
+	resp.Body.Close()
+	if reset["event"] != "reset" {
+		t.Errorf("expect the reset event, got: %v", reset)
+	}
+
+	resp, err = http.Get(server.URL + "/api/heroes/events?lastEventId=x")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expect 400 for an invalid Last-Event-ID, got: %v", resp.StatusCode)
+	}
+}
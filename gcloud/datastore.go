@@ -11,10 +11,12 @@ import (
 )
 
 const (
-	// NAMESPACE  where are the Protocol are saved
+	// NAMESPACE  where are the Protocol of the default tenant are saved
 	NAMESPACE = "heroes"
 	// KIND of datastore
 	KIND = "Protocol"
+	// MaxTextScan is the max number of Protocols, which are scanned for a text query
+	MaxTextScan = 1000
 )
 
 // ProtocolsFromDatastore List all Protocol, there are saved in datastore
@@ -32,6 +34,100 @@ func ProtocolsFromDatastore(c context.Context) ([]service.Protocol, error) {
 	return p, nil
 }
 
+// QueryProtocolsFromDatastore returns the page of the Protocols (ordered by -Time), which match the query.
+// The datastore has no text search, with a Text only the newest MaxTextScan Protocols (of the other filters)
+// are scanned
+func QueryProtocolsFromDatastore(c context.Context, q service.ProtocolQuery) ([]service.Protocol, error) {
+	c = setNamespace(c)
+
+	if q.Text != "" {
+		enough := 0
+		if q.Offset >= 0 && q.Limit > 0 {
+			enough = q.Offset + q.Limit
+		}
+		p, err := scanText(c, q, enough)
+		if err != nil {
+			return p, err
+		}
+		return service.QueryProtocols(p, q), nil
+	}
+
+	dq := protocolQuery(q)
+	if q.Offset > 0 {
+		dq = dq.Offset(q.Offset)
+	}
+	if q.Limit > 0 {
+		dq = dq.Limit(q.Limit)
+	}
+
+	p := []service.Protocol{}
+	_, err := dq.GetAll(c, &p)
+	if err != nil {
+		return p, fmt.Errorf("Err by datastore.GetAll: %v", err)
+	}
+	return p, nil
+}
+
+// CountProtocolsFromDatastore returns the number of the Protocols, which match the query (without Offset and Limit)
+func CountProtocolsFromDatastore(c context.Context, q service.ProtocolQuery) (int, error) {
+	c = setNamespace(c)
+
+	if q.Text == "" {
+		count, err := protocolQuery(q).Count(c)
+		if err != nil {
+			return 0, fmt.Errorf("Err by datastore.Count: %v", err)
+		}
+		return count, nil
+	}
+
+	p, err := scanText(c, q, 0)
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// scanText returns the Protocols with the Text of the query, only the newest MaxTextScan Protocols are scanned,
+// the scan stops after enough (> 0) Protocols are found
+func scanText(c context.Context, q service.ProtocolQuery, enough int) ([]service.Protocol, error) {
+	it := protocolQuery(q).Limit(MaxTextScan).Run(c)
+
+	p := []service.Protocol{}
+	for enough <= 0 || len(p) < enough {
+		var protocol service.Protocol
+		_, err := it.Next(&protocol)
+		if err == datastore.Done {
+			break
+		}
+		if err != nil {
+			return p, fmt.Errorf("Err by datastore.Run: %v", err)
+		}
+		if q.Match(protocol) {
+			p = append(p, protocol)
+		}
+	}
+	return p, nil
+}
+
+// protocolQuery is the datastore query with the filters of the ProtocolQuery (without Text, Offset and Limit),
+// the composite indexes are in index.yaml
+func protocolQuery(q service.ProtocolQuery) *datastore.Query {
+	dq := datastore.NewQuery(KIND)
+	if q.Action != "" {
+		dq = dq.Filter("Action =", q.Action)
+	}
+	if q.HeroID != 0 {
+		dq = dq.Filter("HeroID =", q.HeroID)
+	}
+	if !q.From.IsZero() {
+		dq = dq.Filter("Time >=", q.From)
+	}
+	if !q.To.IsZero() {
+		dq = dq.Filter("Time <", q.To)
+	}
+	return dq.Order("-Time")
+}
+
 // GetByID get Protocol by the ID
 func GetByID(c context.Context, id int64) (*service.Protocol, error) {
 	c = setNamespace(c)
@@ -100,9 +196,17 @@ func Delete(c context.Context, id int64) (*service.Protocol, error) {
 }
 
 func setNamespace(c context.Context) context.Context {
-	c, err := appengine.Namespace(c, NAMESPACE)
+	c, err := appengine.Namespace(c, Namespace(service.TenantFromContext(c)))
 	if err != nil {
 		log.Errorf(c, fmt.Sprintf("Err by set Namespace: %v", err))
 	}
 	return c
 }
+
+// Namespace of the tenant: NAMESPACE for the default tenant, else NAMESPACE-tenant
+func Namespace(tenant string) string {
+	if tenant == "" {
+		return NAMESPACE
+	}
+	return NAMESPACE + "-" + tenant
+}
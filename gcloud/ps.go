@@ -17,6 +17,7 @@ import (
 	"context"
 	"encoding/base64"
 	"fmt"
+	"time"
 
 	"github.com/lima1909/goheroes-appengine/service"
 	"golang.org/x/oauth2/google"
@@ -33,11 +34,13 @@ const (
 // protocoll HeroService calls
 type HeroService struct {
 	hs service.ProtocolHeroService
+	// notify about the successful changes (optional)
+	publisher service.Publisher
 }
 
-// NewHeroService create a new instance
-func NewHeroService(hs service.ProtocolHeroService) *HeroService {
-	return &HeroService{hs: hs}
+// NewHeroService create a new instance, the Publisher (can be nil) is notified about the changes
+func NewHeroService(hs service.ProtocolHeroService, publisher service.Publisher) *HeroService {
+	return &HeroService{hs: hs, publisher: publisher}
 }
 
 // Protocols impl from ProtocolService
@@ -45,6 +48,16 @@ func (hs HeroService) Protocols(c context.Context) ([]service.Protocol, error) {
 	return ProtocolsFromDatastore(c)
 }
 
+// QueryProtocols impl from ProtocolService
+func (hs HeroService) QueryProtocols(c context.Context, q service.ProtocolQuery) ([]service.Protocol, error) {
+	return QueryProtocolsFromDatastore(c, q)
+}
+
+// CountProtocols impl from ProtocolService
+func (hs HeroService) CountProtocols(c context.Context, q service.ProtocolQuery) (int, error) {
+	return CountProtocolsFromDatastore(c, q)
+}
+
 // List protocoll list call
 func (hs HeroService) List(c context.Context, name string) ([]service.Hero, error) {
 	l, err := hs.hs.List(c, name)
@@ -66,30 +79,81 @@ func (hs HeroService) GetByID(c context.Context, id int64) (*service.Hero, error
 // Add delegate to HeroService
 func (hs HeroService) Add(c context.Context, n string) (*service.Hero, error) {
 	h, err := hs.hs.Add(c, n)
-	pub(c, service.NewProtocolf("Add", h.ID, "Add Hero: %v with Name: %s", h, n))
+	hs.changed(c, service.NewProtocolf("Add", h.ID, "Add Hero: %v with Name: %s", h, n), h, err)
 	return h, err
 }
 
 // Update delegate to HeroService
 func (hs HeroService) Update(c context.Context, h service.Hero) (*service.Hero, error) {
-	pub(c, service.NewProtocolf("Update", h.ID, "Update Hero: %v", h))
-	return hs.hs.Update(c, h)
+	hero, err := hs.hs.Update(c, h)
+	hs.changed(c, service.NewProtocolf("Update", h.ID, "Update Hero: %v", h), hero, err)
+	return hero, err
 }
 
 // UpdatePosition delegate to HeroService
 func (hs HeroService) UpdatePosition(c context.Context, h service.Hero, pos int64) (*service.Hero, error) {
 	hero, err := hs.hs.UpdatePosition(c, h, pos)
-	pub(c, service.NewProtocolf("UpdatePosition", h.ID, "UpdatePosition Hero: %v with new Pos: %v", hero, pos))
+	hs.changed(c, service.NewProtocolf("UpdatePosition", h.ID, "UpdatePosition Hero: %v with new Pos: %v", hero, pos), hero, err)
 	return hero, err
 }
 
 // Delete delegate to HeroService
 func (hs HeroService) Delete(c context.Context, id int64) (*service.Hero, error) {
 	h, err := hs.hs.Delete(c, id)
-	pub(c, service.NewProtocolf("Delete", id, "Delete Hero: %v with ID: %v", h, id))
+	hs.changed(c, service.NewProtocolf("Delete", id, "Delete Hero: %v with ID: %v", h, id), nil, err)
+	return h, err
+}
+
+// Trash delegate to HeroService, if it is a TrashService
+func (hs HeroService) Trash(c context.Context) ([]service.DeletedHero, error) {
+	ts, ok := hs.hs.(service.TrashService)
+	if !ok {
+		return nil, fmt.Errorf("trash is not supported by: %T", hs.hs)
+	}
+	return ts.Trash(c)
+}
+
+// Restore delegate to HeroService, if it is a TrashService
+func (hs HeroService) Restore(c context.Context, id int64) (*service.Hero, error) {
+	ts, ok := hs.hs.(service.TrashService)
+	if !ok {
+		return nil, fmt.Errorf("trash is not supported by: %T", hs.hs)
+	}
+	h, err := ts.Restore(c, id)
+	hs.changed(c, service.NewProtocolf("Restore", id, "Restore Hero: %v with ID: %v", h, id), h, err)
 	return h, err
 }
 
+// Purge delegate to HeroService, if it is a TrashService
+func (hs HeroService) Purge(c context.Context, before time.Time) (int, error) {
+	ts, ok := hs.hs.(service.TrashService)
+	if !ok {
+		return 0, fmt.Errorf("trash is not supported by: %T", hs.hs)
+	}
+	count, err := ts.Purge(c, before)
+	pub(c, service.NewProtocolf("Purge", 0, "Purge %v Heroes deleted before: %v", count, before))
+	return count, err
+}
+
+// Reset delegate to HeroService, if it is a ResetService
+func (hs HeroService) Reset(c context.Context) error {
+	rs, ok := hs.hs.(service.ResetService)
+	if !ok {
+		return fmt.Errorf("reset is not supported by: %T", hs.hs)
+	}
+	err := rs.Reset(c)
+	hs.changed(c, service.NewProtocolf("Reset", 0, "Reset Heroes with err: %v", err), nil, err)
+	return err
+}
+
+// changed publish the Protocol and notify the Publisher, if the change was successful
+func (hs HeroService) changed(c context.Context, p service.Protocol, h *service.Hero, err error) {
+	pub(c, p)
+	if err == nil && hs.publisher != nil {
+		hs.publisher.Publish(c, p, h)
+	}
+}
+
 func createSevice(c context.Context) (*pubsub.Service, error) {
 	hc, err := google.DefaultClient(c, pubsub.PubsubScope)
 	if err != nil {
@@ -105,6 +169,8 @@ func createSevice(c context.Context) (*pubsub.Service, error) {
 }
 
 func pub(c context.Context, p service.Protocol) {
+	p.Tenant = service.TenantFromContext(c)
+
 	svc, err := createSevice(c)
 	if err != nil {
 		log.Errorf(c, "Publish create service error: %v", err)
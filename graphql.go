@@ -0,0 +1,316 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/auth"
+	"github.com/lima1909/goheroes-appengine/graphql"
+	"github.com/lima1909/goheroes-appengine/policy"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// the GraphQL schema over the HeroService, ScoreService and ProtocolService:
+//
+//	type Query {
+//	  heroes(name: String, page: Int, size: Int): HeroPage!
+//	  hero(id: Int!): Hero
+//	}
+//	type Mutation {
+//	  addHero(name: String!): Hero!
+//	  updateHero(id: Int!, name: String, scoreName: String, city: String, country: String): Hero!
+//	  moveHero(id: Int!, pos: Int!): Hero!
+//	  deleteHero(id: Int!): Hero!
+//	}
+//	type HeroPage { items: [Hero!]!, total: Int!, page: Int!, size: Int! }
+//	type Hero { id: Int!, name: String!, scoreData: ScoreData!, score: Int, protocols: [Protocol!]! }
+//	type ScoreData { name: String!, city: String!, country: String! }
+//	type Protocol { action: String!, heroId: Int!, note: String!, time: String! }
+var graphQLSchema = newGraphQLSchema()
+
+func newGraphQLSchema() *graphql.Schema {
+	scoreData := &graphql.Object{Name: "ScoreData", Fields: graphql.Fields{
+		"name":    {Type: "String!", Resolve: func(p graphql.Params) (interface{}, error) { return p.Source.(service.ScoreData).Name, nil }},
+		"city":    {Type: "String!", Resolve: func(p graphql.Params) (interface{}, error) { return p.Source.(service.ScoreData).City, nil }},
+		"country": {Type: "String!", Resolve: func(p graphql.Params) (interface{}, error) { return p.Source.(service.ScoreData).Country, nil }},
+	}}
+
+	protocol := &graphql.Object{Name: "Protocol", Fields: graphql.Fields{
+		"action": {Type: "String!"},
+		"heroId": {Type: "Int!", Resolve: func(p graphql.Params) (interface{}, error) { return p.Source.(service.Protocol).HeroID, nil }},
+		"note":   {Type: "String!"},
+		"time": {Type: "String!", Resolve: func(p graphql.Params) (interface{}, error) {
+			return p.Source.(service.Protocol).Time.Format(time.RFC3339), nil
+		}},
+	}}
+
+	hero := &graphql.Object{Name: "Hero", Fields: graphql.Fields{
+		"id":   {Type: "Int!"},
+		"name": {Type: "String!"},
+		"scoreData": {Type: "ScoreData!", Resolve: func(p graphql.Params) (interface{}, error) {
+			return p.Source.(service.Hero).ScoreData, nil
+		}},
+		"score": {Type: "Int", Resolve: func(p graphql.Params) (interface{}, error) {
+			return loaderFromContext(p.Context).score(p.Context, p.Source.(service.Hero).ID)
+		}},
+		"protocols": {Type: "[Protocol!]!", Resolve: func(p graphql.Params) (interface{}, error) {
+			if err := allow(p.Context, policy.ReadProtocol); err != nil {
+				return nil, err
+			}
+			return loaderFromContext(p.Context).protocols(p.Context, p.Source.(service.Hero).ID)
+		}},
+	}}
+
+	heroPage := &graphql.Object{Name: "HeroPage", Fields: graphql.Fields{
+		"items": {Type: "[Hero!]!"},
+		"total": {Type: "Int!"},
+		"page":  {Type: "Int!"},
+		"size":  {Type: "Int!"},
+	}}
+
+	query := &graphql.Object{Name: "Query", Fields: graphql.Fields{
+		"heroes": {
+			Type: "HeroPage!",
+			Args: map[string]string{"name": "String", "page": "Int", "size": "Int"},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				name, _ := p.Args["name"].(string)
+				heroes, err := app.List(p.Context, name)
+				if err != nil {
+					return nil, err
+				}
+				total := len(heroes)
+				page, size, err := graphQLPagination(p.Args, total)
+				if err != nil {
+					return nil, err
+				}
+				heroes = pageOf(heroes, page, size)
+				loaderFromContext(p.Context).add(heroes...)
+				return map[string]interface{}{"items": heroes, "total": total, "page": page, "size": size}, nil
+			},
+		},
+		"hero": {
+			Type: "Hero",
+			Args: map[string]string{"id": "Int!"},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				h, err := app.GetByID(p.Context, p.Args["id"].(int64))
+				if err == service.ErrHeroNotFound {
+					return nil, nil
+				}
+				return loaded(p.Context, h, err)
+			},
+		},
+	}}
+
+	mutation := &graphql.Object{Name: "Mutation", Fields: graphql.Fields{
+		"addHero": {
+			Type: "Hero!",
+			Args: map[string]string{"name": "String!"},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				if err := allow(p.Context, policy.AddHero); err != nil {
+					return nil, err
+				}
+				h, err := app.Add(p.Context, p.Args["name"].(string))
+				return loaded(p.Context, h, err)
+			},
+		},
+		"updateHero": {
+			Type: "Hero!",
+			Args: map[string]string{"id": "Int!", "name": "String", "scoreName": "String", "city": "String", "country": "String"},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				if err := allow(p.Context, policy.UpdateHero); err != nil {
+					return nil, err
+				}
+				h, err := app.GetByID(p.Context, p.Args["id"].(int64))
+				if err != nil {
+					return nil, err
+				}
+				for arg, field := range map[string]*string{"name": &h.Name, "scoreName": &h.ScoreData.Name, "city": &h.ScoreData.City, "country": &h.ScoreData.Country} {
+					if v, ok := p.Args[arg].(string); ok {
+						*field = v
+					}
+				}
+				h, err = app.Update(p.Context, *h)
+				return loaded(p.Context, h, err)
+			},
+		},
+		"moveHero": {
+			Type: "Hero!",
+			Args: map[string]string{"id": "Int!", "pos": "Int!"},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				if err := allow(p.Context, policy.MoveHero); err != nil {
+					return nil, err
+				}
+				h, err := app.GetByID(p.Context, p.Args["id"].(int64))
+				if err != nil {
+					return nil, err
+				}
+				h, err = app.UpdatePosition(p.Context, *h, p.Args["pos"].(int64))
+				return loaded(p.Context, h, err)
+			},
+		},
+		"deleteHero": {
+			Type: "Hero!",
+			Args: map[string]string{"id": "Int!"},
+			Resolve: func(p graphql.Params) (interface{}, error) {
+				if err := allow(p.Context, policy.DeleteHero); err != nil {
+					return nil, err
+				}
+				h, err := app.Delete(p.Context, p.Args["id"].(int64))
+				return loaded(p.Context, h, err)
+			},
+		},
+	}}
+
+	s, err := graphql.NewSchema(query, mutation, hero, heroPage, scoreData, protocol)
+	if err != nil {
+		log.Fatalf("invalid GraphQL schema: %v", err)
+	}
+	return s
+}
+
+// graphQLPagination is the page and the size of the arguments (like the query of the Hero list)
+func graphQLPagination(args map[string]interface{}, total int) (int, int, error) {
+	page, hasPage := args["page"].(int64)
+	size, hasSize := args["size"].(int64)
+	if !hasPage && !hasSize {
+		if total == 0 {
+			return 1, 1, nil
+		}
+		return 1, total, nil
+	}
+
+	if !hasPage {
+		page = 1
+	} else if page < 1 {
+		return 0, 0, fmt.Errorf("invalid page: %v", page)
+	}
+	if !hasSize {
+		size = DefaultPageSize
+	} else if size < 1 {
+		return 0, 0, fmt.Errorf("invalid size: %v", size)
+	}
+	return checkPage(page, size, total)
+}
+
+// loaded add the Hero to the loader of the request (for the score batch) and returns it as value
+func loaded(c context.Context, h *service.Hero, err error) (interface{}, error) {
+	if err != nil {
+		return nil, err
+	}
+	loaderFromContext(c).add(*h)
+	return *h, nil
+}
+
+// allow check the Permission for the Principal of the request (the same roles as the policy.Middleware)
+func allow(c context.Context, perm policy.Permission) error {
+	if app.policy == nil {
+		return nil
+	}
+	roles := []string{policy.Viewer}
+	if principal := auth.PrincipalFromContext(c); principal != nil {
+		roles = append(roles, principal.Roles...)
+	}
+	if !app.policy.Allow(roles, perm) {
+		return fmt.Errorf("permission: %s required", perm)
+	}
+	return nil
+}
+
+// loader load the scores and the Protocols once per request: the scores of all resolved Heroes
+// are requested with one call of the ScoreService
+type loader struct {
+	// r is the request, for the rate limit of the scores
+	r *http.Request
+
+	mu      sync.Mutex
+	pending []service.Hero
+	scores  map[int64]int
+
+	protocolsOnce sync.Once
+	byHero        map[int64][]service.Protocol
+	protocolsErr  error
+}
+
+type loaderKey struct{}
+
+func newLoader(r *http.Request) *loader {
+	return &loader{r: r, scores: map[int64]int{}}
+}
+
+func loaderFromContext(c context.Context) *loader {
+	if l, ok := c.Value(loaderKey{}).(*loader); ok {
+		return l
+	}
+	return newLoader(nil)
+}
+
+// add the Heroes, which scores are loaded with the next batch
+func (l *loader) add(heroes ...service.Hero) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.pending = append(l.pending, heroes...)
+}
+
+// score of the Hero, the scores of all pending Heroes are loaded with one call
+// (every call takes a token of the rate limit class scores, like the route heroes.scores)
+func (l *loader) score(c context.Context, id int64) (interface{}, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if _, ok := l.scores[id]; !ok && len(l.pending) > 0 {
+		if app.limiter != nil && l.r != nil {
+			if _, err := app.limiter.AllowClass(l.r, "scores"); err != nil {
+				return nil, err
+			}
+		}
+		scores, err := app.Scores(c, heroBatch{heroes: l.pending})
+		if err != nil {
+			return nil, err
+		}
+		l.pending = nil
+		for k, v := range scores {
+			l.scores[k] = v
+		}
+	}
+	if s, ok := l.scores[id]; ok {
+		return s, nil
+	}
+	return nil, nil
+}
+
+// protocols of the Hero
+func (l *loader) protocols(c context.Context, id int64) ([]service.Protocol, error) {
+	l.protocolsOnce.Do(func() {
+		protocols, err := app.Protocols(c)
+		l.byHero, l.protocolsErr = map[int64][]service.Protocol{}, err
+		for _, p := range protocols {
+			l.byHero[p.HeroID] = append(l.byHero[p.HeroID], p)
+		}
+	})
+	if l.protocolsErr != nil {
+		return nil, l.protocolsErr
+	}
+	return append([]service.Protocol{}, l.byHero[id]...), nil
+}
+
+// heroBatch is the HeroService for the ScoreService with the Heroes of one batch
+type heroBatch struct {
+	service.HeroService
+	heroes []service.Hero
+}
+
+// List impl from HeroService, all Heroes of the batch
+func (b heroBatch) List(c context.Context, name string) ([]service.Hero, error) {
+	return b.heroes, nil
+}
+
+// graphQL serve the GraphQL requests, every request has his own loader
+func graphQL(w http.ResponseWriter, r *http.Request) {
+	graphQLSchema.Handler(func(r *http.Request) context.Context {
+		return context.WithValue(newContext(r), loaderKey{}, newLoader(r))
+	}).ServeHTTP(w, r)
+}
@@ -0,0 +1,590 @@
+package graphql
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+)
+
+// Request is the JSON body of a GraphQL request
+type Request struct {
+	Query         string                 `json:"query"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Extensions    map[string]interface{} `json:"extensions,omitempty"`
+}
+
+// Response of a GraphQL request, without Data, the request is invalid (no field is resolved)
+type Response struct {
+	Data   interface{} `json:"data,omitempty"`
+	Errors []*Error    `json:"errors,omitempty"`
+}
+
+// Error of a GraphQL request, the Path is the path of the field (field names and list indexes)
+type Error struct {
+	Message string        `json:"message"`
+	Path    []interface{} `json:"path,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return e.Message
+}
+
+// Do execute the request
+func (s *Schema) Do(c context.Context, req Request) *Response {
+	doc, err := Parse(req.Query)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	op, err := operation(doc, req.OperationName)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+	var root *Object
+	switch op.Type {
+	case "query":
+		root = s.Query
+	case "mutation":
+		root = s.Mutation
+	}
+	if root == nil {
+		return &Response{Errors: []*Error{{Message: "unsupported operation: " + op.Type}}}
+	}
+
+	vars, err := s.variables(op, req.Variables)
+	if err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	e := &executor{schema: s, doc: doc, vars: vars, c: c,
+		costs: map[string]cost{}, validating: map[string]bool{}, collected: map[string][]*Field{}}
+	if _, err := e.validate(root, op.Selections); err != nil {
+		return &Response{Errors: []*Error{{Message: err.Error()}}}
+	}
+
+	data, ok := e.object(root, nil, op.Selections, nil)
+	resp := &Response{Errors: e.errors}
+	if ok {
+		resp.Data = data
+	} else {
+		// a non-null error of a root field: "data": null
+		resp.Data = json.RawMessage("null")
+	}
+	return resp
+}
+
+// operation select the operation by name (empty: the only operation)
+func operation(doc *Document, name string) (*Operation, error) {
+	if name == "" {
+		if len(doc.Operations) != 1 {
+			return nil, fmt.Errorf("the operationName is required for a document with more than one operation")
+		}
+		return doc.Operations[0], nil
+	}
+	for _, op := range doc.Operations {
+		if op.Name == name {
+			return op, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown operation: %s", name)
+}
+
+// variables coerce the request variables with the types of the operation
+func (s *Schema) variables(op *Operation, values map[string]interface{}) (map[string]interface{}, error) {
+	vars := map[string]interface{}{}
+	for _, v := range op.Vars {
+		if !scalars[namedType(v.Type)] {
+			return nil, fmt.Errorf("unsupported type: %s of the variable: $%s", v.Type, v.Name)
+		}
+		value, ok := values[v.Name]
+		if !ok {
+			value = v.Default
+		}
+		coerced, err := coerce(v.Type, value, nil)
+		if err != nil {
+			return nil, fmt.Errorf("variable: $%s: %v", v.Name, err)
+		}
+		vars[v.Name] = coerced
+	}
+	return vars, nil
+}
+
+// coerce the value (a literal, a JSON value or a Variable) to the type
+func coerce(t string, value interface{}, vars map[string]interface{}) (interface{}, error) {
+	if v, ok := value.(Variable); ok {
+		var defined bool
+		if value, defined = vars[string(v)]; !defined {
+			return nil, fmt.Errorf("undefined variable: $%s", v)
+		}
+	}
+
+	t, required := nonNull(t)
+	if value == nil {
+		if required {
+			return nil, fmt.Errorf("a value of the type: %s! is required", t)
+		}
+		return nil, nil
+	}
+
+	if item, ok := listOf(t); ok {
+		values, ok := value.([]interface{})
+		if !ok {
+			// a single value is a list with one item
+			values = []interface{}{value}
+		}
+		list := make([]interface{}, len(values))
+		for i, v := range values {
+			c, err := coerce(item, v, vars)
+			if err != nil {
+				return nil, err
+			}
+			list[i] = c
+		}
+		return list, nil
+	}
+
+	switch t {
+	case "Int":
+		if i, ok := toInt(value); ok {
+			return i, nil
+		}
+	case "Float":
+		switch v := value.(type) {
+		case float64:
+			return v, nil
+		case int64:
+			return float64(v), nil
+		case json.Number:
+			if f, err := v.Float64(); err == nil {
+				return f, nil
+			}
+		}
+	case "String":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+	case "ID":
+		if s, ok := value.(string); ok {
+			return s, nil
+		}
+		if i, ok := toInt(value); ok {
+			return strconv.FormatInt(i, 10), nil
+		}
+	case "Boolean":
+		if b, ok := value.(bool); ok {
+			return b, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid value: %v for the type: %s", value, t)
+}
+
+func toInt(value interface{}) (int64, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case json.Number:
+		i, err := v.Int64()
+		return i, err == nil
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			return int64(v), true
+		}
+	}
+	return 0, false
+}
+
+// the limits of a query (with the expanded fragments), a larger query is rejected
+const (
+	MaxDepth  = 10
+	MaxFields = 1000
+)
+
+type executor struct {
+	schema *Schema
+	doc    *Document
+	vars   map[string]interface{}
+	c      context.Context
+	errors []*Error
+
+	// the named fragments are validated and collected only once
+	costs      map[string]cost
+	validating map[string]bool
+	collected  map[string][]*Field
+}
+
+// cost of a selection set: the depth and the number of the fields
+type cost struct {
+	depth, fields int
+}
+
+func (c *cost) add(field cost) error {
+	c.fields += 1 + field.fields
+	if field.depth+1 > c.depth {
+		c.depth = field.depth + 1
+	}
+	return c.check()
+}
+
+func (c *cost) merge(other cost) error {
+	c.fields += other.fields
+	if other.depth > c.depth {
+		c.depth = other.depth
+	}
+	return c.check()
+}
+
+func (c *cost) check() error {
+	if c.depth > MaxDepth {
+		return fmt.Errorf("the query is too deep (max: %d)", MaxDepth)
+	}
+	if c.fields > MaxFields {
+		return fmt.Errorf("the query has too many fields (max: %d)", MaxFields)
+	}
+	return nil
+}
+
+// validate the fields and the arguments of the selections, before a field is resolved
+func (e *executor) validate(o *Object, selections []Selection) (cost, error) {
+	c := cost{}
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *Field:
+			if s.Name == "__typename" {
+				if err := c.add(cost{}); err != nil {
+					return c, err
+				}
+				continue
+			}
+			def, ok := o.Fields[s.Name]
+			if !ok {
+				return c, fmt.Errorf("unknown field: %s of the type: %s", s.Name, o.Name)
+			}
+			for name, value := range s.Args {
+				t, ok := def.Args[name]
+				if !ok {
+					return c, fmt.Errorf("unknown argument: %s of the field: %s.%s", name, o.Name, s.Name)
+				}
+				if _, err := coerce(t, value, e.vars); err != nil {
+					return c, fmt.Errorf("argument: %s of the field: %s.%s: %v", name, o.Name, s.Name, err)
+				}
+			}
+			for name, t := range def.Args {
+				if _, ok := s.Args[name]; !ok {
+					if _, required := nonNull(t); required {
+						return c, fmt.Errorf("the argument: %s of the field: %s.%s is required", name, o.Name, s.Name)
+					}
+				}
+			}
+
+			child, isObject := e.schema.objects[namedType(def.Type)]
+			if isObject && len(s.Selections) == 0 {
+				return c, fmt.Errorf("the field: %s.%s of the type: %s needs a selection", o.Name, s.Name, def.Type)
+			} else if !isObject && len(s.Selections) > 0 {
+				return c, fmt.Errorf("the field: %s.%s of the type: %s has no fields", o.Name, s.Name, def.Type)
+			}
+			field := cost{}
+			if isObject {
+				var err error
+				if field, err = e.validate(child, s.Selections); err != nil {
+					return c, err
+				}
+			}
+			if err := c.add(field); err != nil {
+				return c, err
+			}
+		case *FragmentSpread:
+			f, err := e.validateSpread(s.Name)
+			if err != nil {
+				return c, err
+			}
+			if err := c.merge(f); err != nil {
+				return c, err
+			}
+		case *InlineFragment:
+			on := s.On
+			if on == "" {
+				on = o.Name
+			}
+			f, err := e.validateFragment(on, s.Selections)
+			if err != nil {
+				return c, err
+			}
+			if err := c.merge(f); err != nil {
+				return c, err
+			}
+		}
+	}
+	return c, nil
+}
+
+// validateSpread validate the named fragment only once
+func (e *executor) validateSpread(name string) (cost, error) {
+	if c, ok := e.costs[name]; ok {
+		return c, nil
+	}
+	f, ok := e.doc.Fragments[name]
+	if !ok {
+		return cost{}, fmt.Errorf("unknown fragment: %s", name)
+	}
+	if e.validating[name] {
+		return cost{}, fmt.Errorf("the fragment: %s spreads itself", name)
+	}
+	e.validating[name] = true
+	c, err := e.validateFragment(f.On, f.Selections)
+	delete(e.validating, name)
+	if err != nil {
+		return c, err
+	}
+	e.costs[name] = c
+	return c, nil
+}
+
+func (e *executor) validateFragment(on string, selections []Selection) (cost, error) {
+	o, ok := e.schema.objects[on]
+	if !ok {
+		return cost{}, fmt.Errorf("unknown type: %s of the fragment", on)
+	}
+	return e.validate(o, selections)
+}
+
+// collect the fields of the selections for the Object (with fragments and directives)
+func (e *executor) collect(o *Object, selections []Selection, fields *[]*Field, keys map[string]int) {
+	for _, sel := range selections {
+		switch s := sel.(type) {
+		case *Field:
+			if e.included(s.Directives) {
+				add(s, fields, keys)
+			}
+		case *FragmentSpread:
+			f := e.doc.Fragments[s.Name]
+			if e.included(s.Directives) && f.On == o.Name {
+				for _, field := range e.collectFragment(o, f) {
+					add(field, fields, keys)
+				}
+			}
+		case *InlineFragment:
+			if e.included(s.Directives) && (s.On == "" || s.On == o.Name) {
+				e.collect(o, s.Selections, fields, keys)
+			}
+		}
+	}
+}
+
+// collectFragment collect the fields of the named fragment only once
+func (e *executor) collectFragment(o *Object, f *Fragment) []*Field {
+	if fields, ok := e.collected[f.Name]; ok {
+		return fields
+	}
+	fields := []*Field{}
+	e.collect(o, f.Selections, &fields, map[string]int{})
+	e.collected[f.Name] = fields
+	return fields
+}
+
+// add the field, the selections of a field with the same response key are merged
+func add(f *Field, fields *[]*Field, keys map[string]int) {
+	if i, ok := keys[f.Key()]; ok {
+		merged := *(*fields)[i]
+		merged.Selections = append(append([]Selection{}, merged.Selections...), f.Selections...)
+		(*fields)[i] = &merged
+		return
+	}
+	keys[f.Key()] = len(*fields)
+	*fields = append(*fields, f)
+}
+
+// included evaluate the directives @skip(if:) and @include(if:)
+func (e *executor) included(directives []Directive) bool {
+	for _, d := range directives {
+		cond, _ := coerce("Boolean!", d.Args["if"], e.vars)
+		switch d.Name {
+		case "skip":
+			if cond == true {
+				return false
+			}
+		case "include":
+			if cond != true {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// object resolve the fields of the Object, false if a non-null field is null
+func (e *executor) object(o *Object, source interface{}, selections []Selection, path []interface{}) (interface{}, bool) {
+	fields := []*Field{}
+	e.collect(o, selections, &fields, map[string]int{})
+
+	result := &orderedMap{values: map[string]interface{}{}}
+	for _, f := range fields {
+		fieldPath := append(append([]interface{}{}, path...), f.Key())
+		if f.Name == "__typename" {
+			result.set(f.Key(), o.Name)
+			continue
+		}
+
+		def := o.Fields[f.Name]
+		args := map[string]interface{}{}
+		for name, t := range def.Args {
+			value, ok := f.Args[name]
+			if !ok {
+				continue
+			}
+			args[name], _ = coerce(t, value, e.vars)
+		}
+
+		p := Params{Context: e.c, Source: source, Args: args}
+		var value interface{}
+		var err error
+		if def.Resolve != nil {
+			value, err = def.Resolve(p)
+		} else {
+			value, err = defaultResolve(p, f.Name)
+		}
+		if err != nil {
+			e.errors = append(e.errors, &Error{Message: err.Error(), Path: fieldPath})
+			value = nil
+		}
+
+		completed, ok := e.complete(def.Type, value, f.Selections, fieldPath, err != nil)
+		if !ok {
+			return nil, false
+		}
+		result.set(f.Key(), completed)
+	}
+	return result, true
+}
+
+// complete the value with the type, false if the value is null for a non-null type
+func (e *executor) complete(t string, value interface{}, selections []Selection, path []interface{}, failed bool) (interface{}, bool) {
+	t, required := nonNull(t)
+	if isNil(value) {
+		if required && !failed {
+			e.errors = append(e.errors, &Error{Message: "null for the non-null type: " + t + "!", Path: path})
+		}
+		return nil, !required
+	}
+
+	if item, ok := listOf(t); ok {
+		v := reflect.ValueOf(value)
+		if v.Kind() != reflect.Slice && v.Kind() != reflect.Array {
+			e.errors = append(e.errors, &Error{Message: fmt.Sprintf("list expected, got: %T", value), Path: path})
+			return nil, !required
+		}
+		list := make([]interface{}, v.Len())
+		for i := range list {
+			c, ok := e.complete(item, v.Index(i).Interface(), selections, append(append([]interface{}{}, path...), i), false)
+			if !ok {
+				return nil, !required
+			}
+			list[i] = c
+		}
+		return list, true
+	}
+
+	if o, ok := e.schema.objects[t]; ok {
+		obj, ok := e.object(o, value, selections, path)
+		if !ok {
+			return nil, !required
+		}
+		return obj, true
+	}
+
+	s, err := serialize(t, value)
+	if err != nil {
+		e.errors = append(e.errors, &Error{Message: err.Error(), Path: path})
+		return nil, !required
+	}
+	return s, true
+}
+
+func isNil(value interface{}) bool {
+	if value == nil {
+		return true
+	}
+	v := reflect.ValueOf(value)
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		return v.IsNil()
+	}
+	return false
+}
+
+// serialize the value of a scalar type
+func serialize(t string, value interface{}) (interface{}, error) {
+	v := reflect.Indirect(reflect.ValueOf(value))
+	switch t {
+	case "Int":
+		switch v.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return v.Int(), nil
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32:
+			return int64(v.Uint()), nil
+		}
+	case "Float":
+		switch v.Kind() {
+		case reflect.Float32, reflect.Float64:
+			return v.Float(), nil
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			return float64(v.Int()), nil
+		}
+	case "String", "ID":
+		if v.Kind() == reflect.String {
+			return v.String(), nil
+		}
+		if s, ok := value.(fmt.Stringer); ok {
+			return s.String(), nil
+		}
+		if t == "ID" {
+			switch v.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				return strconv.FormatInt(v.Int(), 10), nil
+			}
+		}
+	case "Boolean":
+		if v.Kind() == reflect.Bool {
+			return v.Bool(), nil
+		}
+	}
+	return nil, fmt.Errorf("can not serialize: %v (%T) as %s", value, value, t)
+}
+
+// orderedMap is a JSON object with the fields in the order of the query
+type orderedMap struct {
+	keys   []string
+	values map[string]interface{}
+}
+
+func (m *orderedMap) set(key string, value interface{}) {
+	if _, ok := m.values[key]; !ok {
+		m.keys = append(m.keys, key)
+	}
+	m.values[key] = value
+}
+
+// MarshalJSON impl from json.Marshaler
+func (m *orderedMap) MarshalJSON() ([]byte, error) {
+	buf := &bytes.Buffer{}
+	buf.WriteByte('{')
+	for i, k := range m.keys {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		key, _ := json.Marshal(k)
+		buf.Write(key)
+		buf.WriteByte(':')
+		value, err := json.Marshal(m.values[k])
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,123 @@
+// Package graphql is a small GraphQL executor: queries and mutations with variables, aliases,
+// fragments and the directives @include and @skip, over a Schema of Objects with resolve functions.
+// The input types are the scalars (Int, Float, String, Boolean, ID) and lists of them,
+// introspection (__schema, __type) is not supported, only __typename.
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ResolveFunc resolve the value of a Field
+type ResolveFunc func(p Params) (interface{}, error)
+
+// Params of a ResolveFunc
+type Params struct {
+	Context context.Context
+	// Source is the value of the parent object
+	Source interface{}
+	// Args are the coerced arguments: int64, float64, string, bool, nil or []interface{}
+	Args map[string]interface{}
+}
+
+// Object is an object type of the Schema
+type Object struct {
+	Name   string
+	Fields Fields
+}
+
+// Fields of an Object by name
+type Fields map[string]*FieldDef
+
+// FieldDef is the definition of a field of an Object
+type FieldDef struct {
+	// Type of the field, e.g. Int, String!, [Hero!]!
+	Type string
+	// Args are the argument names with the types
+	Args map[string]string
+	// Resolve the value (default: the value of the map key or the struct field with the json name)
+	Resolve ResolveFunc
+}
+
+// Schema with the root Objects Query and Mutation (optional)
+type Schema struct {
+	Query    *Object
+	Mutation *Object
+	objects  map[string]*Object
+}
+
+var scalars = map[string]bool{"Int": true, "Float": true, "String": true, "Boolean": true, "ID": true}
+
+// NewSchema create a Schema, objects are all Objects, which are used as field types
+func NewSchema(query, mutation *Object, objects ...*Object) (*Schema, error) {
+	s := &Schema{Query: query, Mutation: mutation, objects: map[string]*Object{}}
+	for _, o := range append([]*Object{query, mutation}, objects...) {
+		if o != nil {
+			s.objects[o.Name] = o
+		}
+	}
+
+	for _, o := range s.objects {
+		for name, f := range o.Fields {
+			if t := namedType(f.Type); !scalars[t] && s.objects[t] == nil {
+				return nil, fmt.Errorf("unknown type: %s of the field: %s.%s", t, o.Name, name)
+			}
+			for arg, t := range f.Args {
+				if !scalars[namedType(t)] {
+					return nil, fmt.Errorf("unsupported input type: %s of the argument: %s.%s(%s)", t, o.Name, name, arg)
+				}
+			}
+		}
+	}
+	return s, nil
+}
+
+// namedType returns the type without list and non-null, e.g. [Hero!]! is Hero
+func namedType(t string) string {
+	return strings.Trim(t, "[]!")
+}
+
+// nonNull returns the type without the non-null and true, if the type is non-null
+func nonNull(t string) (string, bool) {
+	if strings.HasSuffix(t, "!") {
+		return strings.TrimSuffix(t, "!"), true
+	}
+	return t, false
+}
+
+// listOf returns the type of the items and true, if the type is a list
+func listOf(t string) (string, bool) {
+	if strings.HasPrefix(t, "[") && strings.HasSuffix(t, "]") {
+		return t[1 : len(t)-1], true
+	}
+	return t, false
+}
+
+// defaultResolve returns the value of the map key or the struct field with the json name
+func defaultResolve(p Params, name string) (interface{}, error) {
+	if m, ok := p.Source.(map[string]interface{}); ok {
+		return m[name], nil
+	}
+
+	v := reflect.ValueOf(p.Source)
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("can not resolve the field: %s of: %T", name, p.Source)
+	}
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		tag := strings.Split(t.Field(i).Tag.Get("json"), ",")[0]
+		if tag == name || (tag == "" && strings.EqualFold(t.Field(i).Name, name)) {
+			return v.Field(i).Interface(), nil
+		}
+	}
+	return nil, fmt.Errorf("can not resolve the field: %s of: %T", name, p.Source)
+}
@@ -0,0 +1,204 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+)
+
+type item struct {
+	ID    int64  `json:"id"`
+	Name  string `json:"name"`
+	Price float64
+}
+
+func testSchema(t *testing.T) *Schema {
+	items := []item{{1, "a", 1.5}, {2, "b", 2}, {3, "c", 0}}
+
+	itemType := &Object{Name: "Item", Fields: Fields{
+		"id":    {Type: "ID!"},
+		"name":  {Type: "String!"},
+		"price": {Type: "Float"},
+		"fail": {Type: "String", Resolve: func(p Params) (interface{}, error) {
+			return nil, errors.New("failed")
+		}},
+		"failRequired": {Type: "String!", Resolve: func(p Params) (interface{}, error) {
+			return nil, errors.New("failed")
+		}},
+	}}
+	query := &Object{Name: "Query", Fields: Fields{
+		"items": {Type: "[Item!]!", Args: map[string]string{"first": "Int", "ids": "[ID!]"}, Resolve: func(p Params) (interface{}, error) {
+			result := items
+			if ids, ok := p.Args["ids"].([]interface{}); ok {
+				result = []item{}
+				for _, i := range items {
+					for _, id := range ids {
+						if id == i.Name || id == string(rune('0'+i.ID)) {
+							result = append(result, i)
+						}
+					}
+				}
+			}
+			if first, ok := p.Args["first"].(int64); ok && int(first) < len(result) {
+				result = result[:first]
+			}
+			return result, nil
+		}},
+		"item": {Type: "Item", Args: map[string]string{"name": "String!"}, Resolve: func(p Params) (interface{}, error) {
+			for _, i := range items {
+				if i.Name == p.Args["name"] {
+					return &i, nil
+				}
+			}
+			return nil, nil
+		}},
+	}}
+	mutation := &Object{Name: "Mutation", Fields: Fields{
+		"add": {Type: "Item!", Args: map[string]string{"name": "String!"}, Resolve: func(p Params) (interface{}, error) {
+			i := item{ID: int64(len(items) + 1), Name: p.Args["name"].(string)}
+			items = append(items, i)
+			return i, nil
+		}},
+	}}
+
+	s, err := NewSchema(query, mutation, itemType)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	return s
+}
+
+func do(s *Schema, query string, vars map[string]interface{}) string {
+	b, _ := json.Marshal(s.Do(context.Background(), Request{Query: query, Variables: vars}))
+	return string(b)
+}
+
+func TestDo(t *testing.T) {
+	s := testSchema(t)
+
+	for _, tc := range []struct {
+		query    string
+		vars     map[string]interface{}
+		expected string
+	}{
+		{`{ items { id name } }`, nil, `{"data":{"items":[{"id":"1","name":"a"},{"id":"2","name":"b"},{"id":"3","name":"c"}]}}`},
+		{`query { items(first: 1) { name, id, __typename } }`, nil, `{"data":{"items":[{"name":"a","id":"1","__typename":"Item"}]}}`},
+		{`query Q($n: Int = 2) { items(first: $n) { n: name } }`, nil, `{"data":{"items":[{"n":"a"},{"n":"b"}]}}`},
+		{`query Q($n: Int) { items(first: $n) { name } }`, map[string]interface{}{"n": 1.0}, `{"data":{"items":[{"name":"a"}]}}`},
+		{`{ items(ids: [3, "a"]) { name } }`, nil, `{"data":{"items":[{"name":"a"},{"name":"c"}]}}`},
+		{`{ items(ids: 2) { name } }`, nil, `{"data":{"items":[{"name":"b"}]}}`},
+		{`{ item(name: "b") { ...f } } fragment f on Item { id price }`, nil, `{"data":{"item":{"id":"2","price":2}}}`},
+		{`{ item(name: "a") { ... on Item { name } ... @skip(if: true) { id } price } }`, nil, `{"data":{"item":{"name":"a","price":1.5}}}`},
+		{`query ($i: Boolean!) { item(name: "a") { name @include(if: $i) id } }`, map[string]interface{}{"i": false}, `{"data":{"item":{"id":"1"}}}`},
+		{`{ x: item(name: "x") { name } }`, nil, `{"data":{"x":null}}`},
+		{`{ item(name: "c") { name fail } }`, nil, `{"data":{"item":{"name":"c","fail":null}},"errors":[{"message":"failed","path":["item","fail"]}]}`},
+		{`{ item(name: "c") { name failRequired } }`, nil, `{"data":{"item":null},"errors":[{"message":"failed","path":["item","failRequired"]}]}`},
+		{`{ items { failRequired } }`, nil, `{"data":null,"errors":[{"message":"failed","path":["items",0,"failRequired"]}]}`},
+		{`mutation { add(name: "d") { id name } }`, nil, `{"data":{"add":{"id":"4","name":"d"}}}`},
+		// invalid requests
+		{`{ items { id `, nil, `{"errors":[{"message":"syntax error: name expected, got: end of document (line: 1, column: 14)"}]}`},
+		{`{ items { unknown } }`, nil, `{"errors":[{"message":"unknown field: unknown of the type: Item"}]}`},
+		{`{ items }`, nil, `{"errors":[{"message":"the field: Query.items of the type: [Item!]! needs a selection"}]}`},
+		{`{ item { name } }`, nil, `{"errors":[{"message":"the argument: name of the field: Query.item is required"}]}`},
+		{`{ items(first: "1") { name } }`, nil, `{"errors":[{"message":"argument: first of the field: Query.items: invalid value: 1 for the type: Int"}]}`},
+		{`{ items(last: 1) { name } }`, nil, `{"errors":[{"message":"unknown argument: last of the field: Query.items"}]}`},
+		{`query ($n: Int!) { items(first: $n) { name } }`, nil, `{"errors":[{"message":"variable: $n: a value of the type: Int! is required"}]}`},
+		{`{ items(first: $n) { name } }`, nil, `{"errors":[{"message":"argument: first of the field: Query.items: undefined variable: $n"}]}`},
+		{`{ items { ...f } } fragment f on Item { ...f }`, nil, `{"errors":[{"message":"the fragment: f spreads itself"}]}`},
+		{`query A { items { id } } query B { items { name } }`, nil, `{"errors":[{"message":"the operationName is required for a document with more than one operation"}]}`},
+		{`subscription { items { id } }`, nil, `{"errors":[{"message":"unsupported operation: subscription"}]}`},
+	} {
+		if got := do(s, tc.query, tc.vars); got != tc.expected {
+			t.Errorf("%v:\n%v !=\n%v", tc.query, tc.expected, got)
+		}
+	}
+}
+
+func TestParseStrings(t *testing.T) {
+	doc, err := Parse(`{ item(name: "a\"b\u00e4", other: """ block "" """) { id } } # comment`)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	args := doc.Operations[0].Selections[0].(*Field).Args
+	if args["name"] != `a"bä` || args["other"] != `block ""` {
+		t.Errorf("unexpected args: %v", args)
+	}
+}
+
+func TestNewSchemaUnknownType(t *testing.T) {
+	_, err := NewSchema(&Object{Name: "Query", Fields: Fields{"x": {Type: "[X]"}}}, nil)
+	if err == nil || err.Error() != "unknown type: X of the field: Query.x" {
+		t.Errorf("expect err for the unknown type, got: %v", err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := testSchema(t).Handler(func(r *http.Request) context.Context { return r.Context() })
+
+	for _, tc := range []struct {
+		method, query, body string
+		status              int
+	}{
+		{"POST", "", `{"query": "query ($n: Int) { items(first: $n) { name } }", "variables": {"n": 1}}`, http.StatusOK},
+		{"POST", "", `{"query": "{ items { name } }", "unknown": 1}`, http.StatusBadRequest},
+		{"POST", "", `{"query": "{ items { unknown } }"}`, http.StatusBadRequest},
+		{"GET", "?query=" + url.QueryEscape("{ items { name } }"), "", http.StatusOK},
+		{"GET", "?query=" + url.QueryEscape(`mutation { add(name: "x") { id } }`), "", http.StatusMethodNotAllowed},
+		{"PUT", "", "", http.StatusMethodNotAllowed},
+	} {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(tc.method, "/graphql"+tc.query, strings.NewReader(tc.body)))
+		if w.Code != tc.status {
+			t.Errorf("%v %v: %v != %v (%v)", tc.method, tc.query, tc.status, w.Code, w.Body.String())
+		}
+	}
+}
+
+func TestLimits(t *testing.T) {
+	s := testSchema(t)
+
+	// every fragment spreads the next one twice: 2^30 fields, if the fragments are expanded again and again
+	query := `{ items { ...f0 } } fragment f30 on Item { id name }`
+	for i := 0; i < 30; i++ {
+		query += fmt.Sprintf(" fragment f%d on Item { ...f%d ... on Item { ...f%d } }", i, i+1, i+1)
+	}
+	start := time.Now()
+	if got, expected := do(s, query, nil), fmt.Sprintf(`{"errors":[{"message":"the query has too many fields (max: %d)"}]}`, MaxFields); got != expected {
+		t.Errorf("%v != %v", expected, got)
+	}
+	if d := time.Since(start); d > time.Second {
+		t.Errorf("the query is validated too slow: %v", d)
+	}
+
+	// the fragment is collected once
+	if got := do(s, `{ item(name: "a") { ...f ...f } } fragment f on Item { ...g ...g } fragment g on Item { id }`, nil); got != `{"data":{"item":{"id":"1"}}}` {
+		t.Errorf("unexpected result: %v", got)
+	}
+
+	node := &Object{Name: "Node", Fields: Fields{"id": {Type: "ID"}}}
+	node.Fields["child"] = &FieldDef{Type: "Node", Resolve: func(p Params) (interface{}, error) {
+		return map[string]interface{}{"id": 1}, nil
+	}}
+	deep, err := NewSchema(&Object{Name: "Query", Fields: Fields{"node": node.Fields["child"]}}, nil, node)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	query = "{ node { id } }"
+	for i := 2; i < MaxDepth; i++ {
+		query = strings.Replace(query, "{ id }", "{ child { id } }", 1)
+	}
+	if got := do(deep, query, nil); !strings.HasPrefix(got, `{"data":`) {
+		t.Errorf("expect the data for the depth: %d, got: %v", MaxDepth, got)
+	}
+	query = strings.Replace(query, "{ id }", "{ child { id } }", 1)
+	if got, expected := do(deep, query, nil), fmt.Sprintf(`{"errors":[{"message":"the query is too deep (max: %d)"}]}`, MaxDepth); got != expected {
+		t.Errorf("%v != %v", expected, got)
+	}
+}
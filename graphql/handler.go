@@ -0,0 +1,61 @@
+package graphql
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+
+	"github.com/lima1909/goheroes-appengine/body"
+)
+
+// Handler serve the GraphQL requests: GET (query, variables, operationName in the URL, only queries)
+// and POST (the Request as JSON), newContext create the Context of the resolve functions
+func (s *Schema) Handler(newContext func(r *http.Request) context.Context) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		req := Request{}
+		switch r.Method {
+		case "GET":
+			q := r.URL.Query()
+			req.Query, req.OperationName = q.Get("query"), q.Get("operationName")
+			if vars := q.Get("variables"); vars != "" {
+				dec := json.NewDecoder(strings.NewReader(vars))
+				dec.UseNumber()
+				if err := dec.Decode(&req.Variables); err != nil {
+					http.Error(w, "invalid variables: "+err.Error(), http.StatusBadRequest)
+					return
+				}
+			}
+			if doc, err := Parse(req.Query); err == nil {
+				if op, err := operation(doc, req.OperationName); err == nil && op.Type != "query" {
+					w.Header().Set("Allow", "POST")
+					http.Error(w, "a "+op.Type+" is only allowed with POST", http.StatusMethodNotAllowed)
+					return
+				}
+			}
+		case "POST":
+			if err := body.DecodeJSON(r, &req); err != nil {
+				http.Error(w, err.Error(), body.Status(err))
+				return
+			}
+		default:
+			w.Header().Set("Allow", "GET, POST")
+			http.Error(w, "invalid method: "+r.Method, http.StatusMethodNotAllowed)
+			return
+		}
+
+		resp := s.Do(newContext(r), req)
+		b, err := json.Marshal(resp)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json; charset=utf-8")
+		if resp.Data == nil {
+			// the request is invalid, no field is resolved
+			w.WriteHeader(http.StatusBadRequest)
+		}
+		w.Write(b)
+	})
+}
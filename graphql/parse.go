@@ -0,0 +1,485 @@
+package graphql
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Document is a parsed GraphQL request document
+type Document struct {
+	Operations []*Operation
+	Fragments  map[string]*Fragment
+}
+
+// Operation is a query or a mutation
+type Operation struct {
+	// Type is query or mutation
+	Type       string
+	Name       string
+	Vars       []VarDef
+	Selections []Selection
+}
+
+// VarDef is the definition of a variable: $name: Type = default
+type VarDef struct {
+	Name    string
+	Type    string
+	Default interface{}
+}
+
+// Selection is a *Field, *FragmentSpread or *InlineFragment
+type Selection interface{}
+
+// Field of a selection set
+type Field struct {
+	Alias      string
+	Name       string
+	Args       map[string]interface{}
+	Directives []Directive
+	Selections []Selection
+}
+
+// Key is the name of the field in the response (the alias or the name)
+func (f *Field) Key() string {
+	if f.Alias != "" {
+		return f.Alias
+	}
+	return f.Name
+}
+
+// FragmentSpread is a reference to a named Fragment: ...Name
+type FragmentSpread struct {
+	Name       string
+	Directives []Directive
+}
+
+// InlineFragment is a selection set with a type condition: ... on Type { }
+type InlineFragment struct {
+	On         string
+	Directives []Directive
+	Selections []Selection
+}
+
+// Fragment is a named selection set: fragment Name on Type { }
+type Fragment struct {
+	Name       string
+	On         string
+	Selections []Selection
+}
+
+// Directive of a selection, e.g. @include(if: $var)
+type Directive struct {
+	Name string
+	Args map[string]interface{}
+}
+
+// Variable is a reference to a variable in a value: $name
+type Variable string
+
+// Enum is an enum value in a value
+type Enum string
+
+// token kinds
+const (
+	tokEOF = iota
+	tokPunct
+	tokName
+	tokInt
+	tokFloat
+	tokString
+)
+
+type token struct {
+	kind  int
+	value string
+	pos   int
+}
+
+// SyntaxError is an error of the parser
+type SyntaxError struct {
+	Message string
+	Line    int
+	Column  int
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error: %s (line: %d, column: %d)", e.Message, e.Line, e.Column)
+}
+
+type parser struct {
+	src string
+	pos int
+	tok token
+}
+
+// Parse the GraphQL document
+func Parse(src string) (doc *Document, err error) {
+	p := &parser{src: src}
+	defer func() {
+		if r := recover(); r != nil {
+			se, ok := r.(*SyntaxError)
+			if !ok {
+				panic(r)
+			}
+			err = se
+		}
+	}()
+
+	p.next()
+	doc = &Document{Fragments: map[string]*Fragment{}}
+	for p.tok.kind != tokEOF {
+		if p.peek("{") {
+			doc.Operations = append(doc.Operations, &Operation{Type: "query", Selections: p.selectionSet()})
+			continue
+		}
+
+		switch name := p.name(); name {
+		case "query", "mutation", "subscription":
+			doc.Operations = append(doc.Operations, p.operation(name))
+		case "fragment":
+			f := &Fragment{Name: p.name()}
+			if f.Name == "on" {
+				p.fail("invalid fragment name: on")
+			}
+			p.keyword("on")
+			f.On = p.name()
+			f.Selections = p.selectionSet()
+			if _, ok := doc.Fragments[f.Name]; ok {
+				p.fail("duplicate fragment: " + f.Name)
+			}
+			doc.Fragments[f.Name] = f
+		default:
+			p.fail("unexpected: " + name)
+		}
+	}
+	if len(doc.Operations) == 0 {
+		p.fail("no operation")
+	}
+	return doc, nil
+}
+
+func (p *parser) operation(typ string) *Operation {
+	op := &Operation{Type: typ}
+	if p.tok.kind == tokName {
+		op.Name = p.name()
+	}
+	if p.skip("(") {
+		for !p.skip(")") {
+			p.expect("$")
+			v := VarDef{Name: p.name()}
+			p.expect(":")
+			v.Type = p.typeRef()
+			if p.skip("=") {
+				v.Default = p.value(true)
+			}
+			op.Vars = append(op.Vars, v)
+		}
+	}
+	p.directives()
+	op.Selections = p.selectionSet()
+	return op
+}
+
+func (p *parser) typeRef() string {
+	var t string
+	if p.skip("[") {
+		t = "[" + p.typeRef() + "]"
+		p.expect("]")
+	} else {
+		t = p.name()
+	}
+	if p.skip("!") {
+		t += "!"
+	}
+	return t
+}
+
+func (p *parser) selectionSet() []Selection {
+	p.expect("{")
+	selections := []Selection{}
+	for !p.skip("}") {
+		if p.skip("...") {
+			if p.tok.kind == tokName && p.tok.value != "on" {
+				selections = append(selections, &FragmentSpread{Name: p.name(), Directives: p.directives()})
+				continue
+			}
+			f := &InlineFragment{}
+			if p.tok.kind == tokName {
+				p.keyword("on")
+				f.On = p.name()
+			}
+			f.Directives = p.directives()
+			f.Selections = p.selectionSet()
+			selections = append(selections, f)
+			continue
+		}
+
+		f := &Field{Name: p.name()}
+		if p.skip(":") {
+			f.Alias, f.Name = f.Name, p.name()
+		}
+		f.Args = p.arguments(false)
+		f.Directives = p.directives()
+		if p.peek("{") {
+			f.Selections = p.selectionSet()
+		}
+		selections = append(selections, f)
+	}
+	if len(selections) == 0 {
+		p.fail("empty selection set")
+	}
+	return selections
+}
+
+func (p *parser) arguments(constant bool) map[string]interface{} {
+	args := map[string]interface{}{}
+	if p.skip("(") {
+		for !p.skip(")") {
+			name := p.name()
+			p.expect(":")
+			if _, ok := args[name]; ok {
+				p.fail("duplicate argument: " + name)
+			}
+			args[name] = p.value(constant)
+		}
+	}
+	return args
+}
+
+func (p *parser) directives() []Directive {
+	directives := []Directive{}
+	for p.skip("@") {
+		directives = append(directives, Directive{Name: p.name(), Args: p.arguments(false)})
+	}
+	return directives
+}
+
+// value parse a value, constant values are without variables
+func (p *parser) value(constant bool) interface{} {
+	t := p.tok
+	switch {
+	case t.kind == tokPunct && t.value == "$" && !constant:
+		p.next()
+		return Variable(p.name())
+	case t.kind == tokPunct && t.value == "[":
+		p.next()
+		list := []interface{}{}
+		for !p.skip("]") {
+			list = append(list, p.value(constant))
+		}
+		return list
+	case t.kind == tokPunct && t.value == "{":
+		p.next()
+		obj := map[string]interface{}{}
+		for !p.skip("}") {
+			name := p.name()
+			p.expect(":")
+			obj[name] = p.value(constant)
+		}
+		return obj
+	case t.kind == tokInt:
+		p.next()
+		i, err := strconv.ParseInt(t.value, 10, 64)
+		if err != nil {
+			p.fail("invalid Int: " + t.value)
+		}
+		return i
+	case t.kind == tokFloat:
+		p.next()
+		f, err := strconv.ParseFloat(t.value, 64)
+		if err != nil {
+			p.fail("invalid Float: " + t.value)
+		}
+		return f
+	case t.kind == tokString:
+		p.next()
+		return t.value
+	case t.kind == tokName:
+		p.next()
+		switch t.value {
+		case "true":
+			return true
+		case "false":
+			return false
+		case "null":
+			return nil
+		}
+		return Enum(t.value)
+	}
+	p.fail("unexpected: " + t.value)
+	return nil
+}
+
+func (p *parser) name() string {
+	if p.tok.kind != tokName {
+		p.fail("name expected, got: " + p.describe())
+	}
+	name := p.tok.value
+	p.next()
+	return name
+}
+
+func (p *parser) keyword(k string) {
+	if p.tok.kind != tokName || p.tok.value != k {
+		p.fail(k + " expected, got: " + p.describe())
+	}
+	p.next()
+}
+
+func (p *parser) peek(punct string) bool {
+	return p.tok.kind == tokPunct && p.tok.value == punct
+}
+
+func (p *parser) skip(punct string) bool {
+	if p.peek(punct) {
+		p.next()
+		return true
+	}
+	return false
+}
+
+func (p *parser) expect(punct string) {
+	if !p.skip(punct) {
+		p.fail(punct + " expected, got: " + p.describe())
+	}
+}
+
+func (p *parser) describe() string {
+	if p.tok.kind == tokEOF {
+		return "end of document"
+	}
+	return p.tok.value
+}
+
+func (p *parser) fail(msg string) {
+	p.failAt(msg, p.tok.pos)
+}
+
+func (p *parser) failAt(msg string, pos int) {
+	line := strings.Count(p.src[:pos], "\n") + 1
+	column := pos - strings.LastIndex(p.src[:pos], "\n")
+	panic(&SyntaxError{Message: msg, Line: line, Column: column})
+}
+
+// next read the next token (ignored: whitespace, commas and comments)
+func (p *parser) next() {
+	for p.pos < len(p.src) {
+		c := p.src[p.pos]
+		if c == ' ' || c == '\t' || c == '\n' || c == '\r' || c == ',' {
+			p.pos++
+		} else if c == '#' {
+			for p.pos < len(p.src) && p.src[p.pos] != '\n' {
+				p.pos++
+			}
+		} else {
+			break
+		}
+	}
+
+	start := p.pos
+	if p.pos >= len(p.src) {
+		p.tok = token{kind: tokEOF, pos: start}
+		return
+	}
+
+	c := p.src[p.pos]
+	switch {
+	case strings.HasPrefix(p.src[p.pos:], "..."):
+		p.pos += 3
+		p.tok = token{kind: tokPunct, value: "...", pos: start}
+	case strings.IndexByte("!$()[]{}:=@|&", c) >= 0:
+		p.pos++
+		p.tok = token{kind: tokPunct, value: string(c), pos: start}
+	case c == '_' || isLetter(c):
+		for p.pos < len(p.src) && (p.src[p.pos] == '_' || isLetter(p.src[p.pos]) || isDigit(p.src[p.pos])) {
+			p.pos++
+		}
+		p.tok = token{kind: tokName, value: p.src[start:p.pos], pos: start}
+	case c == '-' || isDigit(c):
+		p.number()
+	case c == '"':
+		p.string()
+	default:
+		r, _ := utf8.DecodeRuneInString(p.src[p.pos:])
+		p.failAt(fmt.Sprintf("unexpected character: %q", r), start)
+	}
+}
+
+func (p *parser) number() {
+	start := p.pos
+	kind := tokInt
+	if p.src[p.pos] == '-' {
+		p.pos++
+	}
+	p.digits()
+	if p.pos < len(p.src) && p.src[p.pos] == '.' {
+		kind = tokFloat
+		p.pos++
+		p.digits()
+	}
+	if p.pos < len(p.src) && (p.src[p.pos] == 'e' || p.src[p.pos] == 'E') {
+		kind = tokFloat
+		p.pos++
+		if p.pos < len(p.src) && (p.src[p.pos] == '+' || p.src[p.pos] == '-') {
+			p.pos++
+		}
+		p.digits()
+	}
+	p.tok = token{kind: kind, value: p.src[start:p.pos], pos: start}
+}
+
+func (p *parser) digits() {
+	start := p.pos
+	for p.pos < len(p.src) && isDigit(p.src[p.pos]) {
+		p.pos++
+	}
+	if start == p.pos {
+		p.failAt("digit expected", p.pos)
+	}
+}
+
+func (p *parser) string() {
+	start := p.pos
+	if strings.HasPrefix(p.src[p.pos:], `"""`) {
+		end := strings.Index(p.src[p.pos+3:], `"""`)
+		if end < 0 {
+			p.failAt("unterminated string", start)
+		}
+		value := p.src[p.pos+3 : p.pos+3+end]
+		p.pos += end + 6
+		p.tok = token{kind: tokString, value: strings.TrimSpace(value), pos: start}
+		return
+	}
+
+	p.pos++
+	for p.pos < len(p.src) && p.src[p.pos] != '"' {
+		if p.src[p.pos] == '\n' {
+			break
+		}
+		if p.src[p.pos] == '\\' {
+			p.pos++
+		}
+		p.pos++
+	}
+	if p.pos >= len(p.src) || p.src[p.pos] != '"' {
+		p.failAt("unterminated string", start)
+	}
+	p.pos++
+
+	// the escapes of GraphQL are the same as of JSON
+	value, err := strconv.Unquote(strings.Replace(p.src[start:p.pos], `\/`, "/", -1))
+	if err != nil {
+		p.failAt("invalid string: "+p.src[start:p.pos], start)
+	}
+	p.tok = token{kind: tokString, value: value, pos: start}
+}
+
+func isLetter(c byte) bool {
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z')
+}
+
+func isDigit(c byte) bool {
+	return c >= '0' && c <= '9'
+}
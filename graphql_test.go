@@ -0,0 +1,201 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/auth"
+	"github.com/lima1909/goheroes-appengine/policy"
+	"github.com/lima1909/goheroes-appengine/ratelimit"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// countScores is a ScoreService with the ID as score, it counts the calls
+type countScores struct {
+	calls *int
+}
+
+func (s countScores) Scores(c context.Context, svc service.HeroService) (map[int64]int, error) {
+	*s.calls++
+	heroes, err := svc.List(c, "")
+	scores := map[int64]int{}
+	for _, h := range heroes {
+		scores[h.ID] = int(h.ID) * 10
+	}
+	return scores, err
+}
+
+func postGraphQL(t *testing.T, query string, vars map[string]interface{}, header ...string) (int, map[string]interface{}) {
+	b, _ := json.Marshal(map[string]interface{}{"query": query, "variables": vars})
+	req, _ := http.NewRequest("POST", server.URL+"/graphql", strings.NewReader(string(b)))
+	req.Header.Set("Content-Type", "application/json")
+	for i := 0; i+1 < len(header); i += 2 {
+		req.Header.Set(header[i], header[i+1])
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	defer resp.Body.Close()
+
+	result := map[string]interface{}{}
+	json.NewDecoder(resp.Body).Decode(&result)
+	return resp.StatusCode, result
+}
+
+func TestGraphQLQuery(t *testing.T) {
+	resetTestHeroes(t)
+
+	calls := 0
+	scoreSvc := app.ScoreService
+	app.ScoreService = countScores{calls: &calls}
+	defer func() { app.ScoreService = scoreSvc }()
+
+	status, result := postGraphQL(t, `query ($size: Int) {
+		heroes(page: 1, size: $size) { total page size items { id name score scoreData { city } } }
+		jasmin: hero(id: 1) { name score }
+		missing: hero(id: 9999) { name }
+	}`, map[string]interface{}{"size": 2})
+	if status != http.StatusOK || result["errors"] != nil {
+		t.Fatalf("unexpected response: %v %v", status, result)
+	}
+
+	b, _ := json.Marshal(result["data"])
+	expected := `{"heroes":{"items":[{"id":1,"name":"Jasmin","score":10,"scoreData":{"city":"Nuremberg"}},` +
+		`{"id":2,"name":"Mario","score":20,"scoreData":{"city":"Nürnberg"}}],"page":1,"size":2,"total":7},` +
+		`"jasmin":{"name":"Jasmin","score":10},"missing":null}`
+	if string(b) != expected {
+		t.Errorf("\n%v !=\n%v", expected, string(b))
+	}
+	// the scores of the Heroes of one field are loaded with one call
+	if calls != 1 {
+		t.Errorf("expect one call of the ScoreService, got: %v", calls)
+	}
+}
+
+func TestGraphQLScoresRateLimit(t *testing.T) {
+	calls := 0
+	scoreSvc := app.ScoreService
+	app.ScoreService = countScores{calls: &calls}
+	app.limiter = &ratelimit.Limiter{
+		Store:  ratelimit.NewMemStore(),
+		Limits: map[string]ratelimit.Limit{"scores": {Requests: 1, Per: time.Hour}},
+		Class:  rateClass,
+	}
+	defer func() { app.ScoreService, app.limiter = scoreSvc, nil }()
+
+	// the heroes without the score are not limited
+	for i := 0; i < 2; i++ {
+		if status, result := postGraphQL(t, `{ heroes { items { name } } }`, nil); status != http.StatusOK || result["errors"] != nil {
+			t.Fatalf("unexpected response: %v %v", status, result)
+		}
+	}
+
+	if status, result := postGraphQL(t, `{ heroes { items { score } } }`, nil); status != http.StatusOK || result["errors"] != nil {
+		t.Fatalf("unexpected response: %v %v", status, result)
+	}
+	_, result := postGraphQL(t, `{ heroes { items { score } } }`, nil)
+	if errs, _ := result["errors"].([]interface{}); len(errs) == 0 || !strings.Contains(fmt.Sprint(errs[0]), "rate limit exceeded (scores") {
+		t.Errorf("expect the rate limit error of the scores, got: %v", result)
+	}
+	if calls != 1 {
+		t.Errorf("expect one call of the ScoreService, got: %v", calls)
+	}
+}
+
+func TestGraphQLPagination(t *testing.T) {
+	resetTestHeroes(t)
+
+	for _, tc := range []struct {
+		page, size int64
+		err        bool
+	}{
+		{1, 9223372036854775807, false},
+		{4611686018427387904, 4, true},
+		{3, 9223372036854775807, true},
+	} {
+		status, result := postGraphQL(t, fmt.Sprintf(`{ heroes(page: %d, size: %d) { total size } }`, tc.page, tc.size), nil)
+		if status != http.StatusOK || (result["errors"] != nil) != tc.err {
+			t.Errorf("unexpected response for page %v, size %v: %v %v", tc.page, tc.size, status, result)
+		}
+	}
+}
+
+func TestGraphQLMutation(t *testing.T) {
+	resetTestHeroes(t)
+
+	status, result := postGraphQL(t, `mutation { addHero(name: "GraphQL") { id name } }`, nil)
+	if status != http.StatusOK || result["errors"] != nil {
+		t.Fatalf("unexpected response: %v %v", status, result)
+	}
+	id := result["data"].(map[string]interface{})["addHero"].(map[string]interface{})["id"]
+
+	status, result = postGraphQL(t, `mutation ($id: Int!) {
+		updateHero(id: $id, name: "GraphQL 2", city: "Berlin") { name scoreData { city } }
+		moveHero(id: $id, pos: 0) { id }
+		deleteHero(id: $id) { name }
+	}`, map[string]interface{}{"id": id})
+	b, _ := json.Marshal(result)
+	// the fields are sorted by json.Marshal of the map
+	expected := `{"data":{"deleteHero":{"name":"GraphQL 2"},"moveHero":{"id":` + string(mustJSON(id)) +
+		`},"updateHero":{"name":"GraphQL 2","scoreData":{"city":"Berlin"}}}}`
+	if status != http.StatusOK || string(b) != expected {
+		t.Errorf("\n%v !=\n%v", expected, string(b))
+	}
+
+	_, result = postGraphQL(t, `mutation { moveHero(id: 1, pos: 9999) { id } }`, nil)
+	b, _ = json.Marshal(result)
+	if string(b) != `{"data":null,"errors":[{"message":"Out of Range","path":["moveHero"]}]}` {
+		t.Errorf("expect the error of the service: %v", string(b))
+	}
+}
+
+func mustJSON(v interface{}) []byte {
+	b, _ := json.Marshal(v)
+	return b
+}
+
+func TestGraphQLPermissions(t *testing.T) {
+	resetTestHeroes(t)
+
+	authenticator, public, pol := app.authenticator, app.public, app.policy
+	defer func() {
+		app.authenticator, app.public, app.policy = authenticator, public, pol
+	}()
+	app.authenticator = auth.APIKeys{
+		"edit": {Name: "editor", Roles: []string{policy.Editor}},
+	}
+	app.public = auth.IsRead
+	app.policy = policy.New(policy.DefaultRoles())
+	for route, perm := range routePermissions {
+		app.policy.Require(route, perm)
+	}
+	s := newTestServer()
+	defer s.Close()
+	srv := server
+	server = s
+	defer func() { server = srv }()
+
+	// anonymous: queries are allowed, mutations not
+	if status, result := postGraphQL(t, `{ heroes { total } }`, nil); status != http.StatusOK || result["errors"] != nil {
+		t.Errorf("expect the anonymous query: %v %v", status, result)
+	}
+	_, result := postGraphQL(t, `mutation { deleteHero(id: 1) { id } }`, nil)
+	if b, _ := json.Marshal(result["errors"]); !strings.Contains(string(b), "permission: heroes:delete required") {
+		t.Errorf("expect permission denied: %v", string(b))
+	}
+
+	// editor: can not read the protocols and can not delete
+	_, result = postGraphQL(t, `{ hero(id: 1) { protocols { note } } }`, nil, "Authorization", "ApiKey edit")
+	if b, _ := json.Marshal(result["errors"]); !strings.Contains(string(b), "permission: protocol:read required") {
+		t.Errorf("expect permission denied: %v", string(b))
+	}
+	if status, _ := postGraphQL(t, `{ heroes { total } }`, nil, "Authorization", "ApiKey invalid"); status != http.StatusUnauthorized {
+		t.Errorf("expect 401 for an invalid key, got: %v", status)
+	}
+}
@@ -0,0 +1,73 @@
+//go:build !appengine
+// +build !appengine
+
+package main
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"os"
+
+	"github.com/lima1909/goheroes-appengine/heroespb"
+	"github.com/lima1909/goheroes-appengine/ratelimit"
+	"github.com/lima1909/goheroes-appengine/rpc"
+	"github.com/lima1909/goheroes-appengine/service"
+	"google.golang.org/grpc"
+)
+
+// EnvGRPCAddr is the Env-Variable with the address of the gRPC server, e.g. :9090 (empty: no gRPC server)
+const EnvGRPCAddr = "HEROES_GRPC_ADDR"
+
+// newGRPCServer create the gRPC server with the services of the App
+// and the same tenant resolution, authentication and policy as the API
+func newGRPCServer() *grpc.Server {
+	guard := &rpc.Guard{
+		Resolver:      app.tenantResolver,
+		Authenticator: app.authenticator,
+		Public:        app.public,
+		Policy:        app.policy,
+		Limiter:       grpcLimiter(app.limiter),
+	}
+
+	gs := grpc.NewServer(guard.ServerOptions()...)
+	s := rpc.NewServer(app.ProtocolHeroService, app.ScoreService)
+	s.Events = app.events
+	s.Register(gs)
+	return gs
+}
+
+// grpcLimiter is the Limiter of the API, the method ScoreService.Scores has the class scores (like the route heroes.scores)
+func grpcLimiter(limiter *ratelimit.Limiter) *ratelimit.Limiter {
+	if limiter == nil {
+		return nil
+	}
+	l := *limiter
+	l.Class = func(r *http.Request) string {
+		// the path of a gRPC call is the method
+		if r.URL.Path == heroespb.ScoreService_Scores_FullMethodName {
+			return "scores"
+		}
+		return rateClass(r)
+	}
+	return &l
+}
+
+// startGRPC start the gRPC server, if the address is configured (only standalone, not in the cloud)
+func startGRPC() {
+	if addr := os.Getenv(EnvGRPCAddr); addr != "" && !service.RunInCloud() {
+		go serveGRPC(addr)
+	}
+}
+
+// serveGRPC start the gRPC server on the address (only standalone, not in the cloud)
+func serveGRPC(addr string) {
+	l, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("can not listen on %s for gRPC: %v", addr, err)
+	}
+	log.Printf("start the gRPC server on: %s", l.Addr())
+	if err := newGRPCServer().Serve(l); err != nil {
+		log.Fatalf("gRPC server: %v", err)
+	}
+}
@@ -0,0 +1,7 @@
+//go:build appengine
+// +build appengine
+
+package main
+
+// startGRPC is not supported in the App Engine runtime, the gRPC code (see: grpc.go) needs a newer Go
+func startGRPC() {}
@@ -0,0 +1,133 @@
+//go:build !appengine
+// +build !appengine
+
+package main
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/client"
+	"github.com/lima1909/goheroes-appengine/heroespb"
+	"github.com/lima1909/goheroes-appengine/rpc"
+	"github.com/lima1909/goheroes-appengine/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+func dialGRPC(t *testing.T) (*grpc.ClientConn, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	gs := newGRPCServer()
+	go gs.Serve(l)
+
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	return conn, func() {
+		conn.Close()
+		gs.Stop()
+	}
+}
+
+// the gRPC API and the REST API v2 use the same services
+func TestGRPCParity(t *testing.T) {
+	resetTestHeroes(t)
+
+	conn, stop := dialGRPC(t)
+	defer stop()
+	hs := heroespb.NewHeroServiceClient(conn)
+	rest := client.New(server.URL)
+	c := context.Background()
+
+	h, err := hs.AddHero(c, &heroespb.AddHeroRequest{Name: "Parity"})
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	got, err := rest.Get(c, h.Id)
+	if err != nil || *got != rpc.Hero(h) {
+		t.Errorf("%v != %v (%v)", rpc.Hero(h), got, err)
+	}
+
+	got.ScoreData = service.ScoreData{Name: "parity", City: "Berlin", Country: "de"}
+	if _, err = rest.Update(c, *got); err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	h, err = hs.GetHero(c, &heroespb.GetHeroRequest{Id: got.ID})
+	if err != nil || rpc.Hero(h) != *got {
+		t.Errorf("%v != %v (%v)", *got, h, err)
+	}
+
+	heroes, err := rest.List(c, "")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	list, err := hs.ListHeroes(c, &heroespb.ListHeroesRequest{})
+	if err != nil || len(list.Heroes) != len(heroes) {
+		t.Fatalf("expect %v Heroes, got: %v (%v)", len(heroes), list, err)
+	}
+	for i, h := range list.Heroes {
+		if rpc.Hero(h) != heroes[i] {
+			t.Errorf("%v != %v", heroes[i], h)
+		}
+	}
+
+	if _, err = hs.MoveHero(c, &heroespb.MoveHeroRequest{Hero: h, Pos: 0}); err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if heroes, _ = rest.List(c, ""); len(heroes) == 0 || heroes[0].ID != h.Id {
+		t.Errorf("expect the Hero %v on position 0, got: %v", h.Id, heroes)
+	}
+
+	// the same errors
+	if _, err = rest.Move(c, *got, 9999); err != service.ErrPosNotFound {
+		t.Errorf("expect ErrPosNotFound, got: %v", err)
+	}
+	if _, err = hs.MoveHero(c, &heroespb.MoveHeroRequest{Hero: h, Pos: 9999}); status.Code(err) != codes.OutOfRange {
+		t.Errorf("expect OutOfRange, got: %v", err)
+	}
+
+	if _, err = hs.DeleteHero(c, &heroespb.DeleteHeroRequest{Id: h.Id}); err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if _, err = rest.Get(c, h.Id); err != service.ErrHeroNotFound {
+		t.Errorf("expect ErrHeroNotFound, got: %v", err)
+	}
+	if _, err = hs.GetHero(c, &heroespb.GetHeroRequest{Id: h.Id}); status.Code(err) != codes.NotFound {
+		t.Errorf("expect NotFound, got: %v", err)
+	}
+}
+
+func TestGRPCProtocols(t *testing.T) {
+	resetTestHeroes(t)
+
+	conn, stop := dialGRPC(t)
+	defer stop()
+	ps := heroespb.NewProtocolServiceClient(conn)
+
+	protocols, err := client.New(server.URL).Protocols(context.Background())
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	list, err := ps.ListProtocols(context.Background(), &heroespb.ListProtocolsRequest{})
+	if err != nil || len(list.Protocols) != len(protocols) {
+		t.Fatalf("expect %v Protocols, got: %v (%v)", len(protocols), list, err)
+	}
+
+	// the tail starts with the existing Protocols
+	c, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := ps.TailProtocols(c, &heroespb.TailProtocolsRequest{})
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if _, err = stream.Recv(); err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+}
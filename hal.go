@@ -0,0 +1,144 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/lima1909/goheroes-appengine/hal"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// DefaultPageSize is the size of a page, if only the page is in the request
+const DefaultPageSize = 20
+
+// MaxPageSize is the max size of a page, a bigger size is reduced
+const MaxPageSize = 1000
+
+// the links of a Hero: relation to route name (the route variable id is the ID of the Hero)
+var heroLinks = []struct {
+	rel, route string
+	withID     bool
+}{
+	{"self", "hero.get", true},
+	{"history", "hero.history", true},
+	{"scores", "heroes.scores", false},
+	{"move", "heroes.move", false},
+}
+
+// heroResource create the HAL resource of the Hero with the links: self, history, scores and move
+// (in the API version of the request)
+func heroResource(r *http.Request, h *service.Hero) (map[string]interface{}, error) {
+	links := hal.Links{}
+	for _, l := range heroLinks {
+		pairs := []string{"version", mux.Vars(r)["version"]}
+		if l.withID {
+			pairs = append(pairs, "id", strconv.FormatInt(h.ID, 10))
+		}
+		link, err := hal.RouteLink(routes, l.route, pairs...)
+		if err != nil {
+			return nil, err
+		}
+		links[l.rel] = link
+	}
+	return hal.Resource(versioned(r, h), links, nil)
+}
+
+// writeHeroListHAL write the page of the Heroes as HAL resource with the pagination links
+func writeHeroListHAL(w http.ResponseWriter, r *http.Request, heroes []service.Hero, page, size, total int) {
+	list, err := hal.RouteLink(routes, "heroes.list", "version", mux.Vars(r)["version"])
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	resources := make([]interface{}, 0, len(heroes))
+	for i := range heroes {
+		res, err := heroResource(r, &heroes[i])
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		resources = append(resources, res)
+	}
+
+	query := url.Values{}
+	if name := r.URL.Query().Get("name"); name != "" {
+		query.Set("name", name)
+	}
+	res, err := hal.Resource(struct {
+		Count int `json:"count"`
+		Total int `json:"total"`
+	}{len(heroes), total}, hal.PageLinks(list.Href, query, page, size, total), map[string]interface{}{"heroes": resources})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeToClient(w, r, res)
+}
+
+// pageOf returns the Heroes of the page
+func pageOf(heroes []service.Hero, page, size int) []service.Hero {
+	total := len(heroes)
+	from, to := (page-1)*size, page*size
+	if from > total {
+		from = total
+	}
+	if to > total {
+		to = total
+	}
+	return heroes[from:to]
+}
+
+// pagination returns the page (1 ... n) and the size of the request (query: page and size),
+// without page and size, the page contains all (total) items
+func pagination(r *http.Request, total int) (int, int, error) {
+	page, size, paged, err := pageParams(r)
+	if err != nil {
+		return 0, 0, err
+	}
+	if !paged {
+		if total == 0 {
+			return 1, 1, nil
+		}
+		return 1, total, nil
+	}
+	return checkPage(page, size, total)
+}
+
+// pageParams returns the page and the size of the query (false: no page and no size in the query)
+func pageParams(r *http.Request) (int64, int64, bool, error) {
+	q := r.URL.Query()
+	if q.Get("page") == "" && q.Get("size") == "" {
+		return 0, 0, false, nil
+	}
+
+	page, size := int64(1), int64(DefaultPageSize)
+	var err error
+	if p := q.Get("page"); p != "" {
+		if page, err = strconv.ParseInt(p, 10, 64); err != nil || page < 1 {
+			return 0, 0, false, fmt.Errorf("invalid page: %v", p)
+		}
+	}
+	if s := q.Get("size"); s != "" {
+		if size, err = strconv.ParseInt(s, 10, 64); err != nil || size < 1 {
+			return 0, 0, false, fmt.Errorf("invalid size: %v", s)
+		}
+	}
+	return page, size, true, nil
+}
+
+// checkPage reduce the size to MaxPageSize and check, that the page is not behind the first empty page
+// (after the last page), so the bounds of the page can not overflow
+func checkPage(page, size int64, total int) (int, int, error) {
+	if size > MaxPageSize {
+		size = MaxPageSize
+	}
+	if last := (int64(total) + size - 1) / size; page-1 > last {
+		return 0, 0, fmt.Errorf("invalid page: %v (size: %v, total: %v)", page, size, total)
+	}
+	return int(page), int(size), nil
+}
@@ -0,0 +1,94 @@
+// Package hal create the HAL (Hypertext Application Language) representation of the resources,
+// the links are generated from the named routes of the router.
+// See: https://tools.ietf.org/html/draft-kelly-json-hal
+package hal
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Link to a resource, a templated Link contains variables (RFC 6570), e.g. /api/heroes?pos={pos}
+type Link struct {
+	Href      string `json:"href"`
+	Templated bool   `json:"templated,omitempty"`
+}
+
+// Links of a resource by relation, e.g. self
+type Links map[string]Link
+
+// Resource is the JSON representation of v with the _links and the (optional) _embedded resources
+func Resource(v interface{}, links Links, embedded map[string]interface{}) (map[string]interface{}, error) {
+	res := map[string]interface{}{}
+	if v != nil {
+		b, err := json.Marshal(v)
+		if err != nil {
+			return nil, err
+		}
+		dec := json.NewDecoder(bytes.NewReader(b))
+		dec.UseNumber()
+		if err = dec.Decode(&res); err != nil {
+			return nil, fmt.Errorf("HAL resource must be a JSON object: %v", err)
+		}
+	}
+
+	res["_links"] = links
+	if len(embedded) > 0 {
+		res["_embedded"] = embedded
+	}
+	return res, nil
+}
+
+// RouteLink create the Link to the route with the name, pairs are the route variables (e.g. "id", "1"),
+// the queries of the route are added as templates
+func RouteLink(router *mux.Router, name string, pairs ...string) (Link, error) {
+	route := router.Get(name)
+	if route == nil {
+		return Link{}, fmt.Errorf("no route with name: %s", name)
+	}
+	u, err := route.URLPath(pairs...)
+	if err != nil {
+		return Link{}, fmt.Errorf("can not create URL for route: %s: %v", name, err)
+	}
+
+	link := Link{Href: u.String()}
+	if tmpl, err := route.GetQueriesTemplates(); err == nil && len(tmpl) > 0 {
+		link.Href += "?" + strings.Join(tmpl, "&")
+		link.Templated = true
+	}
+	return link, nil
+}
+
+// PageLinks create the Links self, first, last and (if exist) prev and next for the page (1 ... n)
+// of the list with href, the query contains all other parameters (e.g. a filter)
+func PageLinks(href string, query url.Values, page, size, total int) Links {
+	pageLink := func(p int) Link {
+		q := url.Values{}
+		for k, v := range query {
+			q[k] = v
+		}
+		q.Set("page", strconv.Itoa(p))
+		q.Set("size", strconv.Itoa(size))
+		return Link{Href: href + "?" + q.Encode()}
+	}
+
+	last := 1
+	if size > 0 && total > 0 {
+		last = (total + size - 1) / size
+	}
+
+	links := Links{"self": pageLink(page), "first": pageLink(1), "last": pageLink(last)}
+	if page > 1 {
+		links["prev"] = pageLink(page - 1)
+	}
+	if page < last {
+		links["next"] = pageLink(page + 1)
+	}
+	return links
+}
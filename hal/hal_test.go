@@ -0,0 +1,81 @@
+package hal
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func newRouter() *mux.Router {
+	router := mux.NewRouter()
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("/api/heroes", ok).Methods("PUT").Queries("pos", "{pos}").Name("move")
+	router.HandleFunc("/api/heroes/{id:[0-9]+}", ok).Methods("GET").Name("get")
+	return router
+}
+
+func TestRouteLink(t *testing.T) {
+	router := newRouter()
+
+	for _, tc := range []struct {
+		name  string
+		pairs []string
+		link  Link
+		err   bool
+	}{
+		{"get", []string{"id", "5"}, Link{Href: "/api/heroes/5"}, false},
+		{"move", nil, Link{Href: "/api/heroes?pos={pos}", Templated: true}, false},
+		{"get", []string{"id", "x"}, Link{}, true},
+		{"unknown", nil, Link{}, true},
+	} {
+		link, err := RouteLink(router, tc.name, tc.pairs...)
+		if (err != nil) != tc.err || link != tc.link {
+			t.Errorf("expect %v (err: %v) for: %v, got: %v (%v)", tc.link, tc.err, tc.name, link, err)
+		}
+	}
+}
+
+func TestResource(t *testing.T) {
+	hero := struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}{1, "Jasmin"}
+
+	res, err := Resource(hero, Links{"self": {Href: "/api/heroes/1"}}, nil)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	b, _ := json.Marshal(res)
+	expect := `{"_links":{"self":{"href":"/api/heroes/1"}},"id":1,"name":"Jasmin"}`
+	if string(b) != expect {
+		t.Errorf("%v != %v", expect, string(b))
+	}
+
+	if _, err = Resource([]int{1}, nil, nil); err == nil {
+		t.Errorf("err expected for a list")
+	}
+}
+
+func TestPageLinks(t *testing.T) {
+	links := PageLinks("/api/heroes", url.Values{"name": {"a"}}, 2, 3, 7)
+
+	for rel, href := range map[string]string{
+		"self":  "/api/heroes?name=a&page=2&size=3",
+		"first": "/api/heroes?name=a&page=1&size=3",
+		"prev":  "/api/heroes?name=a&page=1&size=3",
+		"next":  "/api/heroes?name=a&page=3&size=3",
+		"last":  "/api/heroes?name=a&page=3&size=3",
+	} {
+		if links[rel].Href != href {
+			t.Errorf("%v: %v != %v", rel, href, links[rel].Href)
+		}
+	}
+
+	links = PageLinks("/api/heroes", nil, 1, 10, 0)
+	if _, ok := links["next"]; ok || links["last"].Href != "/api/heroes?page=1&size=10" {
+		t.Errorf("expect one page without next: %v", links)
+	}
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/db"
+)
+
+func TestHeroHAL(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+
+	get := func(url string) map[string]interface{} {
+		req, _ := http.NewRequest("GET", server.URL+url, nil)
+		req.Header.Set("Accept", "application/hal+json")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+			return nil
+		}
+		if resp.Header.Get("Content-Type") != "application/hal+json; charset=utf-8" {
+			t.Errorf("expect HAL Content-Type, got: %v", resp.Header.Get("Content-Type"))
+		}
+		res := map[string]interface{}{}
+		json.NewDecoder(resp.Body).Decode(&res)
+		return res
+	}
+	href := func(res map[string]interface{}, rel string) string {
+		links, _ := res["_links"].(map[string]interface{})
+		link, _ := links[rel].(map[string]interface{})
+		h, _ := link["href"].(string)
+		return h
+	}
+
+	hero := get("/api/heroes/1")
+	for rel, expect := range map[string]string{
+		"self":    "/api/heroes/1",
+		"history": "/api/heroes/1/history",
+		"scores":  "/api/heroes/scores",
+		"move":    "/api/heroes?pos={pos}",
+	} {
+		if href(hero, rel) != expect {
+			t.Errorf("%v: %v != %v", rel, expect, href(hero, rel))
+		}
+	}
+	if hero["name"] != "Jasmin" {
+		t.Errorf("expect name Jasmin: %v", hero)
+	}
+
+	list := get("/api/heroes?page=2&size=3")
+	for rel, expect := range map[string]string{
+		"self":  "/api/heroes?page=2&size=3",
+		"prev":  "/api/heroes?page=1&size=3",
+		"next":  "/api/heroes?page=3&size=3",
+		"last":  "/api/heroes?page=3&size=3",
+		"first": "/api/heroes?page=1&size=3",
+	} {
+		if href(list, rel) != expect {
+			t.Errorf("%v: %v != %v", rel, expect, href(list, rel))
+		}
+	}
+	embedded, _ := list["_embedded"].(map[string]interface{})
+	heroes, _ := embedded["heroes"].([]interface{})
+	if len(heroes) != 3 || list["total"] != float64(7) {
+		t.Errorf("expect 3 of 7 Heroes, got: %v of %v", len(heroes), list["total"])
+	}
+	if href(heroes[0].(map[string]interface{}), "self") != "/api/heroes/4" {
+		t.Errorf("expect first Hero on page 2 with ID 4: %v", heroes[0])
+	}
+}
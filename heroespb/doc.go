@@ -0,0 +1,4 @@
+// Package heroespb contains the protobuf messages and the gRPC services of heroes.proto
+package heroespb
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative heroes.proto
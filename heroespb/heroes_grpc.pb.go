@@ -0,0 +1,559 @@
+// The gRPC API of the heroes service, the same services as the REST API (see: README.md).
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             v3.21.12
+// source: heroes.proto
+
+package heroespb
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	HeroService_ListHeroes_FullMethodName = "/heroes.v1.HeroService/ListHeroes"
+	HeroService_GetHero_FullMethodName    = "/heroes.v1.HeroService/GetHero"
+	HeroService_AddHero_FullMethodName    = "/heroes.v1.HeroService/AddHero"
+	HeroService_UpdateHero_FullMethodName = "/heroes.v1.HeroService/UpdateHero"
+	HeroService_MoveHero_FullMethodName   = "/heroes.v1.HeroService/MoveHero"
+	HeroService_DeleteHero_FullMethodName = "/heroes.v1.HeroService/DeleteHero"
+)
+
+// HeroServiceClient is the client API for HeroService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type HeroServiceClient interface {
+	// ListHeroes returns the Heroes, filtered by name (empty: all Heroes)
+	ListHeroes(ctx context.Context, in *ListHeroesRequest, opts ...grpc.CallOption) (*ListHeroesResponse, error)
+	// GetHero returns the Hero by ID (NOT_FOUND: Hero not Found)
+	GetHero(ctx context.Context, in *GetHeroRequest, opts ...grpc.CallOption) (*Hero, error)
+	// AddHero add a new Hero with the name
+	AddHero(ctx context.Context, in *AddHeroRequest, opts ...grpc.CallOption) (*Hero, error)
+	// UpdateHero update the name and the ScoreData of the Hero
+	UpdateHero(ctx context.Context, in *UpdateHeroRequest, opts ...grpc.CallOption) (*Hero, error)
+	// MoveHero move the Hero to the position (OUT_OF_RANGE: invalid position)
+	MoveHero(ctx context.Context, in *MoveHeroRequest, opts ...grpc.CallOption) (*Hero, error)
+	// DeleteHero move the Hero to the trash
+	DeleteHero(ctx context.Context, in *DeleteHeroRequest, opts ...grpc.CallOption) (*Hero, error)
+}
+
+type heroServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewHeroServiceClient(cc grpc.ClientConnInterface) HeroServiceClient {
+	return &heroServiceClient{cc}
+}
+
+func (c *heroServiceClient) ListHeroes(ctx context.Context, in *ListHeroesRequest, opts ...grpc.CallOption) (*ListHeroesResponse, error) {
+	out := new(ListHeroesResponse)
+	err := c.cc.Invoke(ctx, HeroService_ListHeroes_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *heroServiceClient) GetHero(ctx context.Context, in *GetHeroRequest, opts ...grpc.CallOption) (*Hero, error) {
+	out := new(Hero)
+	err := c.cc.Invoke(ctx, HeroService_GetHero_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *heroServiceClient) AddHero(ctx context.Context, in *AddHeroRequest, opts ...grpc.CallOption) (*Hero, error) {
+	out := new(Hero)
+	err := c.cc.Invoke(ctx, HeroService_AddHero_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *heroServiceClient) UpdateHero(ctx context.Context, in *UpdateHeroRequest, opts ...grpc.CallOption) (*Hero, error) {
+	out := new(Hero)
+	err := c.cc.Invoke(ctx, HeroService_UpdateHero_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *heroServiceClient) MoveHero(ctx context.Context, in *MoveHeroRequest, opts ...grpc.CallOption) (*Hero, error) {
+	out := new(Hero)
+	err := c.cc.Invoke(ctx, HeroService_MoveHero_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *heroServiceClient) DeleteHero(ctx context.Context, in *DeleteHeroRequest, opts ...grpc.CallOption) (*Hero, error) {
+	out := new(Hero)
+	err := c.cc.Invoke(ctx, HeroService_DeleteHero_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// HeroServiceServer is the server API for HeroService service.
+// All implementations must embed UnimplementedHeroServiceServer
+// for forward compatibility
+type HeroServiceServer interface {
+	// ListHeroes returns the Heroes, filtered by name (empty: all Heroes)
+	ListHeroes(context.Context, *ListHeroesRequest) (*ListHeroesResponse, error)
+	// GetHero returns the Hero by ID (NOT_FOUND: Hero not Found)
+	GetHero(context.Context, *GetHeroRequest) (*Hero, error)
+	// AddHero add a new Hero with the name
+	AddHero(context.Context, *AddHeroRequest) (*Hero, error)
+	// UpdateHero update the name and the ScoreData of the Hero
+	UpdateHero(context.Context, *UpdateHeroRequest) (*Hero, error)
+	// MoveHero move the Hero to the position (OUT_OF_RANGE: invalid position)
+	MoveHero(context.Context, *MoveHeroRequest) (*Hero, error)
+	// DeleteHero move the Hero to the trash
+	DeleteHero(context.Context, *DeleteHeroRequest) (*Hero, error)
+	mustEmbedUnimplementedHeroServiceServer()
+}
+
+// UnimplementedHeroServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedHeroServiceServer struct {
+}
+
+func (UnimplementedHeroServiceServer) ListHeroes(context.Context, *ListHeroesRequest) (*ListHeroesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListHeroes not implemented")
+}
+func (UnimplementedHeroServiceServer) GetHero(context.Context, *GetHeroRequest) (*Hero, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHero not implemented")
+}
+func (UnimplementedHeroServiceServer) AddHero(context.Context, *AddHeroRequest) (*Hero, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AddHero not implemented")
+}
+func (UnimplementedHeroServiceServer) UpdateHero(context.Context, *UpdateHeroRequest) (*Hero, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateHero not implemented")
+}
+func (UnimplementedHeroServiceServer) MoveHero(context.Context, *MoveHeroRequest) (*Hero, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method MoveHero not implemented")
+}
+func (UnimplementedHeroServiceServer) DeleteHero(context.Context, *DeleteHeroRequest) (*Hero, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteHero not implemented")
+}
+func (UnimplementedHeroServiceServer) mustEmbedUnimplementedHeroServiceServer() {}
+
+// UnsafeHeroServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to HeroServiceServer will
+// result in compilation errors.
+type UnsafeHeroServiceServer interface {
+	mustEmbedUnimplementedHeroServiceServer()
+}
+
+func RegisterHeroServiceServer(s grpc.ServiceRegistrar, srv HeroServiceServer) {
+	s.RegisterService(&HeroService_ServiceDesc, srv)
+}
+
+func _HeroService_ListHeroes_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListHeroesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeroServiceServer).ListHeroes(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeroService_ListHeroes_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeroServiceServer).ListHeroes(ctx, req.(*ListHeroesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeroService_GetHero_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHeroRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeroServiceServer).GetHero(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeroService_GetHero_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeroServiceServer).GetHero(ctx, req.(*GetHeroRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeroService_AddHero_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AddHeroRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeroServiceServer).AddHero(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeroService_AddHero_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeroServiceServer).AddHero(ctx, req.(*AddHeroRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeroService_UpdateHero_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateHeroRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeroServiceServer).UpdateHero(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeroService_UpdateHero_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeroServiceServer).UpdateHero(ctx, req.(*UpdateHeroRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeroService_MoveHero_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(MoveHeroRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeroServiceServer).MoveHero(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeroService_MoveHero_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeroServiceServer).MoveHero(ctx, req.(*MoveHeroRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HeroService_DeleteHero_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteHeroRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HeroServiceServer).DeleteHero(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HeroService_DeleteHero_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HeroServiceServer).DeleteHero(ctx, req.(*DeleteHeroRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// HeroService_ServiceDesc is the grpc.ServiceDesc for HeroService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var HeroService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "heroes.v1.HeroService",
+	HandlerType: (*HeroServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListHeroes",
+			Handler:    _HeroService_ListHeroes_Handler,
+		},
+		{
+			MethodName: "GetHero",
+			Handler:    _HeroService_GetHero_Handler,
+		},
+		{
+			MethodName: "AddHero",
+			Handler:    _HeroService_AddHero_Handler,
+		},
+		{
+			MethodName: "UpdateHero",
+			Handler:    _HeroService_UpdateHero_Handler,
+		},
+		{
+			MethodName: "MoveHero",
+			Handler:    _HeroService_MoveHero_Handler,
+		},
+		{
+			MethodName: "DeleteHero",
+			Handler:    _HeroService_DeleteHero_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "heroes.proto",
+}
+
+const (
+	ScoreService_Scores_FullMethodName = "/heroes.v1.ScoreService/Scores"
+)
+
+// ScoreServiceClient is the client API for ScoreService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ScoreServiceClient interface {
+	Scores(ctx context.Context, in *ScoresRequest, opts ...grpc.CallOption) (*ScoresResponse, error)
+}
+
+type scoreServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewScoreServiceClient(cc grpc.ClientConnInterface) ScoreServiceClient {
+	return &scoreServiceClient{cc}
+}
+
+func (c *scoreServiceClient) Scores(ctx context.Context, in *ScoresRequest, opts ...grpc.CallOption) (*ScoresResponse, error) {
+	out := new(ScoresResponse)
+	err := c.cc.Invoke(ctx, ScoreService_Scores_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// ScoreServiceServer is the server API for ScoreService service.
+// All implementations must embed UnimplementedScoreServiceServer
+// for forward compatibility
+type ScoreServiceServer interface {
+	Scores(context.Context, *ScoresRequest) (*ScoresResponse, error)
+	mustEmbedUnimplementedScoreServiceServer()
+}
+
+// UnimplementedScoreServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedScoreServiceServer struct {
+}
+
+func (UnimplementedScoreServiceServer) Scores(context.Context, *ScoresRequest) (*ScoresResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Scores not implemented")
+}
+func (UnimplementedScoreServiceServer) mustEmbedUnimplementedScoreServiceServer() {}
+
+// UnsafeScoreServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ScoreServiceServer will
+// result in compilation errors.
+type UnsafeScoreServiceServer interface {
+	mustEmbedUnimplementedScoreServiceServer()
+}
+
+func RegisterScoreServiceServer(s grpc.ServiceRegistrar, srv ScoreServiceServer) {
+	s.RegisterService(&ScoreService_ServiceDesc, srv)
+}
+
+func _ScoreService_Scores_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ScoresRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ScoreServiceServer).Scores(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ScoreService_Scores_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ScoreServiceServer).Scores(ctx, req.(*ScoresRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// ScoreService_ServiceDesc is the grpc.ServiceDesc for ScoreService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ScoreService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "heroes.v1.ScoreService",
+	HandlerType: (*ScoreServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Scores",
+			Handler:    _ScoreService_Scores_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "heroes.proto",
+}
+
+const (
+	ProtocolService_ListProtocols_FullMethodName = "/heroes.v1.ProtocolService/ListProtocols"
+	ProtocolService_TailProtocols_FullMethodName = "/heroes.v1.ProtocolService/TailProtocols"
+)
+
+// ProtocolServiceClient is the client API for ProtocolService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type ProtocolServiceClient interface {
+	// ListProtocols returns a page of the Protocols (newest first) with the filters of the REST API
+	ListProtocols(ctx context.Context, in *ListProtocolsRequest, opts ...grpc.CallOption) (*ListProtocolsResponse, error)
+	// TailProtocols send the Protocols after since and then the new Protocols, until the call is canceled
+	// (UNAVAILABLE: the client is too slow, resume with since)
+	TailProtocols(ctx context.Context, in *TailProtocolsRequest, opts ...grpc.CallOption) (ProtocolService_TailProtocolsClient, error)
+}
+
+type protocolServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewProtocolServiceClient(cc grpc.ClientConnInterface) ProtocolServiceClient {
+	return &protocolServiceClient{cc}
+}
+
+func (c *protocolServiceClient) ListProtocols(ctx context.Context, in *ListProtocolsRequest, opts ...grpc.CallOption) (*ListProtocolsResponse, error) {
+	out := new(ListProtocolsResponse)
+	err := c.cc.Invoke(ctx, ProtocolService_ListProtocols_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *protocolServiceClient) TailProtocols(ctx context.Context, in *TailProtocolsRequest, opts ...grpc.CallOption) (ProtocolService_TailProtocolsClient, error) {
+	stream, err := c.cc.NewStream(ctx, &ProtocolService_ServiceDesc.Streams[0], ProtocolService_TailProtocols_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &protocolServiceTailProtocolsClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type ProtocolService_TailProtocolsClient interface {
+	Recv() (*Protocol, error)
+	grpc.ClientStream
+}
+
+type protocolServiceTailProtocolsClient struct {
+	grpc.ClientStream
+}
+
+func (x *protocolServiceTailProtocolsClient) Recv() (*Protocol, error) {
+	m := new(Protocol)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// ProtocolServiceServer is the server API for ProtocolService service.
+// All implementations must embed UnimplementedProtocolServiceServer
+// for forward compatibility
+type ProtocolServiceServer interface {
+	// ListProtocols returns a page of the Protocols (newest first) with the filters of the REST API
+	ListProtocols(context.Context, *ListProtocolsRequest) (*ListProtocolsResponse, error)
+	// TailProtocols send the Protocols after since and then the new Protocols, until the call is canceled
+	// (UNAVAILABLE: the client is too slow, resume with since)
+	TailProtocols(*TailProtocolsRequest, ProtocolService_TailProtocolsServer) error
+	mustEmbedUnimplementedProtocolServiceServer()
+}
+
+// UnimplementedProtocolServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedProtocolServiceServer struct {
+}
+
+func (UnimplementedProtocolServiceServer) ListProtocols(context.Context, *ListProtocolsRequest) (*ListProtocolsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListProtocols not implemented")
+}
+func (UnimplementedProtocolServiceServer) TailProtocols(*TailProtocolsRequest, ProtocolService_TailProtocolsServer) error {
+	return status.Errorf(codes.Unimplemented, "method TailProtocols not implemented")
+}
+func (UnimplementedProtocolServiceServer) mustEmbedUnimplementedProtocolServiceServer() {}
+
+// UnsafeProtocolServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to ProtocolServiceServer will
+// result in compilation errors.
+type UnsafeProtocolServiceServer interface {
+	mustEmbedUnimplementedProtocolServiceServer()
+}
+
+func RegisterProtocolServiceServer(s grpc.ServiceRegistrar, srv ProtocolServiceServer) {
+	s.RegisterService(&ProtocolService_ServiceDesc, srv)
+}
+
+func _ProtocolService_ListProtocols_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListProtocolsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ProtocolServiceServer).ListProtocols(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ProtocolService_ListProtocols_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ProtocolServiceServer).ListProtocols(ctx, req.(*ListProtocolsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _ProtocolService_TailProtocols_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(TailProtocolsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(ProtocolServiceServer).TailProtocols(m, &protocolServiceTailProtocolsServer{stream})
+}
+
+type ProtocolService_TailProtocolsServer interface {
+	Send(*Protocol) error
+	grpc.ServerStream
+}
+
+type protocolServiceTailProtocolsServer struct {
+	grpc.ServerStream
+}
+
+func (x *protocolServiceTailProtocolsServer) Send(m *Protocol) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// ProtocolService_ServiceDesc is the grpc.ServiceDesc for ProtocolService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var ProtocolService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "heroes.v1.ProtocolService",
+	HandlerType: (*ProtocolServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListProtocols",
+			Handler:    _ProtocolService_ListProtocols_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "TailProtocols",
+			Handler:       _ProtocolService_TailProtocols_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "heroes.proto",
+}
@@ -0,0 +1,260 @@
+// Package history is a decorator around a HeroService, which stores every change of a Hero
+// as an immutable Revision (who, when, diff) and can revert a Hero to a Revision.
+//
+// Recorded are the content changes: Add, Update, Delete, Restore and Revert.
+// Changes of the position (UpdatePosition) are not recorded.
+package history
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// HeroService decorate a ProtocolHeroService with Revisions
+type HeroService struct {
+	hs service.ProtocolHeroService
+
+	mu        sync.Mutex
+	revisions map[int64][]service.Revision
+}
+
+// NewHeroService create a new instance
+func NewHeroService(hs service.ProtocolHeroService) *HeroService {
+	return &HeroService{hs: hs, revisions: map[int64][]service.Revision{}}
+}
+
+// History impl from HistoryService, list all Revisions of the Hero (oldest first)
+func (hs *HeroService) History(c context.Context, id int64) ([]service.Revision, error) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	revs := hs.revisions[id]
+	if len(revs) == 0 {
+		// a Hero without changes has no Revisions
+		if _, err := hs.hs.GetByID(c, id); err != nil {
+			return nil, err
+		}
+	}
+
+	result := make([]service.Revision, len(revs))
+	copy(result, revs)
+	return result, nil
+}
+
+// Revert impl from HistoryService, set the Hero to the state of the Revision
+func (hs *HeroService) Revert(c context.Context, id int64, rev int) (*service.Hero, error) {
+	hs.mu.Lock()
+	var target *service.Hero
+	for _, r := range hs.revisions[id] {
+		if r.Rev == rev {
+			target = r.Hero
+			break
+		}
+	}
+	hs.mu.Unlock()
+
+	if target == nil {
+		return nil, service.ErrRevisionNotFound
+	}
+
+	// a deleted Hero must be restored first
+	old, err := hs.hs.GetByID(c, id)
+	if err != nil {
+		ts, ok := hs.hs.(service.TrashService)
+		if !ok {
+			return nil, err
+		}
+		if old, err = ts.Restore(c, id); err != nil {
+			return nil, err
+		}
+	}
+
+	h, err := hs.hs.Update(c, *target)
+	if err != nil {
+		return nil, err
+	}
+
+	hs.record(c, "Revert", id, old, h)
+	return h, nil
+}
+
+// Protocols delegate to ProtocolService
+func (hs *HeroService) Protocols(c context.Context) ([]service.Protocol, error) {
+	return hs.hs.Protocols(c)
+}
+
+// QueryProtocols delegate to ProtocolService
+func (hs *HeroService) QueryProtocols(c context.Context, q service.ProtocolQuery) ([]service.Protocol, error) {
+	return hs.hs.QueryProtocols(c, q)
+}
+
+// CountProtocols delegate to ProtocolService
+func (hs *HeroService) CountProtocols(c context.Context, q service.ProtocolQuery) (int, error) {
+	return hs.hs.CountProtocols(c, q)
+}
+
+// List delegate to HeroService
+func (hs *HeroService) List(c context.Context, name string) ([]service.Hero, error) {
+	return hs.hs.List(c, name)
+}
+
+// GetByID delegate to HeroService
+func (hs *HeroService) GetByID(c context.Context, id int64) (*service.Hero, error) {
+	return hs.hs.GetByID(c, id)
+}
+
+// Add delegate to HeroService and record the Revision
+func (hs *HeroService) Add(c context.Context, n string) (*service.Hero, error) {
+	h, err := hs.hs.Add(c, n)
+	if err != nil {
+		return h, err
+	}
+
+	hs.record(c, "Add", h.ID, nil, h)
+	return h, nil
+}
+
+// Update delegate to HeroService and record the Revision
+func (hs *HeroService) Update(c context.Context, h service.Hero) (*service.Hero, error) {
+	old, _ := hs.hs.GetByID(c, h.ID)
+
+	hero, err := hs.hs.Update(c, h)
+	if err != nil {
+		return hero, err
+	}
+
+	hs.record(c, "Update", hero.ID, old, hero)
+	return hero, nil
+}
+
+// UpdatePosition delegate to HeroService (without Revision)
+func (hs *HeroService) UpdatePosition(c context.Context, h service.Hero, pos int64) (*service.Hero, error) {
+	return hs.hs.UpdatePosition(c, h, pos)
+}
+
+// Delete delegate to HeroService and record the Revision
+func (hs *HeroService) Delete(c context.Context, id int64) (*service.Hero, error) {
+	h, err := hs.hs.Delete(c, id)
+	if err != nil {
+		return h, err
+	}
+
+	hs.record(c, "Delete", id, h, nil)
+	return h, nil
+}
+
+// Trash delegate to HeroService, if it is a TrashService
+func (hs *HeroService) Trash(c context.Context) ([]service.DeletedHero, error) {
+	ts, ok := hs.hs.(service.TrashService)
+	if !ok {
+		return nil, fmt.Errorf("trash is not supported by: %T", hs.hs)
+	}
+	return ts.Trash(c)
+}
+
+// Restore delegate to HeroService, if it is a TrashService and record the Revision
+func (hs *HeroService) Restore(c context.Context, id int64) (*service.Hero, error) {
+	ts, ok := hs.hs.(service.TrashService)
+	if !ok {
+		return nil, fmt.Errorf("trash is not supported by: %T", hs.hs)
+	}
+
+	h, err := ts.Restore(c, id)
+	if err != nil {
+		return h, err
+	}
+
+	hs.record(c, "Restore", id, nil, h)
+	return h, nil
+}
+
+// Purge delegate to HeroService, if it is a TrashService
+func (hs *HeroService) Purge(c context.Context, before time.Time) (int, error) {
+	ts, ok := hs.hs.(service.TrashService)
+	if !ok {
+		return 0, fmt.Errorf("trash is not supported by: %T", hs.hs)
+	}
+	return ts.Purge(c, before)
+}
+
+// Reset delegate to HeroService, if it is a ResetService and remove all Revisions
+func (hs *HeroService) Reset(c context.Context) error {
+	rs, ok := hs.hs.(service.ResetService)
+	if !ok {
+		return fmt.Errorf("reset is not supported by: %T", hs.hs)
+	}
+
+	err := rs.Reset(c)
+	if err == nil {
+		hs.mu.Lock()
+		hs.revisions = map[int64][]service.Revision{}
+		hs.mu.Unlock()
+	}
+	return err
+}
+
+// record a new Revision for the change from before to after (nil for: not exist)
+func (hs *HeroService) record(c context.Context, action string, id int64, before, after *service.Hero) {
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	revs := hs.revisions[id]
+
+	// the first change of an existing Hero (e.g. from the seed), save the initial state
+	if len(revs) == 0 && before != nil && action != "Add" {
+		revs = append(revs, service.Revision{
+			Rev:    1,
+			HeroID: id,
+			Action: "Initial",
+			Who:    "system",
+			Time:   time.Now(),
+			Diff:   []service.Change{},
+			Hero:   copyHero(before),
+		})
+	}
+
+	revs = append(revs, service.Revision{
+		Rev:    len(revs) + 1,
+		HeroID: id,
+		Action: action,
+		Who:    service.UserFromContext(c),
+		Time:   time.Now(),
+		Diff:   Diff(before, after),
+		Hero:   copyHero(after),
+	})
+	hs.revisions[id] = revs
+}
+
+// Diff returns the changed fields from before to after (nil for: not exist)
+func Diff(before, after *service.Hero) []service.Change {
+	o, n := fields(before), fields(after)
+
+	changes := []service.Change{}
+	for i, f := range fieldNames {
+		if o[i] != n[i] {
+			changes = append(changes, service.Change{Field: f, Old: o[i], New: n[i]})
+		}
+	}
+	return changes
+}
+
+var fieldNames = []string{"id", "name", "scoreData.name", "scoreData.city", "scoreData.country"}
+
+func fields(h *service.Hero) []string {
+	if h == nil {
+		return make([]string, len(fieldNames))
+	}
+	return []string{fmt.Sprintf("%d", h.ID), h.Name, h.ScoreData.Name, h.ScoreData.City, h.ScoreData.Country}
+}
+
+func copyHero(h *service.Hero) *service.Hero {
+	if h == nil {
+		return nil
+	}
+	c := *h
+	return &c
+}
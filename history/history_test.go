@@ -0,0 +1,115 @@
+package history
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestAddAndUpdateHistory(t *testing.T) {
+	hs := NewHeroService(db.NewMemService())
+
+	h, _ := hs.Add(context.TODO(), "Test")
+	_, _ = hs.Update(service.WithUser(context.TODO(), "mario"), service.Hero{ID: h.ID, Name: "Test2"})
+
+	revs, err := hs.History(context.TODO(), h.ID)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if len(revs) != 2 {
+		t.Fatalf("2 != %v", len(revs))
+	}
+	if revs[0].Action != "Add" || revs[0].Who != "anonymous" {
+		t.Errorf("expect Add by anonymous, got: %v", revs[0])
+	}
+	if revs[1].Rev != 2 || revs[1].Who != "mario" {
+		t.Errorf("expect Rev 2 by mario, got: %v", revs[1])
+	}
+	if len(revs[1].Diff) != 1 || revs[1].Diff[0].Old != "Test" || revs[1].Diff[0].New != "Test2" {
+		t.Errorf("expect diff name Test -> Test2, got: %v", revs[1].Diff)
+	}
+}
+
+func TestHistoryInitialRevision(t *testing.T) {
+	hs := NewHeroService(db.NewMemService())
+
+	revs, _ := hs.History(context.TODO(), 1)
+	if len(revs) != 0 {
+		t.Errorf("0 != %v", len(revs))
+	}
+
+	_, _ = hs.Update(context.TODO(), service.Hero{ID: 1, Name: "Test"})
+	revs, _ = hs.History(context.TODO(), 1)
+	if len(revs) != 2 {
+		t.Fatalf("2 != %v", len(revs))
+	}
+	if revs[0].Action != "Initial" || revs[0].Hero.Name != "Jasmin" {
+		t.Errorf("expect Initial with Jasmin, got: %v", revs[0])
+	}
+}
+
+func TestHistoryNotFound(t *testing.T) {
+	hs := NewHeroService(db.NewMemService())
+
+	_, err := hs.History(context.TODO(), 99)
+	if err != service.ErrHeroNotFound {
+		t.Errorf("expect ErrHeroNotFound, got: %v", err)
+	}
+}
+
+func TestRevert(t *testing.T) {
+	hs := NewHeroService(db.NewMemService())
+
+	_, _ = hs.Update(context.TODO(), service.Hero{ID: 1, Name: "Test"})
+	h, err := hs.Revert(context.TODO(), 1, 1)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+
+	// with ScoreData from the initial state
+	if h.Name != "Jasmin" || h.ScoreData.Name != "jasmin-roeper" {
+		t.Errorf("expect Jasmin with ScoreData, got: %v", h)
+	}
+
+	revs, _ := hs.History(context.TODO(), 1)
+	if len(revs) != 3 || revs[2].Action != "Revert" {
+		t.Errorf("expect Revert as third Revision, got: %v", revs)
+	}
+
+	_, err = hs.Revert(context.TODO(), 1, 9)
+	if err != service.ErrRevisionNotFound {
+		t.Errorf("expect ErrRevisionNotFound, got: %v", err)
+	}
+}
+
+func TestRevertDeleted(t *testing.T) {
+	hs := NewHeroService(db.NewMemService())
+
+	_, _ = hs.Delete(context.TODO(), 2)
+	h, err := hs.Revert(context.TODO(), 2, 1)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if h.Name != "Mario" {
+		t.Errorf("Mario != %v", h.Name)
+	}
+
+	_, err = hs.GetByID(context.TODO(), 2)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+}
+
+func TestDiff(t *testing.T) {
+	changes := Diff(nil, &service.Hero{ID: 1, Name: "Test"})
+	if len(changes) != 2 {
+		t.Errorf("2 != %v", len(changes))
+	}
+
+	changes = Diff(&service.Hero{ID: 1, Name: "Test"}, &service.Hero{ID: 1, Name: "Test"})
+	if len(changes) != 0 {
+		t.Errorf("0 != %v", len(changes))
+	}
+}
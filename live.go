@@ -0,0 +1,36 @@
+package main
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/lima1909/goheroes-appengine/live"
+	"github.com/lima1909/goheroes-appengine/policy"
+)
+
+// liveHub is the collaborative editing of the Hero list over WebSocket,
+// the commands need the same permissions as the API
+var liveHub = &live.Hub{
+	HeroService: app,
+	Events:      app.events,
+	Allow: func(c context.Context, command string) error {
+		if command == live.Move {
+			return allow(c, policy.MoveHero)
+		}
+		return allow(c, policy.UpdateHero)
+	},
+	// the same Origins as the CORS of the API
+	CheckOrigin: func(origin string) bool {
+		return app.cors.AllowOrigin(origin)
+	},
+}
+
+// liveHeroes accept the WebSocket connections for the collaborative editing,
+// it returns 501 in the App Engine runtime (the standard environment has no WebSockets)
+func liveHeroes(w http.ResponseWriter, r *http.Request) {
+	if app.RunInCloud {
+		http.Error(w, "WebSockets are not supported in the App Engine runtime", http.StatusNotImplemented)
+		return
+	}
+	liveHub.Handler(newContext).ServeHTTP(w, r)
+}
@@ -0,0 +1,417 @@
+// Package live is the collaborative editing of the Hero list over WebSocket: the clients subscribe the list
+// (of the tenant), receive the changes as diffs and send move and update commands. The commands of all clients
+// are applied one after the other through the HeroService, so every client sees the same order of changes.
+//
+// Every change of the list increments the version. A command contains the version, which the client has seen,
+// if the Hero was changed after this version, the command is rejected (conflict) and the client gets a new snapshot.
+//
+// The messages (JSON):
+//
+//	client: {"type": "subscribe"}
+//	client: {"type": "move", "ref": "1", "version": 5, "id": 3, "pos": 0}
+//	client: {"type": "update", "ref": "2", "version": 5, "hero": {"id": 3, "name": "Jasmin", "scoreData": {...}}}
+//	server: {"type": "snapshot", "version": 5, "heroes": [...]}
+//	server: {"type": "diff", "version": 6, "removed": [4], "changed": [...], "order": [3, 1, 2]}
+//	server: {"type": "ack", "ref": "1", "version": 6, "hero": {...}}
+//	server: {"type": "conflict", "ref": "1", "version": 6, "error": "..."}
+//	server: {"type": "error", "ref": "1", "error": "..."}
+package live
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/http"
+	"sync"
+
+	"github.com/lima1909/goheroes-appengine/events"
+	"github.com/lima1909/goheroes-appengine/service"
+	"golang.org/x/net/websocket"
+)
+
+// the message types
+const (
+	Subscribe = "subscribe"
+	Move      = "move"
+	Update    = "update"
+	Snapshot  = "snapshot"
+	Diff      = "diff"
+	Ack       = "ack"
+	Conflict  = "conflict"
+	Error     = "error"
+)
+
+// the size of the send buffer of a client, a client which is too slow is disconnected
+const sendBuffer = 64
+
+// Message between client and server, the fields depend on the type
+type Message struct {
+	Type    string `json:"type"`
+	Ref     string `json:"ref,omitempty"`
+	Version int64  `json:"version,omitempty"`
+
+	// move (id, pos) and update (hero)
+	ID   int64 `json:"id,omitempty"`
+	Pos  int64 `json:"pos,omitempty"`
+	Hero *Hero `json:"hero,omitempty"`
+
+	// snapshot
+	Heroes []Hero `json:"heroes,omitempty"`
+	// diff: the removed IDs, the added or changed Heroes and the new order of the IDs (only if it is changed)
+	Removed []int64 `json:"removed,omitempty"`
+	Changed []Hero  `json:"changed,omitempty"`
+	Order   []int64 `json:"order,omitempty"`
+
+	Error string `json:"error,omitempty"`
+}
+
+// Hero with the ScoreData (like the API v2)
+type Hero struct {
+	ID        int64     `json:"id"`
+	Name      string    `json:"name"`
+	ScoreData ScoreData `json:"scoreData"`
+}
+
+// ScoreData of the Hero
+type ScoreData struct {
+	Name    string `json:"name"`
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+// NewHero convert the service.Hero
+func NewHero(h service.Hero) Hero {
+	return Hero{ID: h.ID, Name: h.Name, ScoreData: ScoreData(h.ScoreData)}
+}
+
+// Hero convert to a service.Hero
+func (h Hero) Hero() service.Hero {
+	return service.Hero{ID: h.ID, Name: h.Name, ScoreData: service.ScoreData(h.ScoreData)}
+}
+
+// Hub contains the rooms (one per tenant) with the connected clients
+type Hub struct {
+	HeroService service.HeroService
+	// Events are the changes of the other APIs (nil: only the changes of the commands)
+	Events *events.Broker
+	// Allow check the permission of the command (move or update), nil: all commands are allowed
+	Allow func(c context.Context, command string) error
+	// CheckOrigin check the Origin of the browser (a request without Origin is accepted), nil: all Origins are accepted
+	CheckOrigin func(origin string) bool
+
+	mu    sync.Mutex
+	rooms map[string]*room
+}
+
+// Handler accept the WebSocket connections, newContext create the Context of the connection (for the HeroService)
+func (h *Hub) Handler(newContext func(r *http.Request) context.Context) http.Handler {
+	return websocket.Server{
+		// the browser send the Origin for the WebSocket without the CORS preflight
+		Handshake: func(_ *websocket.Config, r *http.Request) error {
+			if origin := r.Header.Get("Origin"); origin != "" && h.CheckOrigin != nil && !h.CheckOrigin(origin) {
+				return fmt.Errorf("origin not allowed: %s", origin)
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			h.serve(newContext(ws.Request()), ws)
+		},
+	}
+}
+
+// serve read the commands of the client and write the messages of the room
+func (h *Hub) serve(c context.Context, ws *websocket.Conn) {
+	cl := &client{c: c, send: make(chan Message, sendBuffer)}
+	r := h.join(service.TenantFromContext(c), cl)
+	defer h.leave(r, cl)
+
+	go func() {
+		defer ws.Close()
+		for m := range cl.send {
+			if err := websocket.JSON.Send(ws, m); err != nil {
+				return
+			}
+		}
+	}()
+
+	for {
+		m := Message{}
+		if err := websocket.JSON.Receive(ws, &m); err != nil {
+			return
+		}
+		r.commands <- command{client: cl, msg: m}
+	}
+}
+
+// join the client in the room of the tenant, the room is started with the first client
+func (h *Hub) join(tenant string, cl *client) *room {
+	h.mu.Lock()
+	r, ok := h.rooms[tenant]
+	if !ok {
+		if h.rooms == nil {
+			h.rooms = map[string]*room{}
+		}
+		r = newRoom(h, tenant)
+		h.rooms[tenant] = r
+		go r.run()
+	}
+	r.refs++
+	h.mu.Unlock()
+
+	r.join <- cl
+	return r
+}
+
+// leave the room, the room is stopped with the last client
+func (h *Hub) leave(r *room, cl *client) {
+	r.leave <- cl
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	r.refs--
+	if r.refs == 0 {
+		delete(h.rooms, r.tenant)
+		close(r.done)
+	}
+}
+
+type client struct {
+	c          context.Context
+	send       chan Message
+	subscribed bool
+	closed     bool
+}
+
+type command struct {
+	client *client
+	msg    Message
+}
+
+// room of a tenant, all fields (without refs) are only used by the goroutine run
+type room struct {
+	hub    *Hub
+	tenant string
+	// refs are the joined clients (guarded by hub.mu)
+	refs int
+
+	join, leave chan *client
+	commands    chan command
+	done        chan struct{}
+
+	clients map[*client]bool
+	loaded  bool
+	list    []service.Hero
+	version int64
+	// heroVersions is the version of the last change by Hero ID
+	heroVersions map[int64]int64
+}
+
+func newRoom(h *Hub, tenant string) *room {
+	return &room{
+		hub:          h,
+		tenant:       tenant,
+		join:         make(chan *client),
+		leave:        make(chan *client),
+		commands:     make(chan command),
+		done:         make(chan struct{}),
+		clients:      map[*client]bool{},
+		heroVersions: map[int64]int64{},
+	}
+}
+
+func (r *room) run() {
+	var changes <-chan events.Event
+	cancel := func() {}
+	if r.hub.Events != nil {
+		_, changes, cancel, _ = r.hub.Events.Subscribe(r.tenant, 0)
+	}
+	defer func() { cancel() }()
+
+	for {
+		select {
+		case cl := <-r.join:
+			r.clients[cl] = true
+		case cl := <-r.leave:
+			delete(r.clients, cl)
+			r.close(cl)
+		case cmd := <-r.commands:
+			r.apply(cmd.client, cmd.msg)
+		case e, ok := <-changes:
+			if !ok {
+				// too slow for the Broker: subscribe again and load the changed list
+				_, changes, cancel, _ = r.hub.Events.Subscribe(r.tenant, 0)
+				r.refresh(r.context())
+				continue
+			}
+			r.refresh(r.context(), e.Protocol.HeroID)
+		case <-r.done:
+			return
+		}
+	}
+}
+
+// apply the command of the client
+func (r *room) apply(cl *client, m Message) {
+	switch m.Type {
+	case Subscribe:
+		if !r.loaded {
+			r.refresh(cl.c)
+		}
+		cl.subscribed = true
+		r.send(cl, r.snapshot())
+		return
+	case Move:
+	case Update:
+		if m.Hero == nil {
+			r.send(cl, Message{Type: Error, Ref: m.Ref, Error: "the hero is required"})
+			return
+		}
+		m.ID = m.Hero.ID
+	default:
+		r.send(cl, Message{Type: Error, Ref: m.Ref, Error: "invalid message type: " + m.Type})
+		return
+	}
+
+	if allow := r.hub.Allow; allow != nil {
+		if err := allow(cl.c, m.Type); err != nil {
+			r.send(cl, Message{Type: Error, Ref: m.Ref, Error: err.Error()})
+			return
+		}
+	}
+
+	if v := r.heroVersions[m.ID]; v > m.Version {
+		r.send(cl, Message{Type: Conflict, Ref: m.Ref, Version: r.version,
+			Error: "the Hero is changed by another client, the command is based on an old version"})
+		if cl.subscribed {
+			r.send(cl, r.snapshot())
+		}
+		return
+	}
+
+	var h *service.Hero
+	var err error
+	if m.Type == Move {
+		if h, err = r.hub.HeroService.GetByID(cl.c, m.ID); err == nil {
+			h, err = r.hub.HeroService.UpdatePosition(cl.c, *h, m.Pos)
+		}
+	} else {
+		h, err = r.hub.HeroService.Update(cl.c, m.Hero.Hero())
+	}
+	if err != nil {
+		r.send(cl, Message{Type: Error, Ref: m.Ref, Error: err.Error()})
+		return
+	}
+
+	r.refresh(cl.c, m.ID)
+	hero := NewHero(*h)
+	r.send(cl, Message{Type: Ack, Ref: m.Ref, Version: r.version, Hero: &hero})
+}
+
+// refresh load the list and send the diff to the subscribed clients,
+// the touched Heroes (and all changed Heroes) get the new version
+func (r *room) refresh(c context.Context, touched ...int64) {
+	if c == nil {
+		return
+	}
+	list, err := r.hub.HeroService.List(c, "")
+	if err != nil {
+		log.Printf("can not load the Heroes for the live clients: %v", err)
+		return
+	}
+	if !r.loaded {
+		r.loaded, r.list, r.version = true, list, 1
+		return
+	}
+
+	d := diff(r.list, list)
+	if len(d.Removed) == 0 && len(d.Changed) == 0 && len(d.Order) == 0 {
+		return
+	}
+	r.version++
+	r.list = list
+	d.Version = r.version
+
+	for _, h := range d.Changed {
+		r.heroVersions[h.ID] = r.version
+	}
+	if len(d.Order) > 0 {
+		for _, id := range touched {
+			r.heroVersions[id] = r.version
+		}
+	}
+
+	for cl := range r.clients {
+		if cl.subscribed {
+			r.send(cl, d)
+		}
+	}
+}
+
+// context of a connected client (for the changes of the other APIs), nil: no client
+func (r *room) context() context.Context {
+	for cl := range r.clients {
+		return cl.c
+	}
+	return nil
+}
+
+func (r *room) snapshot() Message {
+	heroes := make([]Hero, len(r.list))
+	for i, h := range r.list {
+		heroes[i] = NewHero(h)
+	}
+	return Message{Type: Snapshot, Version: r.version, Heroes: heroes}
+}
+
+// send the message to the client, a client which is too slow is disconnected
+func (r *room) send(cl *client, m Message) {
+	if cl.closed {
+		return
+	}
+	select {
+	case cl.send <- m:
+	default:
+		r.close(cl)
+	}
+}
+
+func (r *room) close(cl *client) {
+	if !cl.closed {
+		cl.closed = true
+		close(cl.send)
+	}
+}
+
+// diff of the two lists
+func diff(old, current []service.Hero) Message {
+	d := Message{Type: Diff}
+
+	before := map[int64]service.Hero{}
+	for _, h := range old {
+		before[h.ID] = h
+	}
+	after := map[int64]bool{}
+	sameOrder := len(old) == len(current)
+	for i, h := range current {
+		after[h.ID] = true
+		if b, ok := before[h.ID]; !ok || b != h {
+			d.Changed = append(d.Changed, NewHero(h))
+		}
+		if sameOrder && old[i].ID != h.ID {
+			sameOrder = false
+		}
+	}
+	for _, h := range old {
+		if !after[h.ID] {
+			d.Removed = append(d.Removed, h.ID)
+		}
+	}
+
+	if !sameOrder {
+		d.Order = make([]int64, len(current))
+		for i, h := range current {
+			d.Order[i] = h.ID
+		}
+	}
+	return d
+}
@@ -0,0 +1,219 @@
+package live
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/events"
+	"github.com/lima1909/goheroes-appengine/service"
+	"golang.org/x/net/websocket"
+)
+
+func newServer(hub *Hub) *httptest.Server {
+	return httptest.NewServer(hub.Handler(func(r *http.Request) context.Context { return r.Context() }))
+}
+
+func dial(t *testing.T, url string) *websocket.Conn {
+	ws, err := websocket.Dial(strings.Replace(url, "http", "ws", 1), "", "http://localhost/")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	ws.SetDeadline(time.Now().Add(5 * time.Second))
+	return ws
+}
+
+func send(t *testing.T, ws *websocket.Conn, m Message) {
+	if err := websocket.JSON.Send(ws, m); err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+}
+
+// receive the next message of the type, the other messages are skipped
+func receive(t *testing.T, ws *websocket.Conn, msgType string) Message {
+	for {
+		m := Message{}
+		if err := websocket.JSON.Receive(ws, &m); err != nil {
+			t.Fatalf("No err expected (wait for %s): %v", msgType, err)
+		}
+		if m.Type == msgType {
+			return m
+		}
+	}
+}
+
+func ids(heroes []Hero) []int64 {
+	result := make([]int64, len(heroes))
+	for i, h := range heroes {
+		result[i] = h.ID
+	}
+	return result
+}
+
+func TestDiff(t *testing.T) {
+	a, b, c := service.Hero{ID: 1, Name: "a"}, service.Hero{ID: 2, Name: "b"}, service.Hero{ID: 3, Name: "c"}
+	changed := service.Hero{ID: 2, Name: "changed"}
+
+	for _, tc := range []struct {
+		old, current []service.Hero
+		removed      []int64
+		changed      []int64
+		order        []int64
+	}{
+		{[]service.Hero{a, b}, []service.Hero{a, b}, nil, nil, nil},
+		{[]service.Hero{a, b}, []service.Hero{b, a}, nil, nil, []int64{2, 1}},
+		{[]service.Hero{a, b}, []service.Hero{a, changed}, nil, []int64{2}, nil},
+		{[]service.Hero{a, b}, []service.Hero{a, b, c}, nil, []int64{3}, []int64{1, 2, 3}},
+		{[]service.Hero{a, b, c}, []service.Hero{a, c}, []int64{2}, nil, []int64{1, 3}},
+	} {
+		d := diff(tc.old, tc.current)
+		if !reflect.DeepEqual(d.Removed, tc.removed) || !reflect.DeepEqual(ids(d.Changed), ids(heroesOf(tc.current, tc.changed))) ||
+			!reflect.DeepEqual(d.Order, tc.order) {
+			t.Errorf("unexpected diff: %v -> %v: %v", tc.old, tc.current, d)
+		}
+	}
+}
+
+func heroesOf(heroes []service.Hero, ids []int64) []Hero {
+	result := make([]Hero, 0)
+	for _, id := range ids {
+		for _, h := range heroes {
+			if h.ID == id {
+				result = append(result, NewHero(h))
+			}
+		}
+	}
+	return result
+}
+
+func TestMoveAndConflict(t *testing.T) {
+	server := newServer(&Hub{HeroService: db.NewMemService()})
+	defer server.Close()
+
+	mario, jasmin := dial(t, server.URL), dial(t, server.URL)
+	defer mario.Close()
+	defer jasmin.Close()
+
+	send(t, mario, Message{Type: Subscribe})
+	snap := receive(t, mario, Snapshot)
+	send(t, jasmin, Message{Type: Subscribe})
+	if s := receive(t, jasmin, Snapshot); s.Version != snap.Version || !reflect.DeepEqual(ids(s.Heroes), ids(snap.Heroes)) {
+		t.Fatalf("expect the same snapshot: %v != %v", snap, s)
+	}
+
+	// both move the last Hero, the second move is based on an old version
+	last := snap.Heroes[len(snap.Heroes)-1].ID
+	send(t, mario, Message{Type: Move, Ref: "m1", Version: snap.Version, ID: last, Pos: 0})
+	ack := receive(t, mario, Ack)
+	if ack.Ref != "m1" || ack.Version != snap.Version+1 || ack.Hero.ID != last {
+		t.Errorf("unexpected ack: %v", ack)
+	}
+
+	d := receive(t, jasmin, Diff)
+	if d.Version != ack.Version || len(d.Order) != len(snap.Heroes) || d.Order[0] != last {
+		t.Errorf("unexpected diff: %v", d)
+	}
+
+	send(t, jasmin, Message{Type: Move, Ref: "j1", Version: snap.Version, ID: last, Pos: 1})
+	if c := receive(t, jasmin, Conflict); c.Ref != "j1" || c.Version != ack.Version {
+		t.Errorf("unexpected conflict: %v", c)
+	}
+	resync := receive(t, jasmin, Snapshot)
+	if resync.Version != ack.Version || resync.Heroes[0].ID != last {
+		t.Errorf("expect the new snapshot, got: %v", resync)
+	}
+
+	// with the current version, the move is applied
+	send(t, jasmin, Message{Type: Move, Ref: "j2", Version: resync.Version, ID: last, Pos: 1})
+	if a := receive(t, jasmin, Ack); a.Version != resync.Version+1 {
+		t.Errorf("unexpected ack: %v", a)
+	}
+	if d := receive(t, mario, Diff); d.Order[1] != last {
+		t.Errorf("unexpected diff: %v", d)
+	}
+
+	send(t, mario, Message{Type: Move, Ref: "m2", Version: resync.Version + 1, ID: last, Pos: 9999})
+	if e := receive(t, mario, Error); e.Ref != "m2" || e.Error != service.ErrPosNotFound.Error() {
+		t.Errorf("unexpected error: %v", e)
+	}
+}
+
+func TestUpdateAndEvents(t *testing.T) {
+	broker := events.NewBroker(events.DefaultBufferSize)
+	hs := events.NewHeroService(db.NewMemService(), broker)
+	server := newServer(&Hub{HeroService: hs, Events: broker})
+	defer server.Close()
+
+	ws := dial(t, server.URL)
+	defer ws.Close()
+	send(t, ws, Message{Type: Subscribe})
+	snap := receive(t, ws, Snapshot)
+
+	h := snap.Heroes[0]
+	h.ScoreData.City = "Berlin"
+	send(t, ws, Message{Type: Update, Ref: "u1", Version: snap.Version, Hero: &h})
+	d := receive(t, ws, Diff)
+	if len(d.Changed) != 1 || d.Changed[0] != h || d.Order != nil {
+		t.Errorf("unexpected diff: %v", d)
+	}
+	receive(t, ws, Ack)
+
+	// the changes of the other APIs
+	added, err := hs.Add(context.Background(), "Live")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	d = receive(t, ws, Diff)
+	if len(d.Changed) != 1 || d.Changed[0].ID != added.ID || d.Order[len(d.Order)-1] != added.ID {
+		t.Errorf("unexpected diff: %v", d)
+	}
+
+	send(t, ws, Message{Type: Update, Ref: "u2"})
+	if e := receive(t, ws, Error); e.Ref != "u2" {
+		t.Errorf("unexpected error: %v", e)
+	}
+	send(t, ws, Message{Type: "invalid", Ref: "x"})
+	if e := receive(t, ws, Error); e.Ref != "x" {
+		t.Errorf("unexpected error: %v", e)
+	}
+}
+
+func TestCheckOrigin(t *testing.T) {
+	server := newServer(&Hub{
+		HeroService: db.NewMemService(),
+		CheckOrigin: func(origin string) bool { return origin == "http://localhost/" },
+	})
+	defer server.Close()
+
+	ws := dial(t, server.URL)
+	ws.Close()
+	if _, err := websocket.Dial(strings.Replace(server.URL, "http", "ws", 1), "", "https://evil.com/"); err == nil {
+		t.Errorf("expect an error for the not allowed origin")
+	}
+}
+
+func TestAllow(t *testing.T) {
+	server := newServer(&Hub{
+		HeroService: db.NewMemService(),
+		Allow: func(c context.Context, command string) error {
+			if command == Move {
+				return errors.New("permission: heroes:move required")
+			}
+			return nil
+		},
+	})
+	defer server.Close()
+
+	ws := dial(t, server.URL)
+	defer ws.Close()
+	send(t, ws, Message{Type: Move, Ref: "m", ID: 1, Pos: 0})
+	if e := receive(t, ws, Error); e.Error != "permission: heroes:move required" {
+		t.Errorf("unexpected error: %v", e)
+	}
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/live"
+	"golang.org/x/net/websocket"
+)
+
+func TestLiveHeroes(t *testing.T) {
+	resetTestHeroes(t)
+
+	ws, err := websocket.Dial(strings.Replace(server.URL, "http", "ws", 1)+"/api/v2/heroes/live", "", server.URL)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	defer ws.Close()
+	ws.SetDeadline(time.Now().Add(5 * time.Second))
+
+	if err = websocket.JSON.Send(ws, live.Message{Type: live.Subscribe}); err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	snap := live.Message{}
+	if err = websocket.JSON.Receive(ws, &snap); err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+
+	heroes, _ := app.List(context.TODO(), "")
+	if snap.Type != live.Snapshot || len(snap.Heroes) != len(heroes) {
+		t.Errorf("expect the snapshot with %v Heroes, got: %v", len(heroes), snap)
+	}
+}
+
+func TestLiveHeroesInCloud(t *testing.T) {
+	app.RunInCloud = true
+	defer func() { app.RunInCloud = false }()
+
+	resp, err := http.Get(server.URL + "/api/v2/heroes/live")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("expect 501 in the App Engine runtime, got: %v", resp.StatusCode)
+	}
+}
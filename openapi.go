@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+
+	"github.com/gorilla/mux"
+	"github.com/lima1909/goheroes-appengine/events"
+	"github.com/lima1909/goheroes-appengine/openapi"
+	"github.com/lima1909/goheroes-appengine/service"
+	"github.com/lima1909/goheroes-appengine/webhook"
+)
+
+// EnvOpenAPIValidate is the Env-Variable, if it is true, the API requests are validated against the OpenAPI document
+const EnvOpenAPIValidate = "HEROES_OPENAPI_VALIDATE"
+
+// SchemaName impl from openapi.Namer
+func (heroV2) SchemaName() string { return "Hero" }
+
+// SchemaName impl from openapi.Namer
+func (scoreDataV2) SchemaName() string { return "ScoreData" }
+
+// SchemaName impl from openapi.Namer
+func (deletedHeroV2) SchemaName() string { return "DeletedHero" }
+
+// SchemaName impl from openapi.Namer
+func (revisionV2) SchemaName() string { return "Revision" }
+
+// SchemaName impl from openapi.Namer
+func (eventV2) SchemaName() string { return "Event" }
+
+// routeDocs is the documentation of the routes (by route name) for the API version
+func routeDocs(version string) map[string]openapi.Doc {
+	var hero, heroes, trash, revisions, event interface{} = service.Hero{}, []service.Hero{}, []service.DeletedHero{}, []service.Revision{}, events.Event{}
+	if version == V2 {
+		hero, heroes, trash, revisions, event = heroV2{}, []heroV2{}, []deletedHeroV2{}, []revisionV2{}, eventV2{}
+	}
+
+	query := func(name, description string, schema *openapi.Schema) openapi.Parameter {
+		return openapi.Parameter{Name: name, In: "query", Description: description, Schema: schema}
+	}
+	str := &openapi.Schema{Type: "string"}
+	integer := &openapi.Schema{Type: "integer", Format: "int64"}
+	list := []openapi.Parameter{
+		query("name", "filter the Heroes by name", str),
+		query("page", "the page (1 ... n)", integer),
+		query("size", "the size of a page", integer),
+	}
+	dateTime := &openapi.Schema{Type: "string", Format: "date-time"}
+	protocols := []openapi.Parameter{
+		query("action", "filter the Protocols by action, e.g. Add", str),
+		query("heroID", "filter the Protocols by Hero ID", integer),
+		query("from", "the Protocols since the time (inclusive)", dateTime),
+		query("to", "the Protocols until the time (exclusive)", dateTime),
+		query("q", "search the text in the note", str),
+		list[1],
+		list[2],
+	}
+
+	return map[string]openapi.Doc{
+		"heroes.list":                {Summary: "List the Heroes", Query: list, Response: heroes},
+		"heroes.list.slash":          {Summary: "List the Heroes", Query: list, Response: heroes},
+		"heroes.add":                 {Summary: "Add a new Hero with the name", Body: "", Response: hero},
+		"heroes.update":              {Summary: "Update the Hero", Body: hero, Response: hero},
+		"heroes.move":                {Summary: "Move the Hero to the position", Body: hero, Response: hero},
+		"heroes.invalid":             {Hidden: true},
+		"heroes.trash":               {Summary: "List the deleted Heroes", Response: trash},
+		"heroes.scores":              {Summary: "The scores of the Heroes (Hero ID to score) from 8a.nu", Response: map[int64]int{}},
+		"heroes.live":                {Hidden: true}, // WebSocket (see: README), 501 in the App Engine runtime
+		"heroes.events":              {Summary: "The changes of the Heroes as Server-Sent Events (Last-Event-ID: resume)", Response: event, Stream: true},
+		"hero.get":                   {Summary: "Get the Hero by ID", Response: hero},
+		"hero.delete":                {Summary: "Delete the Hero (move the Hero to the trash)", Response: hero},
+		"hero.invalid":               {Hidden: true},
+		"hero.restore":               {Summary: "Restore the deleted Hero from the trash", Response: hero},
+		"hero.history":               {Summary: "List the Revisions of the Hero", Response: revisions},
+		"hero.revert":                {Summary: "Revert the Hero to the Revision", Response: hero},
+		"teams.list":                 {Summary: "List the Teams", Response: []service.Team{}},
+		"teams.add":                  {Summary: "Add a new Team with the name", Body: "", Response: service.Team{}},
+		"teams.update":               {Summary: "Update the Team", Body: service.Team{}, Response: service.Team{}},
+		"team.get":                   {Summary: "Get the Team by ID", Response: service.Team{}},
+		"team.delete":                {Summary: "Delete the Team (the Heroes are not deleted)", Response: service.Team{}},
+		"team.heroes":                {Summary: "List the Heroes of the Team", Response: heroes},
+		"team.hero.add":              {Summary: "Add the Hero to the Team", Response: hero},
+		"team.hero.move":             {Summary: "Move the Hero in the Team to the position", Response: hero},
+		"team.hero.remove":           {Summary: "Remove the Hero from the Team", Response: hero},
+		"admin.reset":                {Summary: "Reset the Heroes to the seed Heroes", Methods: []string{"POST"}, Response: heroes},
+		"protocol":                   {Summary: "List the Protocols of the changes (newest first)", Query: protocols, Response: []service.Protocol{}},
+		"protocol.count":             {Summary: "The number of the Protocols", Query: protocols[:5], Response: protocolCount{}},
+		"webhooks.list":              {Summary: "List the webhooks (without the secrets)", Response: []webhook.Subscription{}},
+		"webhooks.add":               {Summary: "Add a webhook (the response contains the secret)", Body: webhookBody{}, Response: webhook.Subscription{}},
+		"webhook.get":                {Summary: "Get the webhook by ID", Response: webhook.Subscription{}},
+		"webhook.update":             {Summary: "Update the url and the events (and the secret) of the webhook", Body: webhookBody{}, Response: webhook.Subscription{}},
+		"webhook.delete":             {Summary: "Delete the webhook", Response: webhook.Subscription{}},
+		"admin.webhooks.deliveries":  {Summary: "The last deliveries of the webhooks with all attempts", Response: []webhook.Delivery{}},
+		"admin.webhooks.deadletters": {Summary: "The failed deliveries after all attempts", Response: []webhook.Delivery{}},
+		"admin.webhooks.redeliver":   {Summary: "Redeliver the failed delivery", Response: webhook.Delivery{}},
+		"openapi":                    {Summary: "This OpenAPI document", Response: map[string]interface{}{}},
+	}
+}
+
+// openAPIDocument create the OpenAPI document of the API version
+func openAPIDocument(version string) (*openapi.Document, []string, error) {
+	prefix := "/api"
+	if version == V2 {
+		prefix = "/api/" + V2
+	}
+
+	b := openapi.Builder{
+		Info: openapi.Info{
+			Title:       "Backend for Angular: Tour of Heroes",
+			Description: "Get a backend for an interactive application, write in Angular.",
+			Version:     version,
+		},
+		Prefix: prefix,
+		Vars:   map[string]string{"version": prefix[len("/api"):]},
+	}
+	return b.Build(routes, routeDocs(version))
+}
+
+func openAPI(w http.ResponseWriter, r *http.Request) {
+	doc, _, err := openAPIDocument(apiVersion(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeToClient(w, r, doc)
+}
+
+// openAPIDocuments cache the OpenAPI documents of the versions for the current routes
+type openAPIDocuments struct {
+	mu     sync.Mutex
+	routes *mux.Router
+	docs   map[string]*openapi.Document
+}
+
+func (d *openAPIDocuments) document(r *http.Request) *openapi.Document {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	if d.routes != routes {
+		d.routes, d.docs = routes, map[string]*openapi.Document{}
+	}
+
+	version := apiVersion(r)
+	doc, ok := d.docs[version]
+	if !ok {
+		var err error
+		if doc, _, err = openAPIDocument(version); err != nil {
+			log.Printf("can not create the OpenAPI document: %v\n", err)
+			return nil
+		}
+		d.docs[version] = doc
+	}
+	return doc
+}
+
+// newOpenAPIValidator create a Validator for the requests and (for tests) the responses
+func newOpenAPIValidator(responses bool) *openapi.Validator {
+	docs := &openAPIDocuments{}
+	return &openapi.Validator{Document: docs.document, Vars: []string{"version"}, Responses: responses}
+}
+
+// openAPIValidatorFromEnv create a request Validator, if the EnvOpenAPIValidate is true
+func openAPIValidatorFromEnv() (*openapi.Validator, error) {
+	v := os.Getenv(EnvOpenAPIValidate)
+	if v == "" {
+		return nil, nil
+	}
+	b, err := strconv.ParseBool(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %s", EnvOpenAPIValidate, v)
+	}
+	if !b {
+		return nil, nil
+	}
+	return newOpenAPIValidator(false), nil
+}
@@ -0,0 +1,311 @@
+// Package openapi generate the OpenAPI 3 document from the routes of the router
+// and the Go types of the request and response bodies.
+// See: https://spec.openapis.org/oas/v3.0.3
+package openapi
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+)
+
+// Version of the OpenAPI specification
+const Version = "3.0.3"
+
+// EventStream is the media type of the Server-Sent Events
+const EventStream = "text/event-stream"
+
+// Document is the OpenAPI document
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+}
+
+// Info of the API
+type Info struct {
+	Title       string `json:"title"`
+	Description string `json:"description,omitempty"`
+	Version     string `json:"version"`
+}
+
+// PathItem contains the Operations by method (lower case)
+type PathItem map[string]*Operation
+
+// Operation is one method of a path
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Summary     string              `json:"summary,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter in the path or the query
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody of an Operation
+type RequestBody struct {
+	Required bool                 `json:"required"`
+	Content  map[string]MediaType `json:"content"`
+}
+
+// Response of an Operation
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType with the Schema of the content
+type MediaType struct {
+	Schema *Schema `json:"schema"`
+}
+
+// Components contains the Schemas of the types
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// Doc is the documentation of a route (by route name)
+type Doc struct {
+	Summary string
+	// Methods for routes without methods (default: GET)
+	Methods []string
+	// Query are the optional query parameters (the queries of the route are added as required)
+	Query []Parameter
+	// Body is a value of the type of the request body (string: text/plain, other: application/json)
+	Body interface{}
+	// Response is a value of the type of the response body (nil: no body)
+	Response interface{}
+	// Stream responses are Server-Sent Events (text/event-stream), the Response is the type of the event data
+	Stream bool
+	// Hidden routes are not in the document (e.g. the answers for invalid methods)
+	Hidden bool
+}
+
+// Builder create the Document
+type Builder struct {
+	Info Info
+	// Prefix of the paths in the Document, other routes are ignored (e.g. /api)
+	Prefix string
+	// Vars are values of the route variables, which are not path parameters (e.g. the version)
+	Vars map[string]string
+}
+
+// Build the Document with the routes of the router and the Docs (by route name),
+// it returns the names of all routes (with the Prefix) without Doc
+func (b *Builder) Build(router *mux.Router, docs map[string]Doc) (*Document, []string, error) {
+	doc := &Document{
+		OpenAPI:    Version,
+		Info:       b.Info,
+		Paths:      map[string]PathItem{},
+		Components: Components{Schemas: map[string]*Schema{}},
+	}
+	undocumented := []string{}
+
+	err := router.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+		tmpl, err := route.GetPathTemplate()
+		if err != nil {
+			return nil
+		}
+		path, params := Path(tmpl, b.Vars)
+		if !strings.HasPrefix(path, b.Prefix) {
+			return nil
+		}
+
+		name := route.GetName()
+		d, ok := docs[name]
+		if !ok {
+			undocumented = append(undocumented, name)
+		}
+		if d.Hidden {
+			return nil
+		}
+
+		methods, err := route.GetMethods()
+		if err != nil {
+			methods = d.Methods
+		}
+		if len(methods) == 0 {
+			methods = []string{http.MethodGet}
+		}
+
+		queries, _ := route.GetQueriesTemplates()
+		for _, m := range methods {
+			op := &Operation{OperationID: name, Summary: d.Summary, Parameters: append([]Parameter{}, params...)}
+			for _, q := range queries {
+				op.Parameters = append(op.Parameters, queryParameter(q))
+			}
+			op.Parameters = append(op.Parameters, d.Query...)
+			op.RequestBody = requestBody(doc.Components.Schemas, d.Body)
+			op.Responses = responses(doc.Components.Schemas, d.Response, d.Stream)
+
+			item, ok := doc.Paths[path]
+			if !ok {
+				item = PathItem{}
+				doc.Paths[path] = item
+			}
+			m = strings.ToLower(m)
+			if existing, ok := item[m]; ok {
+				merge(existing, op)
+			} else {
+				item[m] = op
+			}
+		}
+		return nil
+	})
+
+	return doc, undocumented, err
+}
+
+// Path convert the mux path template in the OpenAPI path and returns the path Parameters,
+// vars are the values of the route variables, which are not path Parameters
+func Path(tmpl string, vars map[string]string) (string, []Parameter) {
+	path := &bytes.Buffer{}
+	params := []Parameter{}
+
+	for i := 0; i < len(tmpl); i++ {
+		if tmpl[i] != '{' {
+			path.WriteByte(tmpl[i])
+			continue
+		}
+		end := closing(tmpl, i)
+		name, regexp := splitVar(tmpl[i+1 : end])
+		i = end
+
+		if v, ok := vars[name]; ok {
+			path.WriteString(v)
+			continue
+		}
+		path.WriteString("{" + name + "}")
+		params = append(params, Parameter{Name: name, In: "path", Required: true, Schema: varSchema(regexp)})
+	}
+	return path.String(), params
+}
+
+// closing returns the index of the closing brace of the variable, which start at i
+func closing(tmpl string, i int) int {
+	depth := 0
+	for j := i; j < len(tmpl); j++ {
+		switch tmpl[j] {
+		case '{':
+			depth++
+		case '}':
+			depth--
+			if depth == 0 {
+				return j
+			}
+		}
+	}
+	return len(tmpl) - 1
+}
+
+// splitVar split name:regexp
+func splitVar(v string) (string, string) {
+	if i := strings.Index(v, ":"); i != -1 {
+		return v[:i], v[i+1:]
+	}
+	return v, ""
+}
+
+func varSchema(regexp string) *Schema {
+	if regexp == "[0-9]+" {
+		return &Schema{Type: "integer", Format: "int64"}
+	}
+	return &Schema{Type: "string"}
+}
+
+// queryParameter of a query template: name={name:regexp}
+func queryParameter(q string) Parameter {
+	name := q
+	regexp := ""
+	if i := strings.Index(q, "="); i != -1 {
+		name = q[:i]
+		_, regexp = splitVar(strings.Trim(q[i+1:], "{}"))
+	}
+	schema := varSchema(regexp)
+	if regexp == "" {
+		schema = &Schema{Type: "string"}
+	}
+	return Parameter{Name: name, In: "query", Required: true, Schema: schema}
+}
+
+func requestBody(schemas map[string]*Schema, body interface{}) *RequestBody {
+	if body == nil {
+		return nil
+	}
+	contentType := "application/json"
+	if _, ok := body.(string); ok {
+		contentType = "text/plain"
+	}
+	return &RequestBody{Required: true, Content: map[string]MediaType{contentType: {Schema: SchemaOf(schemas, body)}}}
+}
+
+func responses(schemas map[string]*Schema, resp interface{}, stream bool) map[string]Response {
+	ok := Response{Description: "Success"}
+	if resp != nil {
+		contentType := "application/json"
+		if stream {
+			contentType = EventStream
+		}
+		ok.Content = map[string]MediaType{contentType: {Schema: SchemaOf(schemas, resp)}}
+	}
+	return map[string]Response{
+		"200":     ok,
+		"default": {Description: "Error (the message as text/plain)"},
+	}
+}
+
+// merge the Operation in the existing (same path and method): the Parameters of the Operation are optional
+func merge(existing, op *Operation) {
+	if op.Summary != "" {
+		existing.Summary = strings.TrimPrefix(existing.Summary+" / "+op.Summary, " / ")
+	}
+	for _, p := range op.Parameters {
+		if !hasParameter(existing.Parameters, p) {
+			p.Required = false
+			existing.Parameters = append(existing.Parameters, p)
+		}
+	}
+	// the Parameters of the existing Operation are optional, if they are not in the new Operation
+	for i, p := range existing.Parameters {
+		if p.In == "query" && !hasParameter(op.Parameters, p) {
+			existing.Parameters[i].Required = false
+		}
+	}
+	if existing.RequestBody == nil {
+		existing.RequestBody = op.RequestBody
+	}
+}
+
+func hasParameter(params []Parameter, p Parameter) bool {
+	for _, e := range params {
+		if e.Name == p.Name && e.In == p.In {
+			return true
+		}
+	}
+	return false
+}
+
+// Operations returns all "METHOD path" of the Document (sorted)
+func (d *Document) Operations() []string {
+	ops := []string{}
+	for path, item := range d.Paths {
+		for m := range item {
+			ops = append(ops, fmt.Sprintf("%s %s", strings.ToUpper(m), path))
+		}
+	}
+	sort.Strings(ops)
+	return ops
+}
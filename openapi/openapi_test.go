@@ -0,0 +1,104 @@
+package openapi
+
+import (
+	"net/http"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/gorilla/mux"
+)
+
+type hero struct {
+	ID      int64     `json:"id"`
+	Name    string    `json:"name"`
+	Secret  string    `json:"-"`
+	Tags    []string  `json:"tags,omitempty"`
+	Friend  *hero     `json:"friend"`
+	Created time.Time `json:"created"`
+}
+
+type deleted struct {
+	hero
+	Pos int64 `json:"pos"`
+}
+
+func TestPath(t *testing.T) {
+	path, params := Path("/api{version:(?:/v[12])?}/heroes/{id:[0-9]+}/{name}", map[string]string{"version": "/v2"})
+	if path != "/api/v2/heroes/{id}/{name}" {
+		t.Errorf("unexpected path: %v", path)
+	}
+	if len(params) != 2 || params[0].Schema.Type != "integer" || params[1].Schema.Type != "string" || !params[1].Required {
+		t.Errorf("unexpected params: %v", params)
+	}
+}
+
+func TestSchemaOf(t *testing.T) {
+	schemas := map[string]*Schema{}
+
+	s := SchemaOf(schemas, []deleted{})
+	if s.Type != "array" || s.Items.Ref != "#/components/schemas/Deleted" {
+		t.Errorf("unexpected schema: %v", s)
+	}
+
+	d := schemas["Deleted"]
+	for _, p := range []string{"id", "name", "tags", "friend", "created", "pos"} {
+		if _, ok := d.Properties[p]; !ok {
+			t.Errorf("expect property: %v in: %v", p, d.Properties)
+		}
+	}
+	if _, ok := d.Properties["Secret"]; ok || len(d.Properties) != 6 {
+		t.Errorf("unexpected properties: %v", d.Properties)
+	}
+	if !reflect.DeepEqual(d.Required, []string{"pos", "id", "name", "created"}) {
+		t.Errorf("unexpected required: %v", d.Required)
+	}
+	if d.Properties["created"].Format != "date-time" || d.Properties["friend"].Ref != "#/components/schemas/Hero" {
+		t.Errorf("unexpected created or friend: %v %v", d.Properties["created"], d.Properties["friend"])
+	}
+
+	m := SchemaOf(schemas, map[int64]int{})
+	if m.Type != "object" || m.AdditionalProperties.Type != "integer" {
+		t.Errorf("unexpected map schema: %v", m)
+	}
+}
+
+func TestBuild(t *testing.T) {
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	router := mux.NewRouter()
+	router.HandleFunc("/api/heroes", ok).Methods("PUT").Queries("pos", "{pos:[0-9]+}").Name("move")
+	router.HandleFunc("/api/heroes", ok).Methods("PUT").Name("update")
+	router.HandleFunc("/api/heroes", ok).Methods("POST").Name("add")
+	router.HandleFunc("/api/heroes", ok).Methods("DELETE").Name("invalid")
+	router.HandleFunc("/api/unknown", ok).Name("unknown")
+	router.HandleFunc("/info", ok).Name("info")
+
+	b := Builder{Info: Info{Title: "Heroes", Version: "v1"}, Prefix: "/api"}
+	doc, undocumented, err := b.Build(router, map[string]Doc{
+		"move":    {Summary: "Move", Body: hero{}, Response: hero{}},
+		"update":  {Summary: "Update", Body: hero{}, Response: hero{}},
+		"add":     {Summary: "Add", Body: "", Response: hero{}},
+		"invalid": {Hidden: true},
+	})
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+
+	if !reflect.DeepEqual(undocumented, []string{"unknown"}) {
+		t.Errorf("expect undocumented route: unknown, got: %v", undocumented)
+	}
+	if !reflect.DeepEqual(doc.Operations(), []string{"GET /api/unknown", "POST /api/heroes", "PUT /api/heroes"}) {
+		t.Errorf("unexpected operations: %v", doc.Operations())
+	}
+
+	put := doc.Paths["/api/heroes"]["put"]
+	if put.Summary != "Move / Update" || len(put.Parameters) != 1 || put.Parameters[0].Required {
+		t.Errorf("expect merged operation with optional pos: %v %v", put.Summary, put.Parameters)
+	}
+	if _, ok := doc.Paths["/api/heroes"]["post"].RequestBody.Content["text/plain"]; !ok {
+		t.Errorf("expect text/plain request body for add")
+	}
+	if _, ok := doc.Components.Schemas["Hero"]; !ok {
+		t.Errorf("expect Hero schema: %v", doc.Components.Schemas)
+	}
+}
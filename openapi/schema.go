@@ -0,0 +1,129 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+)
+
+// Schema of a type (a subset of the JSON Schema)
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Nullable             bool               `json:"nullable,omitempty"`
+}
+
+// Namer is implemented by types, which have another Schema name as the type name
+type Namer interface {
+	SchemaName() string
+}
+
+var timeType = reflect.TypeOf(time.Time{})
+
+// SchemaOf create the Schema of the type of v (from the json tags),
+// the structs are added to the schemas and referenced (#/components/schemas/Name)
+func SchemaOf(schemas map[string]*Schema, v interface{}) *Schema {
+	return schemaOf(schemas, reflect.TypeOf(v))
+}
+
+func schemaOf(schemas map[string]*Schema, t reflect.Type) *Schema {
+	if t.Kind() == reflect.Ptr {
+		s := schemaOf(schemas, t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		s.Nullable = true
+		return s
+	}
+
+	switch {
+	case t == timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case t.Kind() == reflect.String:
+		return &Schema{Type: "string"}
+	case t.Kind() == reflect.Bool:
+		return &Schema{Type: "boolean"}
+	case t.Kind() >= reflect.Int && t.Kind() <= reflect.Uint64:
+		if t.Kind() == reflect.Int32 || t.Kind() == reflect.Uint32 {
+			return &Schema{Type: "integer", Format: "int32"}
+		}
+		return &Schema{Type: "integer", Format: "int64"}
+	case t.Kind() == reflect.Float32 || t.Kind() == reflect.Float64:
+		return &Schema{Type: "number"}
+	case t.Kind() == reflect.Slice || t.Kind() == reflect.Array:
+		return &Schema{Type: "array", Items: schemaOf(schemas, t.Elem())}
+	case t.Kind() == reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: schemaOf(schemas, t.Elem())}
+	case t.Kind() == reflect.Struct:
+		name := schemaName(t)
+		if _, ok := schemas[name]; !ok {
+			// placeholder for recursive types
+			schemas[name] = &Schema{}
+			*schemas[name] = *structSchema(schemas, t)
+		}
+		return &Schema{Ref: "#/components/schemas/" + name}
+	}
+	return &Schema{}
+}
+
+func schemaName(t reflect.Type) string {
+	if n, ok := reflect.Zero(t).Interface().(Namer); ok {
+		return n.SchemaName()
+	}
+	return strings.Title(t.Name())
+}
+
+func structSchema(schemas map[string]*Schema, t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	addFields(schemas, s, t)
+	return s
+}
+
+// addFields add the fields of the struct, the fields of the embedded structs are added,
+// if there is no field with the same name (like encoding/json)
+func addFields(schemas map[string]*Schema, s *Schema, t reflect.Type) {
+	embedded := []reflect.Type{}
+
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		if f.Anonymous && tag == "" {
+			embedded = append(embedded, f.Type)
+			continue
+		}
+		if f.PkgPath != "" {
+			continue
+		}
+
+		name, opts := tag, ""
+		if i := strings.Index(tag, ","); i != -1 {
+			name, opts = tag[:i], tag[i+1:]
+		}
+		if name == "" {
+			name = f.Name
+		}
+		if _, ok := s.Properties[name]; ok {
+			continue
+		}
+
+		s.Properties[name] = schemaOf(schemas, f.Type)
+		if !strings.Contains(opts, "omitempty") && f.Type.Kind() != reflect.Ptr {
+			s.Required = append(s.Required, name)
+		}
+	}
+
+	for _, e := range embedded {
+		if e.Kind() == reflect.Ptr {
+			e = e.Elem()
+		}
+		addFields(schemas, s, e)
+	}
+}
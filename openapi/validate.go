@@ -0,0 +1,270 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/lima1909/goheroes-appengine/body"
+)
+
+// Validator validate the requests (and the responses) against the Operations of the Document
+type Validator struct {
+	// Document for the request (e.g. by version), nil: no validation
+	Document func(r *http.Request) *Document
+	// Vars are the route variables, which are not path parameters (see: Builder.Vars)
+	Vars []string
+	// Responses are validated too, invalid responses are replaced by 500 (Internal Server Error),
+	// the responses are buffered (only for tests)
+	Responses bool
+}
+
+// Operation returns the Operation of the current route of the request (nil: no Operation)
+func (v *Validator) Operation(r *http.Request) (*Document, *Operation) {
+	doc := v.Document(r)
+	route := mux.CurrentRoute(r)
+	if doc == nil || route == nil {
+		return nil, nil
+	}
+	tmpl, err := route.GetPathTemplate()
+	if err != nil {
+		return nil, nil
+	}
+
+	vars := map[string]string{}
+	for _, name := range v.Vars {
+		vars[name] = mux.Vars(r)[name]
+	}
+	path, _ := Path(tmpl, vars)
+	return doc, doc.Paths[path][strings.ToLower(r.Method)]
+}
+
+// Middleware answer with 400 (Bad Request), if the query or the body does not match the Operation
+func (v *Validator) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		doc, op := v.Operation(r)
+		if op == nil {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		if err := validateRequest(doc, op, r); err != nil {
+			http.Error(w, err.Error(), body.Status(err))
+			return
+		}
+
+		if !v.Responses || op.streams() {
+			// a stream can not be recorded
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		rec := httptest.NewRecorder()
+		h.ServeHTTP(rec, r)
+		if err := validateResponse(doc, op, rec); err != nil {
+			log.Printf("invalid response of: %s %s: %v\n", r.Method, r.URL.Path, err)
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		for k, vs := range rec.Header() {
+			w.Header()[k] = vs
+		}
+		w.WriteHeader(rec.Code)
+		w.Write(rec.Body.Bytes())
+	})
+}
+
+// streams is true, if the Operation responds with Server-Sent Events
+func (op *Operation) streams() bool {
+	_, ok := op.Responses["200"].Content[EventStream]
+	return ok
+}
+
+func validateRequest(doc *Document, op *Operation, r *http.Request) error {
+	q := r.URL.Query()
+	for _, p := range op.Parameters {
+		if p.In != "query" {
+			continue
+		}
+		value := q.Get(p.Name)
+		if value == "" {
+			if p.Required {
+				return fmt.Errorf("request does not match the OpenAPI document: query parameter: %s is required", p.Name)
+			}
+			continue
+		}
+		if err := validateParameter(p, value); err != nil {
+			return fmt.Errorf("request does not match the OpenAPI document: %v", err)
+		}
+	}
+
+	if op.RequestBody == nil {
+		return nil
+	}
+	b, err := body.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(b))
+	if len(b) == 0 && !op.RequestBody.Required {
+		return nil
+	}
+
+	ct := r.Header.Get("Content-Type")
+	if err = validateContent(doc, op.RequestBody.Content, ct, b); err != nil {
+		return fmt.Errorf("request body does not match the OpenAPI document: %v", err)
+	}
+	return nil
+}
+
+func validateResponse(doc *Document, op *Operation, rec *httptest.ResponseRecorder) error {
+	resp, ok := op.Responses[strconv.Itoa(rec.Code)]
+	if !ok || len(resp.Content) == 0 {
+		return nil
+	}
+	ct := rec.Header().Get("Content-Type")
+	mt, _, _ := mime.ParseMediaType(ct)
+	if _, ok := resp.Content[mt]; !ok {
+		// other media types (e.g. XML or HAL) are not described in the Document
+		return nil
+	}
+	if err := validateContent(doc, resp.Content, ct, rec.Body.Bytes()); err != nil {
+		return fmt.Errorf("response body does not match the OpenAPI document: %v", err)
+	}
+	return nil
+}
+
+// validateContent of the media type (without Content-Type: the first media type)
+func validateContent(doc *Document, content map[string]MediaType, ct string, b []byte) error {
+	mt := ""
+	if ct != "" {
+		mt, _, _ = mime.ParseMediaType(ct)
+	} else {
+		types := []string{}
+		for t := range content {
+			types = append(types, t)
+		}
+		sort.Strings(types)
+		mt = types[0]
+	}
+
+	m, ok := content[mt]
+	if !ok {
+		return fmt.Errorf("unsupported Content-Type: %s", ct)
+	}
+	if mt != "application/json" {
+		return nil
+	}
+
+	var value interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err := dec.Decode(&value); err != nil {
+		return fmt.Errorf("invalid JSON: %v", err)
+	}
+	return doc.Validate(m.Schema, value)
+}
+
+func validateParameter(p Parameter, value string) error {
+	if p.Schema != nil && p.Schema.Type == "integer" {
+		if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+			return fmt.Errorf("query parameter: %s must be an integer: %s", p.Name, value)
+		}
+	}
+	return nil
+}
+
+// Validate the value (decoded JSON with json.Number) against the Schema
+func (d *Document) Validate(s *Schema, value interface{}) error {
+	return d.validate(s, value, "$")
+}
+
+func (d *Document) validate(s *Schema, value interface{}, path string) error {
+	if s.Ref != "" {
+		// a struct value is never null, so null is a nil pointer (a $ref can not be nullable in 3.0)
+		if value == nil {
+			return nil
+		}
+		ref, ok := d.Components.Schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return fmt.Errorf("%s: unknown schema: %s", path, s.Ref)
+		}
+		s = ref
+	}
+	if value == nil {
+		if s.Nullable || s.Type == "" {
+			return nil
+		}
+		return fmt.Errorf("%s: null is not allowed", path)
+	}
+
+	switch s.Type {
+	case "object":
+		obj, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s: object expected, got: %T", path, value)
+		}
+		for _, r := range s.Required {
+			if _, ok := obj[r]; !ok {
+				return fmt.Errorf("%s: property: %s is required", path, r)
+			}
+		}
+		for k, v := range obj {
+			ps, ok := s.Properties[k]
+			if !ok {
+				ps = s.AdditionalProperties
+			}
+			if ps == nil {
+				return fmt.Errorf("%s: unknown property: %s", path, k)
+			}
+			if err := d.validate(ps, v, path+"."+k); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := value.([]interface{})
+		if !ok {
+			return fmt.Errorf("%s: array expected, got: %T", path, value)
+		}
+		for i, v := range arr {
+			if err := d.validate(s.Items, v, fmt.Sprintf("%s[%d]", path, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("%s: string expected, got: %T", path, value)
+		}
+		if s.Format == "date-time" {
+			if _, err := time.Parse(time.RFC3339, str); err != nil {
+				return fmt.Errorf("%s: date-time expected: %s", path, str)
+			}
+		}
+	case "integer":
+		n, ok := value.(json.Number)
+		if _, err := n.Int64(); !ok || err != nil {
+			return fmt.Errorf("%s: integer expected, got: %v", path, value)
+		}
+	case "number":
+		if _, ok := value.(json.Number); !ok {
+			return fmt.Errorf("%s: number expected, got: %T", path, value)
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("%s: boolean expected, got: %T", path, value)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package openapi
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+)
+
+func TestValidate(t *testing.T) {
+	doc := &Document{Components: Components{Schemas: map[string]*Schema{}}}
+	s := SchemaOf(doc.Components.Schemas, []hero{})
+
+	for _, tc := range []struct {
+		json string
+		err  bool
+	}{
+		{`[{"id": 1, "name": "Jasmin", "friend": null, "created": "2018-06-01T12:00:00Z"}]`, false},
+		{`[{"id": 1, "name": "Jasmin", "friend": {"id": 2, "name": "Alex", "friend": null, "created": "2018-06-01T12:00:00Z"}, "created": "2018-06-01T12:00:00Z", "tags": ["a"]}]`, false},
+		{`[{"id": "1", "name": "Jasmin", "friend": null, "created": "2018-06-01T12:00:00Z"}]`, true},
+		{`[{"id": 1.5, "name": "Jasmin", "friend": null, "created": "2018-06-01T12:00:00Z"}]`, true},
+		{`[{"name": "Jasmin", "friend": null, "created": "2018-06-01T12:00:00Z"}]`, true},
+		{`[{"id": 1, "name": "Jasmin", "friend": null, "created": "yesterday"}]`, true},
+		{`[{"id": 1, "name": "Jasmin", "friend": null, "created": "2018-06-01T12:00:00Z", "power": 9}]`, true},
+		{`[{"id": 1, "name": "Jasmin", "friend": null, "created": "2018-06-01T12:00:00Z", "tags": [1]}]`, true},
+		{`{"id": 1}`, true},
+	} {
+		var value interface{}
+		dec := json.NewDecoder(strings.NewReader(tc.json))
+		dec.UseNumber()
+		dec.Decode(&value)
+
+		if err := doc.Validate(s, value); (err != nil) != tc.err {
+			t.Errorf("expect err: %v for: %v, got: %v", tc.err, tc.json, err)
+		}
+	}
+}
+
+func TestValidatorMiddleware(t *testing.T) {
+	response := `{"id": 1, "name": "Jasmin", "friend": null, "created": "2018-06-01T12:00:00Z"}`
+
+	router := mux.NewRouter()
+	router.HandleFunc("/api/heroes", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(response))
+	}).Methods("PUT").Name("update")
+
+	b := Builder{Prefix: "/api"}
+	doc, _, _ := b.Build(router, map[string]Doc{
+		"update": {Body: hero{}, Response: hero{}, Query: []Parameter{{Name: "pos", In: "query", Schema: &Schema{Type: "integer"}}}},
+	})
+	v := &Validator{Document: func(r *http.Request) *Document { return doc }, Responses: true}
+	router.Use(v.Middleware)
+
+	for _, tc := range []struct {
+		url, body, response string
+		status              int
+	}{
+		{"/api/heroes", response, response, http.StatusOK},
+		{"/api/heroes?pos=2", response, response, http.StatusOK},
+		{"/api/heroes?pos=x", response, response, http.StatusBadRequest},
+		{"/api/heroes", `{"id": 1}`, response, http.StatusBadRequest},
+		{"/api/heroes", response, `{"id": 1}`, http.StatusInternalServerError},
+	} {
+		response = tc.response
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("PUT", "http://localhost:8080"+tc.url, strings.NewReader(tc.body)))
+
+		if w.Code != tc.status {
+			t.Errorf("%v != %v for: %v %v (%v)", tc.status, w.Code, tc.url, tc.body, w.Body.String())
+		}
+		if tc.status == http.StatusOK && w.Body.String() != tc.response {
+			t.Errorf("expect the response of the handler: %v", w.Body.String())
+		}
+	}
+}
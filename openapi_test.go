@@ -0,0 +1,125 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/lima1909/goheroes-appengine/openapi"
+)
+
+// every registered API route (without the hidden routes) must be in the OpenAPI document
+func TestOpenAPICoversAllRoutes(t *testing.T) {
+	for _, version := range []string{V1, V2} {
+		doc, undocumented, err := openAPIDocument(version)
+		if err != nil {
+			t.Fatalf("no err expected: %v", err)
+		}
+		if len(undocumented) > 0 {
+			t.Errorf("%v: routes without openapi.Doc (see: routeDocs): %v", version, undocumented)
+		}
+
+		ops := map[string]bool{}
+		for _, op := range doc.Operations() {
+			ops[op] = true
+		}
+
+		docs := routeDocs(version)
+		vars := map[string]string{"version": "/" + version}
+		if version == V1 {
+			vars["version"] = ""
+		}
+		routes.Walk(func(route *mux.Route, router *mux.Router, ancestors []*mux.Route) error {
+			tmpl, _ := route.GetPathTemplate()
+			if !strings.HasPrefix(tmpl, apiPrefix) || docs[route.GetName()].Hidden {
+				return nil
+			}
+			path, _ := openapi.Path(tmpl, vars)
+
+			methods, err := route.GetMethods()
+			if err != nil {
+				methods = docs[route.GetName()].Methods
+			}
+			if len(methods) == 0 {
+				methods = []string{"GET"}
+			}
+			for _, m := range methods {
+				if !ops[m+" "+path] {
+					t.Errorf("%v: route: %v (%v %v) is missing in the OpenAPI document", version, route.GetName(), m, path)
+				}
+			}
+			return nil
+		})
+	}
+}
+
+func TestServeOpenAPI(t *testing.T) {
+	for _, tc := range []struct {
+		url, path, heroProperty string
+	}{
+		{"/api/openapi.json", "/api/heroes/{id}", ""},
+		{"/api/v2/openapi.json", "/api/v2/heroes/{id}", "scoreData"},
+	} {
+		resp, err := http.Get(server.URL + tc.url)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+			continue
+		}
+		doc := openapi.Document{}
+		if err = json.NewDecoder(resp.Body).Decode(&doc); err != nil {
+			t.Errorf("No err expected: %v", err)
+			continue
+		}
+
+		if doc.OpenAPI != openapi.Version {
+			t.Errorf("expect version: %v, got: %v", openapi.Version, doc.OpenAPI)
+		}
+		get := doc.Paths[tc.path]["get"]
+		if get == nil || get.Parameters[0].Name != "id" || get.Parameters[0].Schema.Type != "integer" {
+			t.Errorf("expect GET %v with integer id: %v", tc.path, get)
+		}
+		hero := doc.Components.Schemas["Hero"]
+		if hero == nil || hero.Properties["name"] == nil {
+			t.Errorf("expect Hero schema with name: %v", hero)
+		}
+		if _, ok := hero.Properties["scoreData"]; ok != (tc.heroProperty != "") {
+			t.Errorf("%v: scoreData in Hero: %v", tc.url, ok)
+		}
+	}
+}
+
+// a response, which does not match the OpenAPI document, is a 500 in the test server
+func TestOpenAPIResponseDrift(t *testing.T) {
+	router := mux.NewRouter()
+	router.HandleFunc("/api/heroes/{id:[0-9]+}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"id": 1, "title": "Jasmin"}`))
+	}).Methods("GET").Name("hero.get")
+
+	doc, _, _ := openAPIDocument(V1)
+	v := &openapi.Validator{Document: func(r *http.Request) *openapi.Document { return doc }, Responses: true}
+	router.Use(v.Middleware)
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest("GET", "http://localhost:8080/api/heroes/1", nil))
+	if w.Code != http.StatusInternalServerError || !strings.Contains(w.Body.String(), "does not match") {
+		t.Errorf("expect 500 for a drifted response, got: %v %v", w.Code, w.Body.String())
+	}
+}
+
+func TestOpenAPIRequestValidation(t *testing.T) {
+	req, _ := http.NewRequest("GET", server.URL+"/api/heroes?page=x", nil)
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+		return
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusBadRequest || !strings.Contains(string(body), "OpenAPI") {
+		t.Errorf("expect 400 from the OpenAPI validation, got: %v %v", resp.StatusCode, string(body))
+	}
+}
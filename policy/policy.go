@@ -0,0 +1,166 @@
+// Package policy is a role-based authorization: every (named) route requires a Permission
+// and every role has a set of Permissions. The roles of the request are from the auth.Principal.
+package policy
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/gorilla/mux"
+	"github.com/lima1909/goheroes-appengine/auth"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// EnvPolicy is the Env-Variable with the path to a YAML file with the roles (role: [permissions])
+const EnvPolicy = "HEROES_POLICY"
+
+// Permission to execute an operation
+type Permission string
+
+// all Permissions
+const (
+	ReadHeroes   Permission = "heroes:read"
+	AddHero      Permission = "heroes:add"
+	UpdateHero   Permission = "heroes:update"
+	MoveHero     Permission = "heroes:move"
+	DeleteHero   Permission = "heroes:delete"
+	WriteTeams   Permission = "teams:write"
+	DeleteTeam   Permission = "teams:delete"
+	ReadProtocol Permission = "protocol:read"
+	RunWorker    Permission = "worker:run"
+	Reset        Permission = "admin:reset"
+	// ManageWebhooks is the Permission for the webhooks and the deliveries
+	ManageWebhooks Permission = "webhooks:manage"
+)
+
+// the roles
+const (
+	Viewer = "viewer"
+	Editor = "editor"
+	Admin  = "admin"
+	// Worker is the role of the accepted cron requests (see: cron.Role)
+	Worker = "worker"
+)
+
+// Policy contains the Permissions of the roles and the required Permission of the routes
+type Policy struct {
+	roles map[string]map[Permission]bool
+	// routes: mux route name to Permission
+	routes map[string]Permission
+}
+
+// New create a new Policy, roles is a map from role to the Permissions of the role
+func New(roles map[string][]Permission) *Policy {
+	p := &Policy{roles: map[string]map[Permission]bool{}, routes: map[string]Permission{}}
+	for role, perms := range roles {
+		p.roles[role] = map[Permission]bool{}
+		for _, perm := range perms {
+			p.roles[role][perm] = true
+		}
+	}
+	return p
+}
+
+// DefaultRoles are: viewer (read), editor (viewer + add, update, move), admin (all) and worker (run the worker)
+func DefaultRoles() map[string][]Permission {
+	viewer := []Permission{ReadHeroes}
+	editor := append([]Permission{AddHero, UpdateHero, MoveHero, WriteTeams}, viewer...)
+	admin := append([]Permission{DeleteHero, DeleteTeam, ReadProtocol, RunWorker, Reset, ManageWebhooks}, editor...)
+
+	return map[string][]Permission{Viewer: viewer, Editor: editor, Admin: admin, Worker: {RunWorker}}
+}
+
+// FromEnv create a Policy with the roles from the EnvPolicy file or the DefaultRoles,
+// the role Worker needs the Permission RunWorker (else the cron requests are denied)
+func FromEnv() (*Policy, error) {
+	file := os.Getenv(EnvPolicy)
+	if file == "" {
+		return New(DefaultRoles()), nil
+	}
+
+	b, err := ioutil.ReadFile(file)
+	if err != nil {
+		return nil, fmt.Errorf("can not read policy file: %v", err)
+	}
+	roles := map[string][]Permission{}
+	if err = yaml.Unmarshal(b, &roles); err != nil {
+		return nil, fmt.Errorf("can not parse policy file: %s: %v", file, err)
+	}
+	p := New(roles)
+	if !p.Allow([]string{Worker}, RunWorker) {
+		return nil, fmt.Errorf("invalid policy file: %s: the role: %s needs the permission: %s", file, Worker, RunWorker)
+	}
+	return p, nil
+}
+
+// Require the Permission for the route with the name
+func (p *Policy) Require(route string, perm Permission) *Policy {
+	p.routes[route] = perm
+	return p
+}
+
+// Permission returns the required Permission for the route with the name
+func (p *Policy) Permission(route string) (Permission, bool) {
+	perm, ok := p.routes[route]
+	return perm, ok
+}
+
+// Allow is true, if one of the roles has the Permission
+func (p *Policy) Allow(roles []string, perm Permission) bool {
+	for _, r := range roles {
+		if p.roles[r][perm] {
+			return true
+		}
+	}
+	return false
+}
+
+// Roles returns all roles with the Permission
+func (p *Policy) Roles(perm Permission) []string {
+	roles := []string{}
+	for r, perms := range p.roles {
+		if perms[perm] {
+			roles = append(roles, r)
+		}
+	}
+	sort.Strings(roles)
+	return roles
+}
+
+// Middleware check the Permission of the current route for the Principal of the request,
+// requests without Principal have the role viewer, routes without Permission are denied.
+// The response is 401 (Unauthorized) without Principal, else 403 (Forbidden).
+func (p *Policy) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := ""
+		if route := mux.CurrentRoute(r); route != nil {
+			name = route.GetName()
+		}
+
+		roles := []string{Viewer}
+		principal := auth.PrincipalFromContext(r.Context())
+		if principal != nil {
+			roles = append(roles, principal.Roles...)
+		}
+
+		perm, ok := p.Permission(name)
+		if ok && p.Allow(roles, perm) {
+			h.ServeHTTP(w, r)
+			return
+		}
+
+		msg := fmt.Sprintf("permission denied for: %s %s", r.Method, r.URL.Path)
+		if ok {
+			msg = fmt.Sprintf("permission: %s required (roles: %s)", perm, strings.Join(p.Roles(perm), ", "))
+		}
+		if principal == nil {
+			http.Error(w, msg, http.StatusUnauthorized)
+			return
+		}
+		http.Error(w, msg, http.StatusForbidden)
+	})
+}
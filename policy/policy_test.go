@@ -0,0 +1,100 @@
+package policy
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/gorilla/mux"
+	"github.com/lima1909/goheroes-appengine/auth"
+)
+
+func TestAllow(t *testing.T) {
+	p := New(DefaultRoles())
+
+	for _, tc := range []struct {
+		role  string
+		perm  Permission
+		allow bool
+	}{
+		{Viewer, ReadHeroes, true},
+		{Viewer, AddHero, false},
+		{Editor, MoveHero, true},
+		{Editor, DeleteHero, false},
+		{Admin, DeleteHero, true},
+		{Admin, RunWorker, true},
+		{"unknown", ReadHeroes, false},
+	} {
+		if p.Allow([]string{tc.role}, tc.perm) != tc.allow {
+			t.Errorf("expect %v for role: %v and permission: %v", tc.allow, tc.role, tc.perm)
+		}
+	}
+}
+
+func TestRoles(t *testing.T) {
+	roles := New(DefaultRoles()).Roles(AddHero)
+	if len(roles) != 2 || roles[0] != Admin || roles[1] != Editor {
+		t.Errorf("expect admin and editor, got: %v", roles)
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	defer os.Unsetenv(EnvPolicy)
+
+	os.Setenv(EnvPolicy, "testdata/policy.yaml")
+	p, err := FromEnv()
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if !p.Allow([]string{"maintainer"}, DeleteHero) || p.Allow([]string{"maintainer"}, AddHero) {
+		t.Errorf("expect maintainer can delete but not add")
+	}
+
+	os.Setenv(EnvPolicy, "testdata/not-exist.yaml")
+	_, err = FromEnv()
+	if err == nil {
+		t.Errorf("err expected")
+	}
+
+	os.Setenv(EnvPolicy, "testdata/no-worker.yaml")
+	_, err = FromEnv()
+	if err == nil || !strings.Contains(err.Error(), "worker:run") {
+		t.Errorf("expect err for the role worker without worker:run, got: %v", err)
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	p := New(DefaultRoles()).Require("list", ReadHeroes).Require("delete", DeleteHero)
+
+	router := mux.NewRouter()
+	ok := func(w http.ResponseWriter, r *http.Request) {}
+	router.HandleFunc("/heroes", ok).Methods("GET").Name("list")
+	router.HandleFunc("/heroes/{id}", ok).Methods("DELETE").Name("delete")
+	router.HandleFunc("/unknown", ok)
+	router.Use(p.Middleware)
+
+	for _, tc := range []struct {
+		method, url string
+		principal   *auth.Principal
+		status      int
+	}{
+		{"GET", "/heroes", nil, http.StatusOK},
+		{"DELETE", "/heroes/1", nil, http.StatusUnauthorized},
+		{"DELETE", "/heroes/1", &auth.Principal{Name: "mario", Roles: []string{Editor}}, http.StatusForbidden},
+		{"DELETE", "/heroes/1", &auth.Principal{Name: "mario", Roles: []string{Admin}}, http.StatusOK},
+		{"GET", "/unknown", &auth.Principal{Name: "mario", Roles: []string{Admin}}, http.StatusForbidden},
+	} {
+		r := httptest.NewRequest(tc.method, "http://localhost:8080"+tc.url, nil)
+		if tc.principal != nil {
+			r = r.WithContext(auth.WithPrincipal(r.Context(), tc.principal))
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, r)
+
+		if w.Code != tc.status {
+			t.Errorf("%v != %v for: %v %v (%v)", tc.status, w.Code, tc.method, tc.url, w.Body.String())
+		}
+	}
+}
@@ -0,0 +1,72 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+	// per is the duration of the Limit, after this duration the bucket is full again
+	per time.Duration
+}
+
+// sweepInterval is the interval to remove the unused buckets
+const sweepInterval = time.Minute
+
+// MemStore is a in memory Store (per instance)
+type MemStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucket
+	lastSweep time.Time
+	// now is replaceable for tests
+	now func() time.Time
+}
+
+// NewMemStore create a new instance of MemStore without buckets
+func NewMemStore() *MemStore {
+	return &MemStore{buckets: map[string]*bucket{}, now: time.Now}
+}
+
+// Take impl from Store
+func (s *MemStore) Take(c context.Context, key string, l Limit) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now)
+
+	b, ok := s.buckets[key]
+	if !ok {
+		b = &bucket{tokens: float64(l.Requests), last: now, per: l.Per}
+		s.buckets[key] = b
+	}
+
+	b.tokens += now.Sub(b.last).Seconds() * l.rate()
+	if max := float64(l.Requests); b.tokens > max {
+		b.tokens = max
+	}
+	b.last = now
+
+	if b.tokens < 1 {
+		wait := time.Duration((1 - b.tokens) / l.rate() * float64(time.Second))
+		return false, wait, nil
+	}
+	b.tokens--
+	return true, 0, nil
+}
+
+// sweep remove the buckets, which are unused longer as the duration of the Limit (they are full again)
+func (s *MemStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	for k, b := range s.buckets {
+		if now.Sub(b.last) >= b.per {
+			delete(s.buckets, k)
+		}
+	}
+	s.lastSweep = now
+}
@@ -0,0 +1,187 @@
+// Package ratelimit limit the requests per client (Principal, tenant of a verified API key or IP) with token buckets,
+// every route class (e.g. read, write) has his own Limit.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/auth"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// EnvLimits is the Env-Variable with the Limits of the classes: read=120/1m,write=20/1m,scores=5/1m
+// (empty: no rate limiting)
+const EnvLimits = "HEROES_RATE_LIMITS"
+
+// the default classes of the requests
+const (
+	Read  = "read"
+	Write = "write"
+)
+
+// Limit of a token bucket: Requests (the capacity) per duration, the tokens are refilled continuously
+type Limit struct {
+	Requests int
+	Per      time.Duration
+}
+
+// rate is the number of tokens per second
+func (l Limit) rate() float64 {
+	return float64(l.Requests) / l.Per.Seconds()
+}
+
+func (l Limit) String() string {
+	return fmt.Sprintf("%d/%v", l.Requests, l.Per)
+}
+
+// Store contains the token buckets, a shared Store (e.g. memcache) limits the clients over all instances
+type Store interface {
+	// Take a token from the bucket of the key, if the bucket is empty, the result is false
+	// and the duration, until the next token is available
+	Take(c context.Context, key string, l Limit) (bool, time.Duration, error)
+}
+
+// Limiter limit the requests with the Limits of the classes
+type Limiter struct {
+	Store  Store
+	Limits map[string]Limit
+	// Class of the request (default: MethodClass), requests of a class without Limit are not limited
+	Class func(r *http.Request) string
+	// Key of the client (default: Client), only verified identities can be used, else a client get a new
+	// bucket with every request
+	Key func(r *http.Request) string
+}
+
+// ParseLimits parse the format: class1=requests/duration,class2=requests/duration, e.g. read=60/1m
+func ParseLimits(s string) (map[string]Limit, error) {
+	limits := map[string]Limit{}
+	for _, l := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(l), "=", 2)
+		if len(kv) != 2 {
+			return nil, fmt.Errorf("invalid limit: %s (expected: class=requests/duration)", l)
+		}
+		rd := strings.SplitN(kv[1], "/", 2)
+		if len(rd) != 2 {
+			return nil, fmt.Errorf("invalid limit: %s (expected: class=requests/duration)", l)
+		}
+		n, err := strconv.Atoi(rd[0])
+		if err != nil || n <= 0 {
+			return nil, fmt.Errorf("invalid requests of limit: %s", l)
+		}
+		d, err := time.ParseDuration(rd[1])
+		if err != nil || d <= 0 {
+			return nil, fmt.Errorf("invalid duration of limit: %s", l)
+		}
+		limits[kv[0]] = Limit{Requests: n, Per: d}
+	}
+	return limits, nil
+}
+
+// FromEnv create a Limiter with a MemStore and the Limits from the EnvLimits (nil: no rate limiting)
+func FromEnv() (*Limiter, error) {
+	s := os.Getenv(EnvLimits)
+	if s == "" {
+		return nil, nil
+	}
+	limits, err := ParseLimits(s)
+	if err != nil {
+		return nil, err
+	}
+	return &Limiter{Store: NewMemStore(), Limits: limits}, nil
+}
+
+// MethodClass is Read for the read methods, else Write
+func MethodClass(r *http.Request) string {
+	if auth.IsRead(r) {
+		return Read
+	}
+	return Write
+}
+
+// HeaderUserIP is set by App Engine with the IP of the client (the RemoteAddr is the front end)
+const HeaderUserIP = "X-Appengine-User-Ip"
+
+// Client is the key of the client: the (authenticated) Principal or the IP
+// (in the cloud the IP of the HeaderUserIP)
+func Client(r *http.Request) string {
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		return "user:" + p.Name
+	}
+	if ip := r.Header.Get(HeaderUserIP); ip != "" && service.RunInCloud() {
+		return "ip:" + ip
+	}
+	ip, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		ip = r.RemoteAddr
+	}
+	return "ip:" + ip
+}
+
+// TenantClient is the key of the client: the Principal, the tenant or the IP,
+// only for tenants of verified API keys (not for the tenants of a header or subdomain)
+func TenantClient(r *http.Request) string {
+	if p := auth.PrincipalFromContext(r.Context()); p != nil {
+		return "user:" + p.Name
+	}
+	if t := service.TenantFromContext(r.Context()); t != "" {
+		return "tenant:" + t
+	}
+	return Client(r)
+}
+
+// Middleware limit the requests, if the Limit of the class is exceeded,
+// the response is 429 (Too Many Requests) with the Retry-After header (in seconds).
+// If the Store failed, the request is allowed.
+func (l *Limiter) Middleware(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if retry, err := l.Allow(r); err != nil {
+			w.Header().Set("Retry-After", strconv.Itoa(int(math.Ceil(retry.Seconds()))))
+			http.Error(w, err.Error(), http.StatusTooManyRequests)
+			return
+		}
+
+		h.ServeHTTP(w, r)
+	})
+}
+
+// Allow take a token for the request (e.g. for the gRPC calls), if the Limit of the class is exceeded,
+// the result is an error with the duration, until the next token is available.
+// If the Store failed, the request is allowed.
+func (l *Limiter) Allow(r *http.Request) (time.Duration, error) {
+	classOf := l.Class
+	if classOf == nil {
+		classOf = MethodClass
+	}
+	return l.AllowClass(r, classOf(r))
+}
+
+// AllowClass take a token of the class for the request, e.g. for an expensive part of the request
+func (l *Limiter) AllowClass(r *http.Request, class string) (time.Duration, error) {
+	limit, ok := l.Limits[class]
+	if !ok {
+		return 0, nil
+	}
+
+	keyOf := l.Key
+	if keyOf == nil {
+		keyOf = Client
+	}
+	client := keyOf(r)
+	allow, retry, err := l.Store.Take(r.Context(), class+"|"+client, limit)
+	if err != nil {
+		log.Printf("rate limit store failed for: %s %s: %v\n", class, client, err)
+	} else if !allow {
+		log.Printf("rate limit: %v of class: %s exceeded by: %s\n", limit, class, client)
+		return retry, fmt.Errorf("rate limit exceeded (%s: %v), retry after: %v", class, limit, retry)
+	}
+	return 0, nil
+}
@@ -0,0 +1,158 @@
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/auth"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestParseLimits(t *testing.T) {
+	limits, err := ParseLimits("read=60/1m, write=10/1m,scores=5/1h")
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if limits[Read] != (Limit{60, time.Minute}) || limits[Write] != (Limit{10, time.Minute}) || limits["scores"] != (Limit{5, time.Hour}) {
+		t.Errorf("unexpected limits: %v", limits)
+	}
+
+	for _, s := range []string{"read", "read=60", "read=x/1m", "read=0/1m", "read=60/m", "read=60/-1s"} {
+		if _, err := ParseLimits(s); err == nil {
+			t.Errorf("err expected for: %v", s)
+		}
+	}
+}
+
+func TestFromEnv(t *testing.T) {
+	defer os.Unsetenv(EnvLimits)
+
+	l, err := FromEnv()
+	if l != nil || err != nil {
+		t.Errorf("expect no Limiter: %v %v", l, err)
+	}
+
+	os.Setenv(EnvLimits, "write=10/1m")
+	l, err = FromEnv()
+	if err != nil || l.Limits[Write].Requests != 10 {
+		t.Errorf("expect Limiter with write limit: %v %v", l, err)
+	}
+}
+
+func TestMemStore(t *testing.T) {
+	now := time.Date(2018, 6, 1, 12, 0, 0, 0, time.UTC)
+	s := NewMemStore()
+	s.now = func() time.Time { return now }
+	l := Limit{Requests: 2, Per: time.Minute}
+
+	for i := 0; i < 2; i++ {
+		if ok, _, _ := s.Take(context.Background(), "a", l); !ok {
+			t.Errorf("token %d expected", i)
+		}
+	}
+	ok, retry, _ := s.Take(context.Background(), "a", l)
+	if ok || retry != 30*time.Second {
+		t.Errorf("expect no token and retry after 30s: %v %v", ok, retry)
+	}
+	// other key has his own bucket
+	if ok, _, _ := s.Take(context.Background(), "b", l); !ok {
+		t.Errorf("token for other key expected")
+	}
+
+	now = now.Add(30 * time.Second)
+	if ok, _, _ := s.Take(context.Background(), "a", l); !ok {
+		t.Errorf("refilled token expected")
+	}
+
+	// unused buckets are removed
+	now = now.Add(2 * time.Minute)
+	s.Take(context.Background(), "c", l)
+	if len(s.buckets) != 1 {
+		t.Errorf("expect only the bucket c: %v", s.buckets)
+	}
+}
+
+func TestClient(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://localhost:8080/api/heroes", nil)
+	r.RemoteAddr = "10.0.0.1:1234"
+	if c := Client(r); c != "ip:10.0.0.1" {
+		t.Errorf("expect ip, got: %v", c)
+	}
+
+	// the header of App Engine is only trusted in the cloud
+	r.Header.Set(HeaderUserIP, "192.0.2.7")
+	if c := Client(r); c != "ip:10.0.0.1" {
+		t.Errorf("expect the RemoteAddr outside the cloud, got: %v", c)
+	}
+	os.Setenv("RUN_IN_CLOUD", "true")
+	c := Client(r)
+	os.Unsetenv("RUN_IN_CLOUD")
+	if c != "ip:192.0.2.7" {
+		t.Errorf("expect the ip of the App Engine header, got: %v", c)
+	}
+	r.Header.Del(HeaderUserIP)
+
+	// the API key is not verified: every key would get a new bucket
+	r.Header.Set("X-API-Key", "random")
+	if c := Client(r); c != "ip:10.0.0.1" {
+		t.Errorf("expect ip for an unverified API key, got: %v", c)
+	}
+	if c := TenantClient(r.WithContext(service.WithTenant(r.Context(), "club"))); c != "tenant:club" {
+		t.Errorf("expect tenant, got: %v", c)
+	}
+
+	r = r.WithContext(auth.WithPrincipal(r.Context(), &auth.Principal{Name: "mario"}))
+	if c := Client(r); c != "user:mario" {
+		t.Errorf("expect user, got: %v", c)
+	}
+	if c := TenantClient(r.WithContext(service.WithTenant(r.Context(), "club"))); c != "user:mario" {
+		t.Errorf("expect user before tenant, got: %v", c)
+	}
+}
+
+type errStore struct{}
+
+func (errStore) Take(c context.Context, key string, l Limit) (bool, time.Duration, error) {
+	return false, 0, errors.New("store is down")
+}
+
+func TestMiddleware(t *testing.T) {
+	l := &Limiter{Store: NewMemStore(), Limits: map[string]Limit{Write: {Requests: 1, Per: time.Hour}}}
+	h := l.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+
+	do := func(method, ip string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, "http://localhost:8080/api/heroes", nil)
+		r.RemoteAddr = ip + ":1234"
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	if w := do("POST", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("expect 200, got: %v", w.Code)
+	}
+	w := do("POST", "10.0.0.1")
+	if w.Code != http.StatusTooManyRequests || w.Header().Get("Retry-After") != "3600" {
+		t.Errorf("expect 429 with Retry-After: 3600, got: %v %v", w.Code, w.Header().Get("Retry-After"))
+	}
+	if w := do("POST", "10.0.0.2"); w.Code != http.StatusOK {
+		t.Errorf("expect 200 for other client, got: %v", w.Code)
+	}
+	// the class read has no limit
+	for i := 0; i < 3; i++ {
+		if w := do("GET", "10.0.0.1"); w.Code != http.StatusOK {
+			t.Errorf("expect 200 for read, got: %v", w.Code)
+		}
+	}
+
+	// a failed store allow the requests
+	l.Store = errStore{}
+	if w := do("POST", "10.0.0.1"); w.Code != http.StatusOK {
+		t.Errorf("expect 200 by failed store, got: %v", w.Code)
+	}
+}
@@ -0,0 +1,86 @@
+package render
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+)
+
+// marshalMsgPack write the generic value in the MessagePack format (https://msgpack.org)
+func marshalMsgPack(v interface{}) []byte {
+	buf := &bytes.Buffer{}
+	writeMsgPack(buf, v)
+	return buf.Bytes()
+}
+
+func writeMsgPack(buf *bytes.Buffer, v interface{}) {
+	switch t := v.(type) {
+	case nil:
+		buf.WriteByte(0xc0)
+	case bool:
+		if t {
+			buf.WriteByte(0xc3)
+		} else {
+			buf.WriteByte(0xc2)
+		}
+	case int64:
+		writeInt(buf, t)
+	case float64:
+		buf.WriteByte(0xcb)
+		writeUint(buf, math.Float64bits(t), 8)
+	case string:
+		writeHeader(buf, len(t), 0xa0, 31, 0xd9, 0xda, 0xdb)
+		buf.WriteString(t)
+	case []interface{}:
+		writeHeader(buf, len(t), 0x90, 15, 0, 0xdc, 0xdd)
+		for _, e := range t {
+			writeMsgPack(buf, e)
+		}
+	case map[string]interface{}:
+		writeHeader(buf, len(t), 0x80, 15, 0, 0xde, 0xdf)
+		for _, k := range sortedKeys(t) {
+			writeMsgPack(buf, k)
+			writeMsgPack(buf, t[k])
+		}
+	}
+}
+
+// writeHeader write the length of a string, array or map: fix format (fix + n, n <= fixMax),
+// 8 bit (if code8 != 0), 16 bit or 32 bit
+func writeHeader(buf *bytes.Buffer, n int, fix byte, fixMax int, code8, code16, code32 byte) {
+	switch {
+	case n <= fixMax:
+		buf.WriteByte(fix + byte(n))
+	case code8 != 0 && n <= math.MaxUint8:
+		buf.WriteByte(code8)
+		buf.WriteByte(byte(n))
+	case n <= math.MaxUint16:
+		buf.WriteByte(code16)
+		writeUint(buf, uint64(n), 2)
+	default:
+		buf.WriteByte(code32)
+		writeUint(buf, uint64(n), 4)
+	}
+}
+
+func writeInt(buf *bytes.Buffer, i int64) {
+	switch {
+	case i >= 0 && i <= 127:
+		buf.WriteByte(byte(i))
+	case i < 0 && i >= -32:
+		buf.WriteByte(byte(int8(i)))
+	case i >= 0:
+		buf.WriteByte(0xcf)
+		writeUint(buf, uint64(i), 8)
+	default:
+		buf.WriteByte(0xd3)
+		writeUint(buf, uint64(i), 8)
+	}
+}
+
+// writeUint write the size (1, 2, 4 or 8) bytes in big endian
+func writeUint(buf *bytes.Buffer, u uint64, size int) {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, u)
+	buf.Write(b[8-size:])
+}
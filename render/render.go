@@ -0,0 +1,232 @@
+// Package render write the responses in the media type of the Accept header:
+// JSON, XML, YAML, MessagePack or HAL (the handler create the HAL resource, see: package hal). All formats are created from the JSON representation
+// (the json tags), so every format has the same field names.
+package render
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strconv"
+	"strings"
+
+	yaml "gopkg.in/yaml.v2"
+)
+
+// the supported media types
+const (
+	JSON    = "application/json"
+	XML     = "application/xml"
+	YAML    = "application/x-yaml"
+	MsgPack = "application/msgpack"
+	HAL     = "application/hal+json"
+
+	// EventStream is written by the handler itself (Server-Sent Events), it is not a format of Write
+	EventStream = "text/event-stream"
+)
+
+// Supported media types, the first is the default
+var Supported = []string{JSON, XML, YAML, MsgPack, HAL}
+
+// aliases of the supported media types
+var aliases = map[string]string{
+	"text/json":             JSON,
+	"text/xml":              XML,
+	"application/yaml":      YAML,
+	"text/yaml":             YAML,
+	"text/x-yaml":           YAML,
+	"application/x-msgpack": MsgPack,
+}
+
+// Content-Type of the media types
+var contentTypes = map[string]string{
+	JSON:    "application/json; charset=utf-8",
+	XML:     "application/xml; charset=utf-8",
+	YAML:    "application/x-yaml; charset=utf-8",
+	MsgPack: "application/msgpack",
+	HAL:     "application/hal+json; charset=utf-8",
+}
+
+type accepted struct {
+	mediaType string
+	q         float64
+}
+
+// Negotiate returns the supported media type with the highest quality of the Accept header,
+// false, if no supported media type is accepted (empty Accept: JSON).
+// JSON is the default: it is returned, if it is accepted with a wildcard (e.g. */* of the browsers) or for the same quality,
+// another media type only, if it is explicit preferred over JSON
+func Negotiate(accept string) (string, bool) {
+	if strings.TrimSpace(accept) == "" {
+		return Supported[0], true
+	}
+
+	accepts := []accepted{}
+	for _, a := range strings.Split(accept, ",") {
+		mt, params, err := mime.ParseMediaType(strings.TrimSpace(a))
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if qs, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(qs, 64); err != nil {
+				continue
+			}
+		}
+		if alias, ok := aliases[mt]; ok {
+			mt = alias
+		}
+		accepts = append(accepts, accepted{mediaType: mt, q: q})
+	}
+
+	jsonQ, _, jsonExplicit := quality(accepts, JSON)
+	if jsonQ > 0 && !jsonExplicit {
+		return JSON, true
+	}
+
+	best, bestQ, bestPos := "", 0.0, 0
+	for _, s := range Supported {
+		q, pos, explicit := quality(accepts, s)
+		if jsonQ > 0 && !explicit {
+			continue
+		}
+		// the order of the Accept header is the order for the same quality (JSON is the first of Supported)
+		if q > bestQ || (q == bestQ && best != JSON && pos < bestPos) {
+			best, bestQ, bestPos = s, q, pos
+		}
+	}
+	return best, best != ""
+}
+
+// quality of the media type is the quality of the most specific matching Accept entry (and the position of the entry),
+// explicit is true, if the entry is the media type itself (no wildcard)
+func quality(accepts []accepted, mediaType string) (q float64, pos int, explicit bool) {
+	specific := -1
+	for i, a := range accepts {
+		s := -1
+		switch {
+		case a.mediaType == mediaType:
+			s = 2
+		case a.mediaType == "*/*":
+			s = 0
+		case match(a.mediaType, mediaType):
+			s = 1
+		}
+		if s > specific {
+			specific, q, pos = s, a.q, i
+		}
+	}
+	return q, pos, specific == 2
+}
+
+func match(pattern, mediaType string) bool {
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+	return strings.HasSuffix(pattern, "/*") && strings.HasPrefix(mediaType, pattern[:len(pattern)-1])
+}
+
+// MediaType returns the negotiated media type of the request (empty: not acceptable)
+func MediaType(r *http.Request) string {
+	mt, _ := Negotiate(r.Header.Get("Accept"))
+	return mt
+}
+
+// Handler answer with 406 (Not Acceptable), if no supported media type is accepted
+// (before the request is executed), requests for an EventStream are passed
+func Handler(h http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		accept := r.Header.Get("Accept")
+		if _, ok := Negotiate(accept); !ok && !strings.Contains(accept, EventStream) {
+			notAcceptable(w, r)
+			return
+		}
+		h.ServeHTTP(w, r)
+	})
+}
+
+func notAcceptable(w http.ResponseWriter, r *http.Request) {
+	http.Error(w, fmt.Sprintf("not acceptable: %s (supported: %s)", r.Header.Get("Accept"), strings.Join(Supported, ", ")),
+		http.StatusNotAcceptable)
+}
+
+// Write v in the media type of the Accept header
+func Write(w http.ResponseWriter, r *http.Request, v interface{}) {
+	w.Header().Add("Vary", "Accept")
+
+	mt, ok := Negotiate(r.Header.Get("Accept"))
+	if !ok {
+		notAcceptable(w, r)
+		return
+	}
+
+	b, err := Marshal(mt, v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentTypes[mt])
+	w.Write(b)
+}
+
+// Marshal v in the media type
+func Marshal(mediaType string, v interface{}) ([]byte, error) {
+	b, err := json.Marshal(v)
+	if err != nil || mediaType == JSON || mediaType == HAL {
+		return b, err
+	}
+
+	// the JSON representation as generic value (maps, slices, strings, numbers, ...)
+	var generic interface{}
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if err = dec.Decode(&generic); err != nil {
+		return nil, err
+	}
+	generic = numbers(generic)
+
+	switch mediaType {
+	case XML:
+		return marshalXML(generic), nil
+	case YAML:
+		return yaml.Marshal(generic)
+	case MsgPack:
+		return marshalMsgPack(generic), nil
+	}
+	return nil, fmt.Errorf("unsupported media type: %s", mediaType)
+}
+
+// numbers convert the json.Number in int64 or float64
+func numbers(v interface{}) interface{} {
+	switch t := v.(type) {
+	case json.Number:
+		if i, err := t.Int64(); err == nil {
+			return i
+		}
+		f, _ := t.Float64()
+		return f
+	case map[string]interface{}:
+		for k, e := range t {
+			t[k] = numbers(e)
+		}
+	case []interface{}:
+		for i, e := range t {
+			t[i] = numbers(e)
+		}
+	}
+	return v
+}
+
+// sortedKeys of the map, for a stable output
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
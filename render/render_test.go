@@ -0,0 +1,138 @@
+package render
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type hero struct {
+	ID    int64   `json:"id"`
+	Name  string  `json:"name"`
+	Score float64 `json:"score,omitempty"`
+	Team  *string `json:"team"`
+}
+
+func TestNegotiate(t *testing.T) {
+	for _, tc := range []struct {
+		accept, mediaType string
+		ok                bool
+	}{
+		{"", JSON, true},
+		{"*/*", JSON, true},
+		{"application/*", JSON, true},
+		{"text/xml", XML, true},
+		{"application/xml;q=0.5, application/x-yaml", YAML, true},
+		{"text/html, application/msgpack;q=0.9, */*;q=0.1", JSON, true},
+		{"text/html,application/xhtml+xml,application/xml;q=0.9,*/*;q=0.8", JSON, true},
+		{"application/xml, application/json", JSON, true},
+		{"application/xml, application/json;q=0.9", XML, true},
+		{"application/json;q=0.9, */*", JSON, true},
+		{"application/msgpack, application/*;q=0.5", JSON, true},
+		{"application/yaml, application/xml", YAML, true},
+		{"application/json;q=0, */*", XML, true},
+		{"text/html", "", false},
+		{"application/json;q=0", "", false},
+	} {
+		mt, ok := Negotiate(tc.accept)
+		if mt != tc.mediaType || ok != tc.ok {
+			t.Errorf("expect %v %v for: %v, got: %v %v", tc.mediaType, tc.ok, tc.accept, mt, ok)
+		}
+	}
+}
+
+func TestMarshal(t *testing.T) {
+	heroes := []hero{{ID: 1, Name: "Jasmin & Co"}, {ID: 300, Name: "Alex", Score: 1.5}}
+
+	for _, tc := range []struct {
+		mediaType, expect string
+	}{
+		{JSON, `[{"id":1,"name":"Jasmin \u0026 Co","team":null},{"id":300,"name":"Alex","score":1.5,"team":null}]`},
+		{XML, `<?xml version="1.0" encoding="UTF-8"?>` + "\n" +
+			`<response><item><id>1</id><name>Jasmin &amp; Co</name><team/></item>` +
+			`<item><id>300</id><name>Alex</name><score>1.5</score><team/></item></response>`},
+		{YAML, "- id: 1\n  name: Jasmin & Co\n  team: null\n- id: 300\n  name: Alex\n  score: 1.5\n  team: null\n"},
+	} {
+		b, err := Marshal(tc.mediaType, heroes)
+		if err != nil {
+			t.Errorf("no err expected: %v", err)
+		}
+		if string(b) != tc.expect {
+			t.Errorf("%v:\n%v\n!=\n%v", tc.mediaType, tc.expect, string(b))
+		}
+	}
+}
+
+func TestMarshalXMLKeys(t *testing.T) {
+	b, _ := Marshal(XML, map[int64]int{5: 10})
+	expect := `<?xml version="1.0" encoding="UTF-8"?>` + "\n" + `<response><item key="5">10</item></response>`
+	if string(b) != expect {
+		t.Errorf("%v != %v", expect, string(b))
+	}
+}
+
+func TestMarshalMsgPack(t *testing.T) {
+	b, err := Marshal(MsgPack, map[string]interface{}{
+		"a": []interface{}{int64(1), int64(-1), int64(300), true, nil},
+		"b": 1.5,
+	})
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+
+	expect := []byte{
+		0x82,
+		0xa1, 'a', 0x95, 0x01, 0xff, 0xcf, 0, 0, 0, 0, 0, 0, 0x01, 0x2c, 0xc3, 0xc0,
+		0xa1, 'b', 0xcb, 0x3f, 0xf8, 0, 0, 0, 0, 0, 0,
+	}
+	if !bytes.Equal(b, expect) {
+		t.Errorf("% x != % x", expect, b)
+	}
+
+	// long string: str 8
+	long := string(make([]byte, 40))
+	b, _ = Marshal(MsgPack, long)
+	if b[0] != 0xd9 || b[1] != 40 || len(b) != 42 {
+		t.Errorf("expect str 8 header, got: % x", b[:2])
+	}
+}
+
+func TestWrite(t *testing.T) {
+	for _, tc := range []struct {
+		accept, contentType string
+		status              int
+	}{
+		{"", "application/json; charset=utf-8", http.StatusOK},
+		{"application/xml", "application/xml; charset=utf-8", http.StatusOK},
+		{"application/x-yaml", "application/x-yaml; charset=utf-8", http.StatusOK},
+		{"application/msgpack", "application/msgpack", http.StatusOK},
+		{"image/png", "text/plain; charset=utf-8", http.StatusNotAcceptable},
+	} {
+		r := httptest.NewRequest("GET", "http://localhost:8080/api/heroes/1", nil)
+		r.Header.Set("Accept", tc.accept)
+		w := httptest.NewRecorder()
+		Write(w, r, hero{ID: 1, Name: "Jasmin"})
+
+		if w.Code != tc.status || w.Header().Get("Content-Type") != tc.contentType {
+			t.Errorf("expect %v %v for: %v, got: %v %v", tc.status, tc.contentType, tc.accept, w.Code, w.Header().Get("Content-Type"))
+		}
+		if w.Header().Get("Vary") != "Accept" {
+			t.Errorf("expect Vary: Accept, got: %v", w.Header().Get("Vary"))
+		}
+	}
+}
+
+func TestHandler(t *testing.T) {
+	called := false
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+
+	r := httptest.NewRequest("POST", "http://localhost:8080/api/heroes", nil)
+	r.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+
+	if w.Code != http.StatusNotAcceptable || called {
+		t.Errorf("expect 406 without calling the handler, got: %v %v", w.Code, called)
+	}
+}
@@ -0,0 +1,59 @@
+package render
+
+import (
+	"bytes"
+	"encoding/xml"
+	"regexp"
+	"strconv"
+)
+
+// a simple XML name (without namespace)
+var xmlName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_.-]*$`)
+
+// marshalXML write the generic value in the root element: response,
+// the items of a list are item elements, keys, which are no XML names, are item elements with a key attribute
+func marshalXML(v interface{}) []byte {
+	buf := &bytes.Buffer{}
+	buf.WriteString(xml.Header)
+	writeXML(buf, "response", "", v)
+	return buf.Bytes()
+}
+
+func writeXML(buf *bytes.Buffer, name, key string, v interface{}) {
+	buf.WriteString("<" + name)
+	if key != "" {
+		buf.WriteString(` key="`)
+		xml.EscapeText(buf, []byte(key))
+		buf.WriteString(`"`)
+	}
+	if v == nil {
+		buf.WriteString("/>")
+		return
+	}
+	buf.WriteString(">")
+
+	switch t := v.(type) {
+	case map[string]interface{}:
+		for _, k := range sortedKeys(t) {
+			if xmlName.MatchString(k) {
+				writeXML(buf, k, "", t[k])
+			} else {
+				writeXML(buf, "item", k, t[k])
+			}
+		}
+	case []interface{}:
+		for _, e := range t {
+			writeXML(buf, "item", "", e)
+		}
+	case string:
+		xml.EscapeText(buf, []byte(t))
+	case int64:
+		buf.WriteString(strconv.FormatInt(t, 10))
+	case float64:
+		buf.WriteString(strconv.FormatFloat(t, 'g', -1, 64))
+	case bool:
+		buf.WriteString(strconv.FormatBool(t))
+	}
+
+	buf.WriteString("</" + name + ">")
+}
@@ -0,0 +1,174 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/lima1909/goheroes-appengine/auth"
+	"github.com/lima1909/goheroes-appengine/heroespb"
+	"github.com/lima1909/goheroes-appengine/policy"
+	"github.com/lima1909/goheroes-appengine/ratelimit"
+	"github.com/lima1909/goheroes-appengine/service"
+	"github.com/lima1909/goheroes-appengine/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// Permissions is the required Permission for every gRPC method (the same as the REST routes)
+var Permissions = map[string]policy.Permission{
+	heroespb.HeroService_ListHeroes_FullMethodName:        policy.ReadHeroes,
+	heroespb.HeroService_GetHero_FullMethodName:           policy.ReadHeroes,
+	heroespb.HeroService_AddHero_FullMethodName:           policy.AddHero,
+	heroespb.HeroService_UpdateHero_FullMethodName:        policy.UpdateHero,
+	heroespb.HeroService_MoveHero_FullMethodName:          policy.MoveHero,
+	heroespb.HeroService_DeleteHero_FullMethodName:        policy.DeleteHero,
+	heroespb.ScoreService_Scores_FullMethodName:           policy.ReadHeroes,
+	heroespb.ProtocolService_ListProtocols_FullMethodName: policy.ReadProtocol,
+	heroespb.ProtocolService_TailProtocols_FullMethodName: policy.ReadProtocol,
+}
+
+// Guard resolve the tenant, authenticate and authorize the gRPC calls like the REST API,
+// the metadata of the call are the headers for the Resolver and the Authenticator
+type Guard struct {
+	// resolve the tenant (nil: no tenants)
+	Resolver tenant.Resolver
+	// authenticate the calls (nil: no authentication), public calls need no credentials
+	Authenticator auth.Authenticator
+	Public        func(r *http.Request) bool
+	// authorize the calls with the roles of the Principal (nil: all calls are allowed)
+	Policy *policy.Policy
+	// limit the calls of the clients (nil: no rate limiting)
+	Limiter *ratelimit.Limiter
+}
+
+// UnaryInterceptor check the unary calls
+func (g *Guard) UnaryInterceptor(c context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	c, err := g.check(c, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(c, req)
+}
+
+// StreamInterceptor check the streaming calls
+func (g *Guard) StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	c, err := g.check(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, c: c})
+}
+
+// ServerOptions are the interceptors of the Guard for the gRPC server
+func (g *Guard) ServerOptions() []grpc.ServerOption {
+	return []grpc.ServerOption{
+		grpc.UnaryInterceptor(g.UnaryInterceptor),
+		grpc.StreamInterceptor(g.StreamInterceptor),
+	}
+}
+
+// check the call and returns the Context with the tenant and the Principal
+func (g *Guard) check(c context.Context, method string) (context.Context, error) {
+	r, err := request(c, method)
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+
+	if g.Resolver != nil {
+		t, err := g.Resolver(r)
+		if err == tenant.ErrNoTenant {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		} else if err != nil {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		if !tenant.Valid(t) {
+			return nil, status.Errorf(codes.InvalidArgument, "%v: %s", tenant.ErrInvalidTenant, t)
+		}
+		c = service.WithTenant(c, t)
+	}
+
+	var principal *auth.Principal
+	if g.Authenticator != nil {
+		principal, err = g.Authenticator.Authenticate(r)
+		if err == auth.ErrNoCredentials && g.Public != nil && g.Public(r) {
+			principal = nil
+		} else if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		} else {
+			c = auth.WithPrincipal(c, principal)
+		}
+	}
+
+	if g.Policy != nil {
+		roles := []string{policy.Viewer}
+		if principal != nil {
+			roles = append(roles, principal.Roles...)
+		}
+		perm, ok := Permissions[method]
+		if !ok || !g.Policy.Allow(roles, perm) {
+			msg := "permission denied for: " + method
+			if ok {
+				msg = fmt.Sprintf("permission: %s required (roles: %s)", perm, strings.Join(g.Policy.Roles(perm), ", "))
+			}
+			if principal == nil {
+				return nil, status.Error(codes.Unauthenticated, msg)
+			}
+			return nil, status.Error(codes.PermissionDenied, msg)
+		}
+	}
+
+	if g.Limiter != nil {
+		// with the tenant and the Principal for the key of the client
+		if _, err := g.Limiter.Allow(r.WithContext(c)); err != nil {
+			return nil, status.Error(codes.ResourceExhausted, err.Error())
+		}
+	}
+
+	return c, nil
+}
+
+// request create the http.Request of the call: the metadata are the header, the reads (see: Permissions) are GET,
+// all other calls are POST (for the public function of the authentication)
+func request(c context.Context, method string) (*http.Request, error) {
+	httpMethod := "POST"
+	if perm := Permissions[method]; perm == policy.ReadHeroes || perm == policy.ReadProtocol {
+		httpMethod = "GET"
+	}
+
+	md, _ := metadata.FromIncomingContext(c)
+	host := ""
+	if a := md.Get(":authority"); len(a) > 0 {
+		host = a[0]
+	}
+
+	r, err := http.NewRequest(httpMethod, "http://"+host+method, nil)
+	if err != nil {
+		return nil, err
+	}
+	r.Host = host
+	if p, ok := peer.FromContext(c); ok {
+		r.RemoteAddr = p.Addr.String()
+	}
+	for k, v := range md {
+		if !strings.HasPrefix(k, ":") {
+			r.Header[http.CanonicalHeaderKey(k)] = v
+		}
+	}
+	return r.WithContext(c), nil
+}
+
+// serverStream with the Context of the Guard
+type serverStream struct {
+	grpc.ServerStream
+	c context.Context
+}
+
+// Context impl from grpc.ServerStream
+func (s *serverStream) Context() context.Context {
+	return s.c
+}
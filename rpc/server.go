@@ -0,0 +1,276 @@
+// Package rpc is the gRPC server of the heroes service (see: heroespb/heroes.proto),
+// it delegates to the same services as the REST API.
+package rpc
+
+import (
+	"context"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/events"
+	"github.com/lima1909/goheroes-appengine/heroespb"
+	"github.com/lima1909/goheroes-appengine/service"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// the page size of ListProtocols (like the REST API)
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 1000
+)
+
+// Server impl from the gRPC services: HeroService, ScoreService and ProtocolService
+type Server struct {
+	heroespb.UnimplementedHeroServiceServer
+	heroespb.UnimplementedScoreServiceServer
+	heroespb.UnimplementedProtocolServiceServer
+
+	HeroService  service.ProtocolHeroService
+	ScoreService service.ScoreService
+	// Events are the new Protocols of TailProtocols (nil: TailProtocols is not available)
+	Events *events.Broker
+}
+
+// NewServer create a Server for the services
+func NewServer(heroes service.ProtocolHeroService, scores service.ScoreService) *Server {
+	return &Server{HeroService: heroes, ScoreService: scores}
+}
+
+// Register the services of the Server on the gRPC server
+func (s *Server) Register(gs *grpc.Server) {
+	heroespb.RegisterHeroServiceServer(gs, s)
+	heroespb.RegisterScoreServiceServer(gs, s)
+	heroespb.RegisterProtocolServiceServer(gs, s)
+}
+
+// ListHeroes impl from HeroServiceServer
+func (s *Server) ListHeroes(c context.Context, req *heroespb.ListHeroesRequest) (*heroespb.ListHeroesResponse, error) {
+	heroes, err := s.HeroService.List(c, req.GetName())
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	resp := &heroespb.ListHeroesResponse{Heroes: make([]*heroespb.Hero, len(heroes))}
+	for i, h := range heroes {
+		resp.Heroes[i] = NewHero(h)
+	}
+	return resp, nil
+}
+
+// GetHero impl from HeroServiceServer
+func (s *Server) GetHero(c context.Context, req *heroespb.GetHeroRequest) (*heroespb.Hero, error) {
+	return hero(s.HeroService.GetByID(c, req.GetId()))
+}
+
+// AddHero impl from HeroServiceServer
+func (s *Server) AddHero(c context.Context, req *heroespb.AddHeroRequest) (*heroespb.Hero, error) {
+	return hero(s.HeroService.Add(c, req.GetName()))
+}
+
+// UpdateHero impl from HeroServiceServer
+func (s *Server) UpdateHero(c context.Context, req *heroespb.UpdateHeroRequest) (*heroespb.Hero, error) {
+	if req.GetHero() == nil {
+		return nil, status.Error(codes.InvalidArgument, "the hero is required")
+	}
+	return hero(s.HeroService.Update(c, Hero(req.GetHero())))
+}
+
+// MoveHero impl from HeroServiceServer
+func (s *Server) MoveHero(c context.Context, req *heroespb.MoveHeroRequest) (*heroespb.Hero, error) {
+	if req.GetHero() == nil {
+		return nil, status.Error(codes.InvalidArgument, "the hero is required")
+	}
+	return hero(s.HeroService.UpdatePosition(c, Hero(req.GetHero()), req.GetPos()))
+}
+
+// DeleteHero impl from HeroServiceServer
+func (s *Server) DeleteHero(c context.Context, req *heroespb.DeleteHeroRequest) (*heroespb.Hero, error) {
+	return hero(s.HeroService.Delete(c, req.GetId()))
+}
+
+// Scores impl from ScoreServiceServer
+func (s *Server) Scores(c context.Context, req *heroespb.ScoresRequest) (*heroespb.ScoresResponse, error) {
+	scores, err := s.ScoreService.Scores(c, s.HeroService)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	resp := &heroespb.ScoresResponse{Scores: map[int64]int32{}}
+	for id, score := range scores {
+		resp.Scores[id] = int32(score)
+	}
+	return resp, nil
+}
+
+// ListProtocols impl from ProtocolServiceServer
+func (s *Server) ListProtocols(c context.Context, req *heroespb.ListProtocolsRequest) (*heroespb.ListProtocolsResponse, error) {
+	q, err := ProtocolQuery(req)
+	if err != nil {
+		return nil, err
+	}
+	protocols, err := s.HeroService.QueryProtocols(c, q)
+	if err != nil {
+		return nil, Error(err)
+	}
+
+	resp := &heroespb.ListProtocolsResponse{Protocols: make([]*heroespb.Protocol, len(protocols))}
+	for i, p := range protocols {
+		resp.Protocols[i] = NewProtocol(p)
+	}
+	return resp, nil
+}
+
+// TailProtocols impl from ProtocolServiceServer, the existing Protocols (sorted by time) are sent
+// and then the new Protocols of the Events, until the call is canceled
+func (s *Server) TailProtocols(req *heroespb.TailProtocolsRequest, stream heroespb.ProtocolService_TailProtocolsServer) error {
+	if s.Events == nil {
+		return status.Error(codes.Unimplemented, "no events for the tail of the protocols")
+	}
+	c := stream.Context()
+
+	// subscribe before the existing Protocols are read, so no new Protocol is lost,
+	// the events until sent are published before the read, so their Protocols are in the existing Protocols
+	_, events, cancel, _ := s.Events.Subscribe(service.TenantFromContext(c), 0)
+	defer cancel()
+	sent := s.Events.LastID()
+
+	var last time.Time
+	if req.GetSince() != nil {
+		last = req.GetSince().AsTime()
+	}
+	protocols, err := s.HeroService.Protocols(c)
+	if err != nil {
+		return Error(err)
+	}
+	sort.SliceStable(protocols, func(i, j int) bool { return protocols[i].Time.Before(protocols[j].Time) })
+
+	for _, p := range protocols {
+		if !p.Time.After(last) {
+			continue
+		}
+		if err := stream.Send(NewProtocol(p)); err != nil {
+			return err
+		}
+	}
+
+	for {
+		select {
+		case <-c.Done():
+			return nil
+		case e, ok := <-events:
+			if !ok {
+				return status.Error(codes.Unavailable, "the tail is too slow, resume with since")
+			}
+			// the Protocol is already sent with the existing Protocols
+			if e.ID <= sent {
+				continue
+			}
+			sent = e.ID
+			if err := stream.Send(NewProtocol(e.Protocol)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// ProtocolQuery convert the request to the service.ProtocolQuery
+func ProtocolQuery(req *heroespb.ListProtocolsRequest) (service.ProtocolQuery, error) {
+	q := service.ProtocolQuery{Action: req.GetAction(), HeroID: req.GetHeroId(), Text: req.GetText()}
+	if req.GetFrom() != nil {
+		q.From = req.GetFrom().AsTime()
+	}
+	if req.GetTo() != nil {
+		q.To = req.GetTo().AsTime()
+	}
+
+	page, size := int64(req.GetPage()), int64(req.GetSize())
+	if page < 0 || size < 0 {
+		return q, status.Errorf(codes.InvalidArgument, "invalid page: %v or size: %v", page, size)
+	}
+	if page == 0 {
+		page = 1
+	}
+	if size == 0 {
+		size = DefaultPageSize
+	} else if size > MaxPageSize {
+		size = MaxPageSize
+	}
+	// the offset must be an int (on all platforms)
+	if page-1 > math.MaxInt32/size {
+		return q, status.Errorf(codes.InvalidArgument, "invalid page: %v (size: %v)", page, size)
+	}
+	q.Offset, q.Limit = int((page-1)*size), int(size)
+	return q, nil
+}
+
+// NewHero convert the service.Hero to the message
+func NewHero(h service.Hero) *heroespb.Hero {
+	return &heroespb.Hero{
+		Id:   h.ID,
+		Name: h.Name,
+		ScoreData: &heroespb.ScoreData{
+			Name:    h.ScoreData.Name,
+			City:    h.ScoreData.City,
+			Country: h.ScoreData.Country,
+		},
+	}
+}
+
+// Hero convert the message to a service.Hero
+func Hero(h *heroespb.Hero) service.Hero {
+	return service.Hero{
+		ID:   h.GetId(),
+		Name: h.GetName(),
+		ScoreData: service.ScoreData{
+			Name:    h.GetScoreData().GetName(),
+			City:    h.GetScoreData().GetCity(),
+			Country: h.GetScoreData().GetCountry(),
+		},
+	}
+}
+
+// NewProtocol convert the service.Protocol to the message
+func NewProtocol(p service.Protocol) *heroespb.Protocol {
+	return &heroespb.Protocol{
+		Action: p.Action,
+		HeroId: p.HeroID,
+		Note:   p.Note,
+		Time:   timestamppb.New(p.Time),
+		Tenant: p.Tenant,
+	}
+}
+
+func hero(h *service.Hero, err error) (*heroespb.Hero, error) {
+	if err != nil {
+		return nil, Error(err)
+	}
+	return NewHero(*h), nil
+}
+
+// the gRPC codes of the service errors (all other errors: Internal)
+var codesOfErrors = map[error]codes.Code{
+	service.ErrHeroNotFound:     codes.NotFound,
+	service.ErrPosNotFound:      codes.OutOfRange,
+	service.ErrNoContent:        codes.Unavailable,
+	service.ErrRevisionNotFound: codes.NotFound,
+	service.ErrTeamNotFound:     codes.NotFound,
+	service.ErrHeroInTeam:       codes.AlreadyExists,
+}
+
+// Error convert the service error to a gRPC status error
+func Error(err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	if code, ok := codesOfErrors[err]; ok {
+		return status.Error(code, err.Error())
+	}
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		return status.FromContextError(err).Err()
+	}
+	return status.Error(codes.Internal, err.Error())
+}
@@ -0,0 +1,253 @@
+package rpc
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/auth"
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/events"
+	"github.com/lima1909/goheroes-appengine/heroespb"
+	"github.com/lima1909/goheroes-appengine/policy"
+	"github.com/lima1909/goheroes-appengine/ratelimit"
+	"github.com/lima1909/goheroes-appengine/service"
+	"github.com/lima1909/goheroes-appengine/tenant"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// protocols is a ProtocolHeroService with the given Protocols
+type protocols struct {
+	*db.MemService
+	p []service.Protocol
+}
+
+func (p *protocols) Protocols(c context.Context) ([]service.Protocol, error) {
+	return append([]service.Protocol{}, p.p...), nil
+}
+
+func serve(t *testing.T, s *Server, opts ...grpc.ServerOption) (*grpc.ClientConn, func()) {
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	gs := grpc.NewServer(opts...)
+	s.Register(gs)
+	go gs.Serve(l)
+
+	conn, err := grpc.Dial(l.Addr().String(), grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	return conn, func() {
+		conn.Close()
+		gs.Stop()
+	}
+}
+
+func TestHeroService(t *testing.T) {
+	conn, stop := serve(t, NewServer(db.NewMemService(), nil))
+	defer stop()
+	hs := heroespb.NewHeroServiceClient(conn)
+	c := context.Background()
+
+	h, err := hs.AddHero(c, &heroespb.AddHeroRequest{Name: "Rpc"})
+	if err != nil || h.Name != "Rpc" {
+		t.Fatalf("expect the Hero Rpc, got: %v (%v)", h, err)
+	}
+
+	h.ScoreData = &heroespb.ScoreData{Name: "rpc", City: "Berlin", Country: "de"}
+	if _, err = hs.UpdateHero(c, &heroespb.UpdateHeroRequest{Hero: h}); err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+
+	got, err := hs.GetHero(c, &heroespb.GetHeroRequest{Id: h.Id})
+	if err != nil || got.GetScoreData().GetCity() != "Berlin" {
+		t.Errorf("expect the updated Hero, got: %v (%v)", got, err)
+	}
+
+	list, err := hs.ListHeroes(c, &heroespb.ListHeroesRequest{Name: "Rpc"})
+	if err != nil || len(list.Heroes) != 1 || list.Heroes[0].Id != h.Id {
+		t.Errorf("expect the Hero: %v, got: %v (%v)", h, list, err)
+	}
+
+	for _, tc := range []struct {
+		call func() error
+		code codes.Code
+	}{
+		{func() error { _, err := hs.GetHero(c, &heroespb.GetHeroRequest{Id: 9999}); return err }, codes.NotFound},
+		{func() error { _, err := hs.MoveHero(c, &heroespb.MoveHeroRequest{Hero: h, Pos: 9999}); return err }, codes.OutOfRange},
+		{func() error { _, err := hs.UpdateHero(c, &heroespb.UpdateHeroRequest{}); return err }, codes.InvalidArgument},
+		{func() error { _, err := hs.DeleteHero(c, &heroespb.DeleteHeroRequest{Id: h.Id}); return err }, codes.OK},
+		{func() error { _, err := hs.DeleteHero(c, &heroespb.DeleteHeroRequest{Id: h.Id}); return err }, codes.NotFound},
+	} {
+		if code := status.Code(tc.call()); code != tc.code {
+			t.Errorf("%v != %v", tc.code, code)
+		}
+	}
+}
+
+func TestListProtocols(t *testing.T) {
+	conn, stop := serve(t, NewServer(db.NewMemService(), nil))
+	defer stop()
+	ps := heroespb.NewProtocolServiceClient(conn)
+
+	for _, tc := range []struct {
+		req   *heroespb.ListProtocolsRequest
+		count int
+		code  codes.Code
+	}{
+		{&heroespb.ListProtocolsRequest{}, 8, codes.OK},
+		{&heroespb.ListProtocolsRequest{Action: "Delete"}, 2, codes.OK},
+		{&heroespb.ListProtocolsRequest{HeroId: 23}, 1, codes.OK},
+		{&heroespb.ListProtocolsRequest{Text: "SEARCH"}, 2, codes.OK},
+		{&heroespb.ListProtocolsRequest{From: timestamppb.New(time.Now().Add(-time.Hour))}, 4, codes.OK},
+		{&heroespb.ListProtocolsRequest{Page: 2, Size: 5}, 3, codes.OK},
+		{&heroespb.ListProtocolsRequest{Page: -1}, 0, codes.InvalidArgument},
+	} {
+		list, err := ps.ListProtocols(context.Background(), tc.req)
+		if status.Code(err) != tc.code || len(list.GetProtocols()) != tc.count {
+			t.Errorf("%v: expect %v (%v), got: %v (%v)", tc.req, tc.count, tc.code, len(list.GetProtocols()), err)
+		}
+	}
+}
+
+func TestTailProtocols(t *testing.T) {
+	now := time.Now()
+	broker := events.NewBroker(events.DefaultBufferSize)
+	hs := events.NewHeroService(&protocols{
+		MemService: db.NewMemService(),
+		p: []service.Protocol{
+			{Action: "Delete", HeroID: 2, Time: now.Add(-time.Minute)},
+			{Action: "Add", HeroID: 1, Time: now.Add(-time.Hour)},
+			{Action: "Search", Time: now.Add(-2 * time.Hour)},
+		},
+	}, broker)
+	s := NewServer(hs, nil)
+	s.Events = broker
+	conn, stop := serve(t, s)
+	defer stop()
+
+	c, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	stream, err := heroespb.NewProtocolServiceClient(conn).TailProtocols(c,
+		&heroespb.TailProtocolsRequest{Since: timestamppb.New(now.Add(-90 * time.Minute))})
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+
+	// the existing Protocols after since (sorted by time), then the new one
+	for _, action := range []string{"Add", "Delete"} {
+		p, err := stream.Recv()
+		if err != nil {
+			t.Fatalf("No err expected: %v", err)
+		}
+		if p.Action != action {
+			t.Errorf("%v != %v", action, p.Action)
+		}
+	}
+	h, err := hs.Add(context.Background(), "Tail")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	p, err := stream.Recv()
+	if err != nil || p.Action != "Add" || p.HeroId != h.ID {
+		t.Errorf("expect the new Protocol of the Hero: %v, got: %v (%v)", h.ID, p, err)
+	}
+
+	// a new Protocol with the same time as a sent Protocol
+	broker.Publish(context.Background(), service.Protocol{Action: "Update", HeroID: 2, Time: now.Add(-time.Minute)}, nil)
+	p, err = stream.Recv()
+	if err != nil || p.Action != "Update" || p.HeroId != 2 {
+		t.Errorf("expect the new Protocol with the same time, got: %v (%v)", p, err)
+	}
+}
+
+func TestGuard(t *testing.T) {
+	g := &Guard{
+		Resolver: tenant.Header(tenant.HeaderTenant),
+		Authenticator: auth.APIKeys{
+			"editor": auth.Principal{Name: "mario", Roles: []string{policy.Editor}},
+			"admin":  auth.Principal{Name: "jasmin", Roles: []string{policy.Admin}},
+		},
+		Public: auth.IsRead,
+		Policy: policy.New(policy.DefaultRoles()),
+	}
+	conn, stop := serve(t, NewServer(db.NewMemService(), nil), g.ServerOptions()...)
+	defer stop()
+	hs := heroespb.NewHeroServiceClient(conn)
+	ps := heroespb.NewProtocolServiceClient(conn)
+
+	for _, tc := range []struct {
+		tenant, key string
+		call        func(c context.Context) error
+		code        codes.Code
+	}{
+		{"", "", func(c context.Context) error { _, err := hs.ListHeroes(c, &heroespb.ListHeroesRequest{}); return err }, codes.Unauthenticated},
+		{"a b", "", func(c context.Context) error { _, err := hs.ListHeroes(c, &heroespb.ListHeroesRequest{}); return err }, codes.InvalidArgument},
+		{"one", "", func(c context.Context) error { _, err := hs.ListHeroes(c, &heroespb.ListHeroesRequest{}); return err }, codes.OK},
+		{"one", "", func(c context.Context) error {
+			_, err := hs.AddHero(c, &heroespb.AddHeroRequest{Name: "x"})
+			return err
+		}, codes.Unauthenticated},
+		{"one", "invalid", func(c context.Context) error { _, err := hs.ListHeroes(c, &heroespb.ListHeroesRequest{}); return err }, codes.Unauthenticated},
+		{"one", "editor", func(c context.Context) error {
+			_, err := hs.AddHero(c, &heroespb.AddHeroRequest{Name: "x"})
+			return err
+		}, codes.OK},
+		{"one", "editor", func(c context.Context) error {
+			_, err := hs.DeleteHero(c, &heroespb.DeleteHeroRequest{Id: 1})
+			return err
+		}, codes.PermissionDenied},
+		{"one", "admin", func(c context.Context) error {
+			_, err := hs.DeleteHero(c, &heroespb.DeleteHeroRequest{Id: 1})
+			return err
+		}, codes.OK},
+		{"one", "editor", func(c context.Context) error {
+			stream, err := ps.TailProtocols(c, &heroespb.TailProtocolsRequest{})
+			if err == nil {
+				_, err = stream.Recv()
+			}
+			return err
+		}, codes.PermissionDenied},
+	} {
+		md := metadata.MD{}
+		if tc.tenant != "" {
+			md.Set(tenant.HeaderTenant, tc.tenant)
+		}
+		if tc.key != "" {
+			md.Set("Authorization", "ApiKey "+tc.key)
+		}
+		if code := status.Code(tc.call(metadata.NewOutgoingContext(context.Background(), md))); code != tc.code {
+			t.Errorf("%v != %v (tenant: %q, key: %q)", tc.code, code, tc.tenant, tc.key)
+		}
+	}
+}
+
+func TestGuardRateLimit(t *testing.T) {
+	g := &Guard{Limiter: &ratelimit.Limiter{
+		Store:  ratelimit.NewMemStore(),
+		Limits: map[string]ratelimit.Limit{ratelimit.Write: {Requests: 1, Per: time.Hour}},
+	}}
+	conn, stop := serve(t, NewServer(db.NewMemService(), nil), g.ServerOptions()...)
+	defer stop()
+	hs := heroespb.NewHeroServiceClient(conn)
+	c := context.Background()
+
+	if _, err := hs.AddHero(c, &heroespb.AddHeroRequest{Name: "first"}); err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if _, err := hs.AddHero(c, &heroespb.AddHeroRequest{Name: "second"}); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("expect ResourceExhausted, got: %v", err)
+	}
+	// the reads have no Limit
+	if _, err := hs.ListHeroes(c, &heroespb.ListHeroesRequest{}); err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+}
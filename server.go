@@ -2,19 +2,32 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"html/template"
-	"io/ioutil"
 	"log"
+	"math"
 	"net/http"
+	"os"
 	"reflect"
 	"strconv"
+	"strings"
 	"time"
 
+	"github.com/lima1909/goheroes-appengine/auth"
+	"github.com/lima1909/goheroes-appengine/body"
+	"github.com/lima1909/goheroes-appengine/cors"
+	"github.com/lima1909/goheroes-appengine/cron"
 	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/events"
 	"github.com/lima1909/goheroes-appengine/gcloud"
+	"github.com/lima1909/goheroes-appengine/history"
+	"github.com/lima1909/goheroes-appengine/openapi"
+	"github.com/lima1909/goheroes-appengine/policy"
+	"github.com/lima1909/goheroes-appengine/ratelimit"
+	"github.com/lima1909/goheroes-appengine/render"
 	"github.com/lima1909/goheroes-appengine/score"
+	"github.com/lima1909/goheroes-appengine/tenant"
+	"github.com/lima1909/goheroes-appengine/webhook"
 
 	"github.com/gorilla/mux"
 	"github.com/lima1909/goheroes-appengine/service"
@@ -31,85 +44,348 @@ var app = NewApp()
 type App struct {
 	service.ProtocolHeroService
 	service.ScoreService
+	service.TeamService
+
+	// resolve the tenant of the API requests (nil: no tenants)
+	tenantResolver tenant.Resolver
+	// authenticate the API requests (nil: no authentication), public requests need no credentials
+	authenticator auth.Authenticator
+	public        func(r *http.Request) bool
+	// authorize the requests with the roles of the Principal (without Principal: the role viewer)
+	policy *policy.Policy
+	// accept only cron (or shared secret) requests for the worker
+	workerGuard *cron.Guard
+	// the CORS policy for the browser clients
+	cors *cors.Options
+	// limit the requests per client (nil: no rate limiting)
+	limiter *ratelimit.Limiter
+	// the sunset of the API v1 (zero: no Sunset header)
+	v1Sunset time.Time
+	// validate the API requests against the OpenAPI document (nil: no validation)
+	validator *openapi.Validator
+	// the changes of the Heroes for the Server-Sent Events
+	events *events.Broker
+	// deliver the changes to the subscribed webhooks (nil: in the cloud)
+	webhooks *webhook.Dispatcher
+	// serve the admin reset of the Heroes (see: EnvAdminReset)
+	adminReset bool
 
 	// Info to the current system
 	HeroesServiceStr string
 	RunInCloud       bool
+	TenantMode       string
 	AppIsStarted     string
 }
 
 // NewApp create a new App instance
 func NewApp() *App {
-	var svc service.ProtocolHeroService = db.NewMemService()
-	var scoreSvc = score.Default()
+	// check the configuration once, before create the services for every tenant
+	if _, err := db.NewMemServiceFromEnv(); err != nil {
+		log.Fatalf("can not create the MemService: %v", err)
+	}
+	resolver, err := tenant.ResolverFromEnv()
+	if err != nil {
+		log.Fatalf("can not create the tenant resolver: %v", err)
+	}
+	authenticator, public, err := auth.FromEnv()
+	if err != nil {
+		log.Fatalf("can not create the authenticator: %v", err)
+	}
+	// without authentication all requests have the role viewer
+	pol, err := policy.FromEnv()
+	if err != nil {
+		log.Fatalf("can not create the policy: %v", err)
+	}
+	for route, perm := range routePermissions {
+		pol.Require(route, perm)
+	}
+
+	corsOpts, err := cors.FromEnv()
+	if err != nil {
+		log.Fatalf("can not create the CORS options: %v", err)
+	}
+	corsOpts.AllowedHeaders = append(corsOpts.AllowedHeaders, tenant.HeaderTenant, tenant.HeaderAPIKey)
+
+	limiter, err := ratelimit.FromEnv()
+	if err != nil {
+		log.Fatalf("can not create the rate limiter: %v", err)
+	}
+	if limiter != nil {
+		limiter.Class = rateClass
+		// only the tenants of the API keys are verified
+		if os.Getenv(tenant.EnvMode) == "apikey" {
+			limiter.Key = ratelimit.TenantClient
+		}
+	}
+
+	v1Sunset, err := sunsetFromEnv()
+	if err != nil {
+		log.Fatalf("can not parse the sunset of the API v1: %v", err)
+	}
+
+	validator, err := openAPIValidatorFromEnv()
+	if err != nil {
+		log.Fatalf("can not create the OpenAPI validator: %v", err)
+	}
+
+	broker, err := events.FromEnv()
+	if err != nil {
+		log.Fatalf("can not create the events broker: %v", err)
+	}
+	pub := service.Publishers{broker}
+
+	// the webhooks are delivered with goroutines and the http.Client, this is not supported in the App Engine runtime
+	var webhooks *webhook.Dispatcher
+	if !service.RunInCloud() {
+		webhooks, err = webhook.FromEnv()
+		if err != nil {
+			log.Fatalf("can not create the webhook dispatcher: %v", err)
+		}
+		pub = append(pub, webhooks)
+	}
+
+	adminReset, _ := strconv.ParseBool(os.Getenv(EnvAdminReset))
+
+	var scoreSvc service.ScoreService = score.Default()
 
 	// if run in cloud, than replace the service
 	if service.RunInCloud() {
-		svc = gcloud.NewHeroService(db.NewMemService())
 		scoreSvc = score.New(func(c context.Context) *http.Client {
 			return urlfetch.Client(c)
 		})
 	}
+	// publish the changed scores
+	scoreSvc = events.NewScoreService(scoreSvc, pub)
+
+	// every tenant get his own services
+	create := func() service.ProtocolHeroService {
+		return newHeroService(pub)
+	}
+	svc := tenant.NewHeroService(create)
+	teamSvc := tenant.NewTeamService(func() service.TeamService {
+		return db.NewTeamService(svc)
+	})
 
 	return &App{
 		ProtocolHeroService: svc,
 		ScoreService:        scoreSvc,
-
-		HeroesServiceStr: reflect.TypeOf(svc).String(),
+		TeamService:         teamSvc,
+
+		tenantResolver: resolver,
+		authenticator:  authenticator,
+		public:         public,
+		policy:         pol,
+		workerGuard:    cron.GuardFromEnv(),
+		cors:           corsOpts,
+		limiter:        limiter,
+		v1Sunset:       v1Sunset,
+		validator:      validator,
+		events:         broker,
+		webhooks:       webhooks,
+		adminReset:     adminReset,
+
+		HeroesServiceStr: reflect.TypeOf(create()).String(),
 		RunInCloud:       service.RunInCloud(),
+		TenantMode:       os.Getenv(tenant.EnvMode),
 		AppIsStarted:     time.Now().Local().Format("2006.01.02 15:04:05"),
 	}
 }
 
-// handle CORS and the OPION method
-func corsAndOptionHandler(h http.Handler) http.HandlerFunc {
-	return func(w http.ResponseWriter, r *http.Request) {
-		w.Header().Set("Access-Control-Allow-Origin", "*")
-		w.Header().Set("Access-Control-Allow-Methods", "POST, GET, OPTIONS, PUT, DELETE")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type")
+// the required Permission for every route (by route name)
+var routePermissions = map[string]policy.Permission{
+	"root":                       policy.ReadHeroes,
+	"info":                       policy.ReadHeroes,
+	"heroes.list":                policy.ReadHeroes,
+	"heroes.list.slash":          policy.ReadHeroes,
+	"heroes.invalid":             policy.ReadHeroes,
+	"heroes.add":                 policy.AddHero,
+	"heroes.move":                policy.MoveHero,
+	"heroes.update":              policy.UpdateHero,
+	"heroes.trash":               policy.ReadHeroes,
+	"heroes.scores":              policy.ReadHeroes,
+	"heroes.events":              policy.ReadHeroes,
+	"heroes.live":                policy.ReadHeroes, // the commands are checked by the live.Hub
+	"hero.get":                   policy.ReadHeroes,
+	"hero.invalid":               policy.ReadHeroes,
+	"hero.delete":                policy.DeleteHero,
+	"hero.restore":               policy.UpdateHero,
+	"hero.history":               policy.ReadHeroes,
+	"hero.revert":                policy.UpdateHero,
+	"teams.list":                 policy.ReadHeroes,
+	"teams.add":                  policy.WriteTeams,
+	"teams.update":               policy.WriteTeams,
+	"team.get":                   policy.ReadHeroes,
+	"team.delete":                policy.DeleteTeam,
+	"team.heroes":                policy.ReadHeroes,
+	"team.hero.move":             policy.WriteTeams,
+	"team.hero.add":              policy.WriteTeams,
+	"team.hero.remove":           policy.WriteTeams,
+	"admin.reset":                policy.Reset,
+	"webhooks.list":              policy.ManageWebhooks,
+	"webhooks.add":               policy.ManageWebhooks,
+	"webhook.get":                policy.ManageWebhooks,
+	"webhook.update":             policy.ManageWebhooks,
+	"webhook.delete":             policy.ManageWebhooks,
+	"admin.webhooks.deliveries":  policy.ManageWebhooks,
+	"admin.webhooks.deadletters": policy.ManageWebhooks,
+	"admin.webhooks.redeliver":   policy.ManageWebhooks,
+	"protocol":                   policy.ReadProtocol,
+	"protocol.count":             policy.ReadProtocol,
+	"worker.protocol":            policy.RunWorker,
+	"openapi":                    policy.ReadHeroes,
+	"graphql":                    policy.ReadHeroes, // the mutations and the protocols are checked in the resolver
+}
 
-		if r.Method == "OPTIONS" {
-			w.WriteHeader(http.StatusOK)
-		} else {
-			h.ServeHTTP(w, r)
-		}
+// WorkerRejected returns the number of the rejected worker requests (for the info page)
+func (a *App) WorkerRejected() int64 {
+	return a.workerGuard.Rejected()
+}
+
+// the request body Rule for the routes with a body (all other routes: body.DefaultMaxBytes)
+var routeBodies = map[string]body.Rule{
+	"heroes.add":     body.Text(1 << 10),
+	"heroes.update":  body.JSON(16 << 10),
+	"heroes.move":    body.JSON(16 << 10),
+	"teams.add":      body.Text(1 << 10),
+	"teams.update":   body.JSON(16 << 10),
+	"webhooks.add":   body.JSON(16 << 10),
+	"webhook.update": body.JSON(16 << 10),
+	"graphql":        body.JSON(body.DefaultMaxBytes),
+}
+
+// rateClass is the rate limit class of the request: scores (calls 8a.nu for every Hero), read or write
+func rateClass(r *http.Request) string {
+	if route := mux.CurrentRoute(r); route != nil && route.GetName() == "heroes.scores" {
+		return "scores"
 	}
+	return ratelimit.MethodClass(r)
+}
+
+// newHeroService create the ProtocolHeroService for one tenant, the changes are published to the Publisher
+func newHeroService(pub service.Publisher) service.ProtocolHeroService {
+	memSvc, err := db.NewMemServiceFromEnv()
+	if err != nil {
+		log.Fatalf("can not create the MemService: %v", err)
+	}
+
+	var svc service.ProtocolHeroService = events.NewHeroService(memSvc, pub)
+
+	// if run in cloud, than replace the service
+	if service.RunInCloud() {
+		svc = gcloud.NewHeroService(memSvc, pub)
+	}
+
+	// record all changes as Revisions
+	return history.NewHeroService(svc)
+}
+
+// newContext create the appengine Context with the values of the request Context (e.g. the tenant)
+func newContext(r *http.Request) context.Context {
+	return appengine.WithContext(r.Context(), r)
 }
 
+// routes of the current Handler, to create the HAL links
+var routes *mux.Router
+
 // create all used Handler
 func handler() http.Handler {
 	router := mux.NewRouter()
+	routes = router
 
-	router.Handle("/", http.RedirectHandler("/info", http.StatusFound))
-	router.HandleFunc("/info", infoPage)
+	router.Handle("/", http.RedirectHandler("/info", http.StatusFound)).Name("root")
+	router.HandleFunc("/info", infoPage).Name("info")
 
-	url := "/api/heroes"
-	router.HandleFunc(url, heroList).Methods("GET")
-	router.HandleFunc(url, addHero).Methods("POST")
-	router.HandleFunc(url, switchHero).Methods("PUT").Queries("pos", "{pos}")
-	router.HandleFunc(url, updateHero).Methods("PUT")
+	url := apiPrefix + "/heroes"
+	router.HandleFunc(url, heroList).Methods("GET").Name("heroes.list")
+	router.HandleFunc(url, addHero).Methods("POST").Name("heroes.add")
+	router.HandleFunc(url, switchHero).Methods("PUT").Queries("pos", "{pos}").Name("heroes.move")
+	router.HandleFunc(url, updateHero).Methods("PUT").Name("heroes.update")
 	router.HandleFunc(url, func(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid method: "+r.Method, http.StatusBadRequest)
-	}).Methods("DELETE", "PATH", "COPY", "HEAD", "LINK", "UNLINK", "PURGE", "LOCK", "UNLOCK", "VIEW", "PROPFIND")
+	}).Methods("DELETE", "PATH", "COPY", "HEAD", "LINK", "UNLINK", "PURGE", "LOCK", "UNLOCK", "VIEW", "PROPFIND").Name("heroes.invalid")
 
-	urlWithID := "/api/heroes/{id:[0-9]+}"
-	router.HandleFunc(urlWithID, getHero).Methods("GET")
-	router.HandleFunc(urlWithID, deleteHero).Methods("DELETE")
+	urlWithID := apiPrefix + "/heroes/{id:[0-9]+}"
+	router.HandleFunc(urlWithID, getHero).Methods("GET").Name("hero.get")
+	router.HandleFunc(urlWithID, deleteHero).Methods("DELETE").Name("hero.delete")
 	router.HandleFunc(urlWithID, func(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, "invalid method: "+r.Method, http.StatusBadRequest)
-	}).Methods("PUT", "POST", "PATH", "COPY", "HEAD", "LINK", "UNLINK", "PURGE", "LOCK", "UNLOCK", "VIEW", "PROPFIND")
+	}).Methods("PUT", "POST", "PATH", "COPY", "HEAD", "LINK", "UNLINK", "PURGE", "LOCK", "UNLOCK", "VIEW", "PROPFIND").Name("hero.invalid")
+
+	router.HandleFunc(urlWithID+"/restore", restoreHero).Methods("POST").Name("hero.restore")
+	router.HandleFunc(apiPrefix+"/heroes/trash", heroTrash).Methods("GET").Name("heroes.trash")
+	router.HandleFunc(urlWithID+"/history", heroHistory).Methods("GET").Name("hero.history")
+	router.HandleFunc(urlWithID+"/revert", revertHero).Methods("POST").Queries("rev", "{rev:[0-9]+}").Name("hero.revert")
+
+	urlWithScores := apiPrefix + "/heroes/scores"
+	router.HandleFunc(urlWithScores, getScores).Methods("GET").Name("heroes.scores")
+	router.HandleFunc(apiPrefix+"/heroes/events", heroEvents).Methods("GET").Name("heroes.events")
+	router.HandleFunc("/api/"+V2+"/heroes/live", liveHeroes).Methods("GET").Name("heroes.live")
 
-	urlWithScores := "/api/heroes/scores"
-	router.HandleFunc(urlWithScores, getScores).Methods("GET")
+	teamRoutes(router)
+	webhookRoutes(router)
 
 	// TODO: not necessary anymore (only for the slash on the end)
-	router.HandleFunc("/api/heroes/", heroList)
+	router.HandleFunc(apiPrefix+"/heroes/", heroList).Name("heroes.list.slash")
+
+	// admin: restore the seed Heroes (fixtures), only if it is enabled
+	if app.adminReset {
+		router.HandleFunc(apiPrefix+"/admin/reset", resetHeroes).Methods("POST").Name("admin.reset")
+	}
 
 	// gcloud tries
-	router.HandleFunc("/api/heroes/protocol", protocol)
-	router.HandleFunc("/worker/protocol", subscribeAndStore)
+	router.HandleFunc(apiPrefix+"/heroes/protocol", protocol).Name("protocol")
+	router.HandleFunc(apiPrefix+"/heroes/protocol/count", countProtocols).Methods("GET").Name("protocol.count")
+	router.HandleFunc(apiPrefix+"/openapi.json", openAPI).Methods("GET").Name("openapi")
+	router.HandleFunc("/worker/protocol", subscribeAndStore).Name("worker.protocol")
+	router.HandleFunc("/graphql", graphQL).Methods("GET", "POST").Name("graphql")
+
+	router.Use(deprecationHandler(app.v1Sunset))
+	if app.limiter != nil {
+		router.Use(app.limiter.Middleware)
+	}
+	if app.policy != nil {
+		router.Use(app.policy.Middleware)
+	}
+	router.Use(body.NewLimiter(routeBodies).Middleware)
+	if app.validator != nil {
+		router.Use(app.validator.Middleware)
+	}
+
+	// preflight requests are only answered for existing routes
+	match := func(r *http.Request) bool {
+		return router.Match(r, &mux.RouteMatch{})
+	}
+	return cors.Handler(apiHandler(router), app.cors, match)
+}
+
+// apiHandler add the authentication and the tenant resolution for all API and GraphQL requests,
+// the worker requests are only accepted from cron (without authentication and tenant)
+func apiHandler(router http.Handler) http.Handler {
+	worker := cron.Handler(router, app.workerGuard)
+
+	api := render.Handler(router)
+	gql := router
+	if app.authenticator != nil {
+		api = auth.Handler(api, app.authenticator, app.public)
+		// a GraphQL query is a read with every method, the mutations are checked in the resolver
+		gql = auth.Handler(gql, app.authenticator, func(r *http.Request) bool { return app.public != nil })
+	}
+	if app.tenantResolver != nil {
+		api = tenant.Handler(api, app.tenantResolver)
+		gql = tenant.Handler(gql, app.tenantResolver)
+	}
 
-	return corsAndOptionHandler(router)
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if strings.HasPrefix(r.URL.Path, "/worker/") {
+			worker.ServeHTTP(w, r)
+		} else if strings.HasPrefix(r.URL.Path, "/api/") {
+			api.ServeHTTP(w, r)
+		} else if r.URL.Path == "/graphql" {
+			gql.ServeHTTP(w, r)
+		} else {
+			router.ServeHTTP(w, r)
+		}
+	})
 }
 
 func init() {
@@ -118,28 +394,96 @@ func init() {
 }
 
 func main() {
+	startGRPC()
 	appengine.Main()
 }
 
 func protocol(w http.ResponseWriter, r *http.Request) {
-	protocols, err := app.Protocols(appengine.NewContext(r))
+	q, err := protocolQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	protocols, err := app.QueryProtocols(newContext(r), q)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	b, err := json.Marshal(protocols)
+	writeToClient(w, r, protocols)
+}
+
+// protocolCount is the response of the count of the Protocols
+type protocolCount struct {
+	Count int `json:"count"`
+}
+
+func countProtocols(w http.ResponseWriter, r *http.Request) {
+	q, err := protocolQuery(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	count, err := app.CountProtocols(newContext(r), q)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	fmt.Fprintf(w, "%s", string(b))
+	writeToClient(w, r, protocolCount{Count: count})
+}
+
+// protocolQuery of the request, query: action, heroID, from and to (RFC 3339), q (text in the note),
+// page and size (without page and size: all Protocols)
+func protocolQuery(r *http.Request) (service.ProtocolQuery, error) {
+	values := r.URL.Query()
+	q := service.ProtocolQuery{Action: values.Get("action"), Text: values.Get("q")}
+
+	if id := values.Get("heroID"); id != "" {
+		heroID, err := strconv.ParseInt(id, 10, 64)
+		if err != nil {
+			return q, fmt.Errorf("invalid heroID: %v", id)
+		}
+		q.HeroID = heroID
+	}
+	for _, t := range []struct {
+		name string
+		time *time.Time
+	}{{"from", &q.From}, {"to", &q.To}} {
+		if v := values.Get(t.name); v != "" {
+			parsed, err := time.Parse(time.RFC3339, v)
+			if err != nil {
+				return q, fmt.Errorf("invalid %s (expect RFC 3339): %v", t.name, v)
+			}
+			*t.time = parsed
+		}
+	}
+
+	page, size, paged, err := pageParams(r)
+	if err != nil {
+		return q, err
+	}
+	if paged {
+		if size > MaxPageSize {
+			size = MaxPageSize
+		}
+		// the total is unknown, the offset must be an int (on all platforms)
+		if page-1 > math.MaxInt32/size {
+			return q, fmt.Errorf("invalid page: %v (size: %v)", page, size)
+		}
+		q.Offset, q.Limit = int((page-1)*size), int(size)
+	} else {
+		// without page and size only the first page, the protocol grows with every request
+		q.Limit = DefaultPageSize
+	}
+	return q, nil
 }
 
 func subscribeAndStore(w http.ResponseWriter, r *http.Request) {
 	if service.RunInCloud() {
-		c := appengine.NewContext(r)
+		c := newContext(r)
 
 		protocols, err := gcloud.Sub(c)
 		if err != nil {
@@ -148,21 +492,41 @@ func subscribeAndStore(w http.ResponseWriter, r *http.Request) {
 		}
 
 		for _, p := range protocols {
-			err = gcloud.Add(c, p)
+			err = gcloud.Add(service.WithTenant(c, p.Tenant), p)
 			if err != nil {
 				loga.Errorf(c, "err by add protocol to datastore: %v", err)
 			}
 
 		}
 
-		b, err := json.Marshal(protocols)
-		if err != nil {
-			http.Error(w, err.Error(), http.StatusInternalServerError)
-			return
-		}
+		writeToClient(w, r, protocols)
+	}
+}
+
+// EnvAdminReset is the Env-Variable, if it is true, the admin reset of the Heroes is served (e.g. for the e2e tests)
+const EnvAdminReset = "HEROES_ADMIN_RESET"
 
-		fmt.Fprintf(w, "%s ", string(b))
+func resetHeroes(w http.ResponseWriter, r *http.Request) {
+	rs, ok := app.ProtocolHeroService.(service.ResetService)
+	if !ok {
+		http.Error(w, "reset is not supported by: "+app.HeroesServiceStr, http.StatusNotImplemented)
+		return
+	}
+
+	c := newContext(r)
+	err := rs.Reset(c)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	heroes, err := app.List(c, "")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
 	}
+
+	writeToClient(w, r, heroes)
 }
 
 func infoPage(w http.ResponseWriter, r *http.Request) {
@@ -180,48 +544,46 @@ func infoPage(w http.ResponseWriter, r *http.Request) {
 }
 
 func getScores(w http.ResponseWriter, r *http.Request) {
-	scoreMap, err := app.Scores(appengine.NewContext(r), app.ProtocolHeroService)
+	scoreMap, err := app.Scores(newContext(r), app.ProtocolHeroService)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	b, err := json.Marshal(scoreMap)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
-		return
-	}
-
-	fmt.Fprintf(w, "%s", b)
+	writeToClient(w, r, scoreMap)
 }
 
 func heroList(w http.ResponseWriter, r *http.Request) {
-	heroes, err := app.List(appengine.NewContext(r), r.URL.Query().Get("name"))
+	heroes, err := app.List(newContext(r), r.URL.Query().Get("name"))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
 
-	b, err := json.Marshal(heroes)
+	page, size, err := pagination(r, len(heroes))
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	total := len(heroes)
+	heroes = pageOf(heroes, page, size)
 
-	fmt.Fprintf(w, "%s", string(b))
+	if render.MediaType(r) == render.HAL {
+		writeHeroListHAL(w, r, heroes, page, size, total)
+		return
+	}
+	writeToClient(w, r, heroes)
 }
 
 func addHero(w http.ResponseWriter, r *http.Request) {
-	defer r.Body.Close()
-
-	body, err := ioutil.ReadAll(r.Body)
+	b, err := body.ReadAll(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), body.Status(err))
 		return
 	}
 
-	heroName := string(body)
-	h, err := app.Add(appengine.NewContext(r), heroName)
+	heroName := string(b)
+	h, err := app.Add(newContext(r), heroName)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -239,7 +601,7 @@ func getHero(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hero, err := app.GetByID(appengine.NewContext(r), int64(id))
+	hero, err := app.GetByID(newContext(r), int64(id))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
@@ -257,24 +619,116 @@ func deleteHero(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	hero, err := app.Delete(appengine.NewContext(r), int64(id))
+	hero, err := app.Delete(newContext(r), int64(id))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeHeroToClient(w, r, hero)
+
+}
+
+func heroTrash(w http.ResponseWriter, r *http.Request) {
+	ts, ok := app.ProtocolHeroService.(service.TrashService)
+	if !ok {
+		http.Error(w, "trash is not supported by: "+app.HeroesServiceStr, http.StatusNotImplemented)
+		return
+	}
+
+	trash, err := ts.Trash(newContext(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeToClient(w, r, trash)
+}
+
+func restoreHero(w http.ResponseWriter, r *http.Request) {
+	ts, ok := app.ProtocolHeroService.(service.TrashService)
+	if !ok {
+		http.Error(w, "trash is not supported by: "+app.HeroesServiceStr, http.StatusNotImplemented)
+		return
+	}
+
+	vars := mux.Vars(r)
+	varID := vars["id"]
+	id, err := strconv.Atoi(varID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid id: %v", varID), http.StatusBadRequest)
+		return
+	}
+
+	hero, err := ts.Restore(newContext(r), int64(id))
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
 
 	writeHeroToClient(w, r, hero)
+}
+
+func heroHistory(w http.ResponseWriter, r *http.Request) {
+	hs, ok := app.ProtocolHeroService.(service.HistoryService)
+	if !ok {
+		http.Error(w, "history is not supported by: "+app.HeroesServiceStr, http.StatusNotImplemented)
+		return
+	}
+
+	vars := mux.Vars(r)
+	varID := vars["id"]
+	id, err := strconv.Atoi(varID)
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid id: %v", varID), http.StatusBadRequest)
+		return
+	}
+
+	revs, err := hs.History(newContext(r), int64(id))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeToClient(w, r, revs)
+}
+
+func revertHero(w http.ResponseWriter, r *http.Request) {
+	hs, ok := app.ProtocolHeroService.(service.HistoryService)
+	if !ok {
+		http.Error(w, "history is not supported by: "+app.HeroesServiceStr, http.StatusNotImplemented)
+		return
+	}
+
+	vars := mux.Vars(r)
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid id: %v", vars["id"]), http.StatusBadRequest)
+		return
+	}
+	rev, err := strconv.Atoi(vars["rev"])
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid rev: %v", vars["rev"]), http.StatusBadRequest)
+		return
+	}
 
+	hero, err := hs.Revert(newContext(r), int64(id), rev)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeHeroToClient(w, r, hero)
 }
 
 func updateHero(w http.ResponseWriter, r *http.Request) {
 	hero, err := getHeroFromService(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), body.Status(err))
 		return
 	}
 
-	h, err := app.Update(appengine.NewContext(r), hero)
+	h, err := app.Update(newContext(r), hero)
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
@@ -287,7 +741,7 @@ func switchHero(w http.ResponseWriter, r *http.Request) {
 
 	hero, err := getHeroFromService(r)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), body.Status(err))
 		return
 	}
 
@@ -298,8 +752,11 @@ func switchHero(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	h, err := app.UpdatePosition(appengine.NewContext(r), hero, int64(posNb))
-	if err != nil {
+	h, err := app.UpdatePosition(newContext(r), hero, int64(posNb))
+	if err == service.ErrHeroNotFound || err == service.ErrPosNotFound {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	} else if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
@@ -309,18 +766,30 @@ func switchHero(w http.ResponseWriter, r *http.Request) {
 }
 
 func getHeroFromService(r *http.Request) (service.Hero, error) {
-	defer r.Body.Close()
+	if apiVersion(r) == V2 {
+		hero := heroV2{}
+		err := body.DecodeJSON(r, &hero)
+		return hero.Hero(), err
+	}
 
 	hero := service.Hero{}
-	return hero, json.NewDecoder(r.Body).Decode(&hero)
+	return hero, body.DecodeJSON(r, &hero)
 }
 
 func writeHeroToClient(w http.ResponseWriter, r *http.Request, h *service.Hero) {
-	b, err := json.Marshal(h)
-	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if render.MediaType(r) == render.HAL {
+		res, err := heroResource(r, h)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeToClient(w, r, res)
 		return
 	}
+	writeToClient(w, r, h)
+}
 
-	fmt.Fprintf(w, "%s", string(b))
+// writeToClient write v in the media type of the Accept header (JSON, XML, YAML or MessagePack)
+func writeToClient(w http.ResponseWriter, r *http.Request, v interface{}) {
+	render.Write(w, r, versioned(r, v))
 }
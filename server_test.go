@@ -11,16 +11,51 @@ import (
 	"os"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/lima1909/goheroes-appengine/auth"
+	"github.com/lima1909/goheroes-appengine/cron"
 	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/history"
+	"github.com/lima1909/goheroes-appengine/policy"
+	"github.com/lima1909/goheroes-appengine/ratelimit"
 	"github.com/lima1909/goheroes-appengine/service"
+	"github.com/lima1909/goheroes-appengine/tenant"
 )
 
 var (
-	server = httptest.NewServer(handler())
+	// the requests of the tests are without authentication, so the server is without the policy
+	// (the permissions are tested with their own server)
+	server = func() *httptest.Server {
+		app.policy = nil
+		return newTestServer()
+	}()
 )
 
+// newTestServer create the server, which validate the requests and the responses against the OpenAPI document
+func newTestServer() *httptest.Server {
+	app.validator = newOpenAPIValidator(true)
+	return httptest.NewServer(handler())
+}
+
+// resetTestHeroes restore the seed Heroes before and after the test (with the ResetService)
+func resetTestHeroes(t *testing.T) {
+	t.Helper()
+
+	reset := func() {
+		rs, ok := app.ProtocolHeroService.(service.ResetService)
+		if !ok {
+			t.Fatalf("reset is not supported by: %T", app.ProtocolHeroService)
+		}
+		if err := rs.Reset(context.TODO()); err != nil {
+			t.Fatalf("No err expected: %v", err)
+		}
+	}
+	reset()
+	t.Cleanup(reset)
+}
+
 func init() {
 	os.Setenv("RUN_IN_CLOUD", "NotSet")
 }
@@ -423,3 +458,534 @@ func TestGetScores(t *testing.T) {
 	// 	t.Errorf(`expect "*" but get: %v`, resp.Header.Get("Access-Control-Allow-Origin"))
 	// }
 }
+
+func TestQueryProtocols(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+
+	for _, tc := range []struct {
+		query  string
+		status int
+		count  int
+	}{
+		{"", http.StatusOK, 8},
+		{"?action=Delete", http.StatusOK, 2},
+		{"?heroID=23", http.StatusOK, 1},
+		{"?q=SEARCH", http.StatusOK, 2},
+		{"?action=List&page=1&size=1", http.StatusOK, 1},
+		{"?from=" + time.Now().Add(-time.Hour).UTC().Format(time.RFC3339), http.StatusOK, 4},
+		{"?heroID=x", http.StatusBadRequest, 0},
+		{"?to=yesterday", http.StatusBadRequest, 0},
+		{"?page=0", http.StatusBadRequest, 0},
+		{"?page=4611686018427387904&size=4", http.StatusBadRequest, 0},
+		{"?page=2&size=9223372036854775807", http.StatusOK, 0},
+	} {
+		resp, err := http.Get(server.URL + "/api/heroes/protocol" + tc.query)
+		if err != nil {
+			t.Fatalf("No err expected: %v", err)
+		}
+		protocols := []service.Protocol{}
+		json.NewDecoder(resp.Body).Decode(&protocols)
+		resp.Body.Close()
+		if resp.StatusCode != tc.status || len(protocols) != tc.count {
+			t.Errorf("%s: expect %v (%v), got: %v (%v)", tc.query, tc.status, tc.count, resp.StatusCode, len(protocols))
+		}
+	}
+
+	resp, err := http.Get(server.URL + "/api/v2/heroes/protocol/count?action=List&page=1&size=1")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	count := protocolCount{}
+	json.NewDecoder(resp.Body).Decode(&count)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || count.Count != 2 {
+		t.Errorf("expect the count 2 without paging, got: %v (%v)", resp.StatusCode, count)
+	}
+
+	// without page and size only the first page
+	q, err := protocolQuery(httptest.NewRequest("GET", "/api/heroes/protocol", nil))
+	if err != nil || q.Offset != 0 || q.Limit != DefaultPageSize {
+		t.Errorf("expect the first page with the DefaultPageSize, got: %v (%v)", q, err)
+	}
+}
+
+func TestResetHeroes(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+	_, _ = app.Add(context.TODO(), "Test")
+
+	// without HEROES_ADMIN_RESET the route does not exist
+	resp, err := http.Post(fmt.Sprintf("%s/api/admin/reset", server.URL), "", nil)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status not found (404) without the reset, but is: %v", resp.StatusCode)
+	}
+
+	app.adminReset = true
+	resetServer := httptest.NewServer(handler())
+	app.adminReset = false
+	defer resetServer.Close()
+
+	resp, err = http.Post(fmt.Sprintf("%s/api/admin/reset", resetServer.URL), "", nil)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status ok (200), but is: %v (%v)", resp.StatusCode, string(body))
+	}
+
+	heroes := make([]service.Hero, 0)
+	err = json.Unmarshal(body, &heroes)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if len(heroes) != 7 {
+		t.Errorf("expect 7 heroes after reset, but %v", len(heroes))
+	}
+}
+
+func TestTrashAndRestoreHero(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+	_, _ = app.Delete(context.TODO(), 4)
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/heroes/trash", server.URL))
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status ok (200), but is: %v (%v)", resp.StatusCode, string(body))
+	}
+
+	trash := make([]service.DeletedHero, 0)
+	err = json.Unmarshal(body, &trash)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if len(trash) != 1 || trash[0].ID != 4 {
+		t.Errorf("expect Hero with ID 4 in trash, but %v", trash)
+	}
+
+	resp, err = http.Post(fmt.Sprintf("%s/api/heroes/4/restore", server.URL), "", nil)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	body, _ = ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status ok (200), but is: %v (%v)", resp.StatusCode, string(body))
+	}
+
+	hero, err := app.GetByID(context.TODO(), 4)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if hero.Name != "Adam O" {
+		t.Errorf("expect Adam O, got: %v", hero.Name)
+	}
+}
+
+func TestHistoryAndRevertHero(t *testing.T) {
+	app.ProtocolHeroService = history.NewHeroService(db.NewMemService())
+	_, _ = app.Update(context.TODO(), service.Hero{ID: 5, Name: "Test"})
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/heroes/5/history", server.URL))
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	body, _ := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status ok (200), but is: %v (%v)", resp.StatusCode, string(body))
+	}
+
+	revs := make([]service.Revision, 0)
+	err = json.Unmarshal(body, &revs)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if len(revs) != 2 {
+		t.Errorf("expect 2 revisions, but %v", len(revs))
+	}
+
+	resp, err = http.Post(fmt.Sprintf("%s/api/heroes/5/revert?rev=1", server.URL), "", nil)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	body, _ = ioutil.ReadAll(resp.Body)
+	if !strings.Contains(string(body), "Shauna C") {
+		t.Errorf("expect: Shauna C in body, got: %v", string(body))
+	}
+}
+
+func TestSwitchHeroOutOfRange(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+
+	req, err := http.NewRequest("PUT",
+		fmt.Sprintf("%s/api/heroes?pos=8", server.URL),
+		strings.NewReader(` { "name" : "Jasmin", "id" : 1} `))
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected status bad request (400), but is: %v", resp.StatusCode)
+	}
+}
+
+func TestTenantIsolation(t *testing.T) {
+	app.ProtocolHeroService = tenant.NewHeroService(func() service.ProtocolHeroService { return db.NewMemService() })
+	app.tenantResolver = tenant.Header(tenant.HeaderTenant)
+	defer func() { app.tenantResolver = nil }()
+
+	tenantServer := httptest.NewServer(handler())
+	defer tenantServer.Close()
+
+	get := func(tn string) *http.Response {
+		req, _ := http.NewRequest("GET", fmt.Sprintf("%s/api/heroes/1", tenantServer.URL), nil)
+		req.Header.Set(tenant.HeaderTenant, tn)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+		}
+		return resp
+	}
+
+	req, _ := http.NewRequest("DELETE", fmt.Sprintf("%s/api/heroes/1", tenantServer.URL), nil)
+	req.Header.Set(tenant.HeaderTenant, "club")
+	_, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+
+	if resp := get("club"); resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expected deleted hero (400) for club, but is: %v", resp.StatusCode)
+	}
+	if resp := get("gym"); resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status ok (200) for gym, but is: %v", resp.StatusCode)
+	}
+	if resp := get(""); resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status unauthorized (401) without tenant, but is: %v", resp.StatusCode)
+	}
+
+	// the info page needs no tenant
+	resp, err := http.Get(fmt.Sprintf("%s/info", tenantServer.URL))
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status ok (200) for info, but is: %v", resp.StatusCode)
+	}
+}
+
+func TestAuthForWriteRoutes(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+	app.authenticator, app.public = auth.APIKeys{"secret": auth.Principal{Name: "mario"}}, auth.IsRead
+	defer func() { app.authenticator, app.public = nil, nil }()
+
+	authServer := httptest.NewServer(handler())
+	defer authServer.Close()
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/heroes", authServer.URL))
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status ok (200) for public read, but is: %v", resp.StatusCode)
+	}
+
+	resp, err = http.Post(fmt.Sprintf("%s/api/heroes", authServer.URL), "", strings.NewReader("Test"))
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if resp.StatusCode != http.StatusUnauthorized {
+		t.Errorf("expected status unauthorized (401), but is: %v", resp.StatusCode)
+	}
+
+	req, _ := http.NewRequest("POST", fmt.Sprintf("%s/api/heroes", authServer.URL), strings.NewReader("Test"))
+	req.Header.Set("Authorization", "ApiKey secret")
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status ok (200) with API key, but is: %v", resp.StatusCode)
+	}
+}
+
+func TestPolicyForRoutes(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+	app.authenticator = auth.APIKeys{
+		"editor": auth.Principal{Name: "mario", Roles: []string{policy.Editor}},
+		"admin":  auth.Principal{Name: "jasmin", Roles: []string{policy.Admin}},
+	}
+	app.public = auth.IsRead
+	app.policy = policy.New(policy.DefaultRoles())
+	for route, perm := range routePermissions {
+		app.policy.Require(route, perm)
+	}
+	defer func() { app.authenticator, app.public, app.policy = nil, nil, nil }()
+
+	policyServer := httptest.NewServer(handler())
+	defer policyServer.Close()
+
+	do := func(method, url, key string) int {
+		req, _ := http.NewRequest(method, policyServer.URL+url, nil)
+		if key != "" {
+			req.Header.Set("Authorization", "ApiKey "+key)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+			return 0
+		}
+		return resp.StatusCode
+	}
+
+	for _, tc := range []struct {
+		method, url, key string
+		status           int
+	}{
+		{"GET", "/api/heroes", "", http.StatusOK},
+		{"GET", "/api/heroes/protocol", "", http.StatusUnauthorized},
+		{"GET", "/api/heroes/protocol", "editor", http.StatusForbidden},
+		{"DELETE", "/api/heroes/3", "editor", http.StatusForbidden},
+		{"DELETE", "/api/heroes/3", "admin", http.StatusOK},
+		{"GET", "/worker/protocol", "editor", http.StatusForbidden},
+	} {
+		if status := do(tc.method, tc.url, tc.key); status != tc.status {
+			t.Errorf("%v != %v for: %v %v with key: %v", tc.status, status, tc.method, tc.url, tc.key)
+		}
+	}
+}
+
+// without authentication the requests have the role viewer
+func TestPolicyWithoutAuth(t *testing.T) {
+	app.policy = NewApp().policy
+	defer func() { app.policy = nil }()
+
+	policyServer := httptest.NewServer(handler())
+	defer policyServer.Close()
+
+	for _, tc := range []struct {
+		method, url string
+		status      int
+	}{
+		{"GET", "/api/heroes", http.StatusOK},
+		{"POST", "/api/heroes", http.StatusUnauthorized},
+		{"DELETE", "/api/heroes/3", http.StatusUnauthorized},
+		{"GET", "/api/heroes/protocol", http.StatusUnauthorized},
+		{"GET", "/api/webhooks", http.StatusUnauthorized},
+	} {
+		req, _ := http.NewRequest(tc.method, policyServer.URL+tc.url, strings.NewReader("Alex"))
+		req.Header.Set("Content-Type", "text/plain")
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("No err expected: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%v != %v for: %v %v", tc.status, resp.StatusCode, tc.method, tc.url)
+		}
+	}
+}
+
+func TestWorkerGuard(t *testing.T) {
+	guard := app.workerGuard
+	app.workerGuard = &cron.Guard{Secret: "s3cret"}
+	app.authenticator = auth.APIKeys{"admin": auth.Principal{Name: "jasmin", Roles: []string{policy.Admin}}}
+	app.public = auth.IsRead
+	app.policy = policy.New(policy.DefaultRoles())
+	for route, perm := range routePermissions {
+		app.policy.Require(route, perm)
+	}
+	defer func() { app.workerGuard, app.authenticator, app.public, app.policy = guard, nil, nil, nil }()
+
+	workerServer := httptest.NewServer(handler())
+	defer workerServer.Close()
+
+	for _, tc := range []struct {
+		header, value string
+		status        int
+	}{
+		{cron.HeaderSecret, "s3cret", http.StatusOK},
+		{cron.HeaderSecret, "wrong", http.StatusForbidden},
+		{"Authorization", "ApiKey admin", http.StatusForbidden},
+		{cron.HeaderCron, "true", http.StatusForbidden},
+	} {
+		req, _ := http.NewRequest("GET", workerServer.URL+"/worker/protocol", nil)
+		req.Header.Set(tc.header, tc.value)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+			continue
+		}
+		if resp.StatusCode != tc.status {
+			t.Errorf("%v != %v for header: %v", tc.status, resp.StatusCode, tc.header)
+		}
+	}
+
+	if app.WorkerRejected() != 3 {
+		t.Errorf("3 != %v", app.WorkerRejected())
+	}
+}
+
+func TestOptionsUnknownRoute(t *testing.T) {
+	req, _ := http.NewRequest("OPTIONS", fmt.Sprintf("%s/api/unknown", server.URL), nil)
+	req.Header.Set("Origin", "https://heroes.com")
+	req.Header.Set("Access-Control-Request-Method", "GET")
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+		return
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expect status not found (404), but is: %v", resp.StatusCode)
+	}
+}
+
+func TestRateLimit(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+	app.limiter = &ratelimit.Limiter{
+		Store:  ratelimit.NewMemStore(),
+		Limits: map[string]ratelimit.Limit{"write": {Requests: 1, Per: time.Hour}, "scores": {Requests: 1, Per: time.Hour}},
+		Class:  rateClass,
+	}
+	defer func() { app.limiter = nil }()
+
+	limitServer := httptest.NewServer(handler())
+	defer limitServer.Close()
+
+	for _, tc := range []struct {
+		method, url string
+		status      int
+	}{
+		{"POST", "/api/heroes", http.StatusOK},
+		{"POST", "/api/heroes", http.StatusTooManyRequests},
+		{"GET", "/api/heroes", http.StatusOK},
+		{"GET", "/api/heroes", http.StatusOK},
+		{"GET", "/api/heroes/scores", http.StatusTooManyRequests},
+	} {
+		// the first scores request is the allowed one (without calling 8a.nu)
+		if tc.url == "/api/heroes/scores" {
+			app.limiter.Store.Take(context.Background(), "scores|ip:127.0.0.1", app.limiter.Limits["scores"])
+		}
+
+		req, _ := http.NewRequest(tc.method, limitServer.URL+tc.url, strings.NewReader("Alex"))
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+			continue
+		}
+		if resp.StatusCode != tc.status {
+			t.Errorf("%v != %v for: %v %v", tc.status, resp.StatusCode, tc.method, tc.url)
+		}
+		if tc.status == http.StatusTooManyRequests && resp.Header.Get("Retry-After") == "" {
+			t.Errorf("expect Retry-After header")
+		}
+	}
+}
+
+func TestRequestBodyChecks(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+
+	for _, tc := range []struct {
+		method, url, contentType, body string
+		status                         int
+	}{
+		{"POST", "/api/heroes", "text/plain", "Alex", http.StatusOK},
+		{"POST", "/api/heroes", "text/plain", strings.Repeat("Alex", 1000), http.StatusRequestEntityTooLarge},
+		{"POST", "/api/heroes", "application/xml", "<name>Alex</name>", http.StatusUnsupportedMediaType},
+		{"PUT", "/api/heroes", "application/json", `{"id": 1, "name": "Jasmin", "power": 9}`, http.StatusBadRequest},
+		{"PUT", "/api/heroes", "application/json", `{"id": 1, "name": "Jasmin"} {"id": 2}`, http.StatusBadRequest},
+		{"PUT", "/api/heroes?pos=2", "text/plain", `{"id": 1, "name": "Jasmin"}`, http.StatusUnsupportedMediaType},
+		{"PUT", "/api/teams", "application/json", `{"id": 1, "name": "Team", "heroes": []}`, http.StatusBadRequest},
+	} {
+		req, _ := http.NewRequest(tc.method, server.URL+tc.url, strings.NewReader(tc.body))
+		req.Header.Set("Content-Type", tc.contentType)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+			continue
+		}
+		if resp.StatusCode != tc.status {
+			t.Errorf("%v != %v for: %v %v %v", tc.status, resp.StatusCode, tc.method, tc.url, tc.contentType)
+		}
+	}
+}
+
+func TestContentNegotiation(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+
+	for _, tc := range []struct {
+		url, accept, contentType, contains string
+		status                             int
+	}{
+		{"/api/heroes/1", "", "application/json; charset=utf-8", `"name":"Jasmin"`, http.StatusOK},
+		{"/api/heroes/1", "application/xml", "application/xml; charset=utf-8", "<name>Jasmin</name>", http.StatusOK},
+		{"/api/heroes", "application/x-yaml", "application/x-yaml; charset=utf-8", "name: Jasmin", http.StatusOK},
+		{"/api/heroes/1", "application/msgpack", "application/msgpack", "\xa4name\xa6Jasmin", http.StatusOK},
+		{"/api/heroes/1", "text/html", "text/plain; charset=utf-8", "not acceptable", http.StatusNotAcceptable},
+	} {
+		req, _ := http.NewRequest("GET", server.URL+tc.url, nil)
+		req.Header.Set("Accept", tc.accept)
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+			continue
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+
+		if resp.StatusCode != tc.status || resp.Header.Get("Content-Type") != tc.contentType {
+			t.Errorf("expect %v %v for: %v, got: %v %v", tc.status, tc.contentType, tc.accept, resp.StatusCode, resp.Header.Get("Content-Type"))
+		}
+		if !strings.Contains(string(body), tc.contains) {
+			t.Errorf("expect %q in body for: %v, got: %q", tc.contains, tc.accept, string(body))
+		}
+	}
+}
+
+func TestHeroListPagination(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+
+	for _, tc := range []struct {
+		query  string
+		count  int
+		status int
+	}{
+		{"", 7, http.StatusOK},
+		{"?page=3&size=3", 1, http.StatusOK},
+		{"?page=4&size=3", 0, http.StatusOK},
+		{"?size=2", 2, http.StatusOK},
+		{"?page=0", 0, http.StatusBadRequest},
+		{"?size=x", 0, http.StatusBadRequest},
+		{"?page=5&size=3", 0, http.StatusBadRequest},
+		{"?page=4611686018427387904&size=4", 0, http.StatusBadRequest},
+		{"?page=3&size=9223372036854775807", 0, http.StatusBadRequest},
+		{"?page=1&size=9223372036854775807", 7, http.StatusOK},
+	} {
+		resp, err := http.Get(server.URL + "/api/heroes" + tc.query)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+			continue
+		}
+		if resp.StatusCode != tc.status {
+			t.Errorf("%v != %v for: %v", tc.status, resp.StatusCode, tc.query)
+			continue
+		}
+		if tc.status != http.StatusOK {
+			continue
+		}
+		heroes := []service.Hero{}
+		json.NewDecoder(resp.Body).Decode(&heroes)
+		if len(heroes) != tc.count {
+			t.Errorf("%v != %v for: %v", tc.count, len(heroes), tc.query)
+		}
+	}
+}
@@ -2,7 +2,9 @@ package service
 
 import (
 	"fmt"
+	"sort"
 	"strconv"
+	"strings"
 	"time"
 )
 
@@ -16,6 +18,7 @@ type Protocol struct {
 	HeroID int64     `json:"heroid"`
 	Note   string    `json:"note"`
 	Time   time.Time `json:"time"`
+	Tenant string    `json:"tenant,omitempty"`
 }
 
 // GetTimeString convert Time in the right format (const: dateFormat)
@@ -45,6 +48,7 @@ func Protocol2Map(p Protocol) map[string]string {
 		"HeroID": strconv.Itoa(int(p.HeroID)),
 		"Note":   p.Note,
 		"Time":   p.GetTimeString(),
+		"Tenant": p.Tenant,
 	}
 }
 
@@ -60,5 +64,68 @@ func Map2Protocol(m map[string]string) Protocol {
 		HeroID: int64(id),
 		Note:   m["Note"],
 		Time:   t,
+		Tenant: m["Tenant"],
 	}
 }
+
+// ProtocolQuery filter and page the Protocols, the zero values are not filtered
+type ProtocolQuery struct {
+	Action string
+	HeroID int64
+	// From (inclusive) and To (exclusive) of the Time
+	From, To time.Time
+	// Text is searched in the Note (case insensitive)
+	Text string
+	// Offset and Limit of the page (Limit 0: all Protocols after the Offset)
+	Offset, Limit int
+}
+
+// Match returns true, if the Protocol match the filter of the query (without Offset and Limit)
+func (q ProtocolQuery) Match(p Protocol) bool {
+	if q.Action != "" && q.Action != p.Action {
+		return false
+	}
+	if q.HeroID != 0 && q.HeroID != p.HeroID {
+		return false
+	}
+	if !q.From.IsZero() && p.Time.Before(q.From) {
+		return false
+	}
+	if !q.To.IsZero() && !p.Time.Before(q.To) {
+		return false
+	}
+	return q.Text == "" || strings.Contains(strings.ToUpper(p.Note), strings.ToUpper(q.Text))
+}
+
+// QueryProtocols returns the page of the matched Protocols (newest first), for the services without a query language
+func QueryProtocols(protocols []Protocol, q ProtocolQuery) []Protocol {
+	result := make([]Protocol, 0)
+	for _, p := range protocols {
+		if q.Match(p) {
+			result = append(result, p)
+		}
+	}
+	sort.SliceStable(result, func(i, j int) bool { return result[i].Time.After(result[j].Time) })
+
+	if q.Offset < 0 {
+		q.Offset = 0
+	} else if q.Offset > len(result) {
+		q.Offset = len(result)
+	}
+	result = result[q.Offset:]
+	if q.Limit > 0 && q.Limit < len(result) {
+		result = result[:q.Limit]
+	}
+	return result
+}
+
+// CountProtocols returns the number of the matched Protocols (without Offset and Limit)
+func CountProtocols(protocols []Protocol, q ProtocolQuery) int {
+	count := 0
+	for _, p := range protocols {
+		if q.Match(p) {
+			count++
+		}
+	}
+	return count
+}
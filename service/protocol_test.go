@@ -2,6 +2,7 @@ package service
 
 import (
 	"testing"
+	"time"
 )
 
 var (
@@ -38,3 +39,47 @@ func TestMap2Protocol(t *testing.T) {
 		t.Errorf("%v != %v", p.GetTimeString(), protocol.GetTimeString())
 	}
 }
+
+func TestQueryProtocols(t *testing.T) {
+	now := time.Now()
+	protocols := []Protocol{
+		{Action: "Add", HeroID: 1, Note: "add Jasmin", Time: now.Add(-3 * time.Hour)},
+		{Action: "Delete", HeroID: 1, Note: "delete Jasmin", Time: now},
+		{Action: "Add", HeroID: 2, Note: "add Alex", Time: now.Add(-time.Hour)},
+		{Action: "Update", HeroID: 2, Note: "update Alex", Time: now.Add(-2 * time.Hour)},
+	}
+
+	for _, tc := range []struct {
+		q     ProtocolQuery
+		notes []string
+		count int
+	}{
+		{ProtocolQuery{}, []string{"delete Jasmin", "add Alex", "update Alex", "add Jasmin"}, 4},
+		{ProtocolQuery{Action: "Add"}, []string{"add Alex", "add Jasmin"}, 2},
+		{ProtocolQuery{HeroID: 2}, []string{"add Alex", "update Alex"}, 2},
+		{ProtocolQuery{Text: "JASMIN"}, []string{"delete Jasmin", "add Jasmin"}, 2},
+		{ProtocolQuery{From: now.Add(-2 * time.Hour), To: now}, []string{"add Alex", "update Alex"}, 2},
+		{ProtocolQuery{Offset: 1, Limit: 2}, []string{"add Alex", "update Alex"}, 4},
+		{ProtocolQuery{Action: "Add", Offset: 5}, []string{}, 2},
+		{ProtocolQuery{Offset: -1, Limit: 1}, []string{"delete Jasmin"}, 4},
+	} {
+		result := QueryProtocols(protocols, tc.q)
+		notes := []string{}
+		for _, p := range result {
+			notes = append(notes, p.Note)
+		}
+		if len(notes) != len(tc.notes) {
+			t.Errorf("%v: expect %v, got: %v", tc.q, tc.notes, notes)
+			continue
+		}
+		for i := range notes {
+			if notes[i] != tc.notes[i] {
+				t.Errorf("%v: expect %v, got: %v", tc.q, tc.notes, notes)
+				break
+			}
+		}
+		if count := CountProtocols(protocols, tc.q); count != tc.count {
+			t.Errorf("%v: expect count %v, got: %v", tc.q, tc.count, count)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"errors"
 	"os"
 	"strconv"
+	"time"
 )
 
 var (
@@ -15,6 +16,12 @@ var (
 	ErrPosNotFound = errors.New("Out of Range")
 	// ErrNoContent if reading 8a.nu returns empty string
 	ErrNoContent = errors.New("No content found on 8a.nu")
+	// ErrRevisionNotFound if no Revision was found
+	ErrRevisionNotFound = errors.New("Revision not Found")
+	// ErrTeamNotFound if no Team was found
+	ErrTeamNotFound = errors.New("Team not Found")
+	// ErrHeroInTeam if the Hero is already a member of the Team
+	ErrHeroInTeam = errors.New("Hero is already in the Team")
 )
 
 // Hero the struct
@@ -31,6 +38,39 @@ type ScoreData struct {
 	Country string
 }
 
+// Team is a named List of Heroes, with an own order
+type Team struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+// DeletedHero is a Hero in the trash
+type DeletedHero struct {
+	Hero
+	// Pos is the position of the Hero before deleting
+	Pos       int64     `json:"pos"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+// Revision is an immutable change of a Hero
+type Revision struct {
+	Rev    int       `json:"rev"`
+	HeroID int64     `json:"heroid"`
+	Action string    `json:"action"`
+	Who    string    `json:"who"`
+	Time   time.Time `json:"time"`
+	Diff   []Change  `json:"diff"`
+	// Hero is the state after the change (nil after Delete)
+	Hero *Hero `json:"hero,omitempty"`
+}
+
+// Change of one Hero field
+type Change struct {
+	Field string `json:"field"`
+	Old   string `json:"old"`
+	New   string `json:"new"`
+}
+
 // HeroService access to Heroes methods
 type HeroService interface {
 	List(c context.Context, name string) ([]Hero, error)
@@ -44,6 +84,10 @@ type HeroService interface {
 // ProtocolService acces to the Protocols
 type ProtocolService interface {
 	Protocols(c context.Context) ([]Protocol, error)
+	// QueryProtocols returns the page of the Protocols (newest first), which match the query
+	QueryProtocols(c context.Context, q ProtocolQuery) ([]Protocol, error)
+	// CountProtocols returns the number of the Protocols, which match the query (without Offset and Limit)
+	CountProtocols(c context.Context, q ProtocolQuery) (int, error)
 }
 
 // ProtocolHeroService combine Hero and ProtocolService
@@ -52,11 +96,85 @@ type ProtocolHeroService interface {
 	ProtocolService
 }
 
+// ResetService restore the initial (seed) Heroes, e.g. between end-to-end test runs
+type ResetService interface {
+	Reset(c context.Context) error
+}
+
+// TrashService access to the deleted Heroes
+type TrashService interface {
+	Trash(c context.Context) ([]DeletedHero, error)
+	Restore(c context.Context, id int64) (*Hero, error)
+	Purge(c context.Context, before time.Time) (int, error)
+}
+
+// HistoryService access to the Revisions of a Hero
+type HistoryService interface {
+	History(c context.Context, id int64) ([]Revision, error)
+	Revert(c context.Context, id int64, rev int) (*Hero, error)
+}
+
+// TeamService access to Teams and the Heroes of a Team
+type TeamService interface {
+	Teams(c context.Context) ([]Team, error)
+	GetTeam(c context.Context, id int64) (*Team, error)
+	AddTeam(c context.Context, n string) (*Team, error)
+	UpdateTeam(c context.Context, t Team) (*Team, error)
+	DeleteTeam(c context.Context, id int64) (*Team, error)
+
+	TeamHeroes(c context.Context, teamID int64) ([]Hero, error)
+	AddTeamHero(c context.Context, teamID, heroID int64) (*Hero, error)
+	UpdateTeamHeroPosition(c context.Context, teamID, heroID, pos int64) (*Hero, error)
+	RemoveTeamHero(c context.Context, teamID, heroID int64) (*Hero, error)
+}
+
+// Publisher is notified about the changes of the Heroes: the Protocol and the Hero after the change
+type Publisher interface {
+	Publish(c context.Context, p Protocol, h *Hero)
+}
+
+// Publishers notify all Publishers
+type Publishers []Publisher
+
+// Publish impl from Publisher
+func (ps Publishers) Publish(c context.Context, p Protocol, h *Hero) {
+	for _, pub := range ps {
+		pub.Publish(c, p, h)
+	}
+}
+
 // ScoreService get Score from Hero-List from 8a.nu
 type ScoreService interface {
 	Scores(c context.Context, svc HeroService) (map[int64]int, error)
 }
 
+type userKey struct{}
+type tenantKey struct{}
+
+// WithUser returns a new Context, which carries the name of the current user
+func WithUser(c context.Context, user string) context.Context {
+	return context.WithValue(c, userKey{}, user)
+}
+
+// UserFromContext returns the name of the current user or "anonymous"
+func UserFromContext(c context.Context) string {
+	if user, ok := c.Value(userKey{}).(string); ok && user != "" {
+		return user
+	}
+	return "anonymous"
+}
+
+// WithTenant returns a new Context, which carries the tenant of the current request
+func WithTenant(c context.Context, tenant string) context.Context {
+	return context.WithValue(c, tenantKey{}, tenant)
+}
+
+// TenantFromContext returns the tenant of the current request or "" (the default tenant)
+func TenantFromContext(c context.Context) string {
+	tenant, _ := c.Value(tenantKey{}).(string)
+	return tenant
+}
+
 // RunInCloud check Env: RUN_IN_CLOUD is set tue true
 func RunInCloud() bool {
 	inCloud, _ := strconv.ParseBool(os.Getenv("RUN_IN_CLOUD"))
@@ -0,0 +1,203 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/lima1909/goheroes-appengine/body"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// register all Team Handler
+func teamRoutes(router *mux.Router) {
+	url := apiPrefix + "/teams"
+	router.HandleFunc(url, teamList).Methods("GET").Name("teams.list")
+	router.HandleFunc(url, addTeam).Methods("POST").Name("teams.add")
+	router.HandleFunc(url, updateTeam).Methods("PUT").Name("teams.update")
+
+	urlWithID := apiPrefix + "/teams/{teamID:[0-9]+}"
+	router.HandleFunc(urlWithID, getTeam).Methods("GET").Name("team.get")
+	router.HandleFunc(urlWithID, deleteTeam).Methods("DELETE").Name("team.delete")
+
+	router.HandleFunc(urlWithID+"/heroes", teamHeroList).Methods("GET").Name("team.heroes")
+	router.HandleFunc(urlWithID+"/heroes/{id:[0-9]+}", switchTeamHero).Methods("PUT").Queries("pos", "{pos}").Name("team.hero.move")
+	router.HandleFunc(urlWithID+"/heroes/{id:[0-9]+}", addTeamHero).Methods("PUT").Name("team.hero.add")
+	router.HandleFunc(urlWithID+"/heroes/{id:[0-9]+}", removeTeamHero).Methods("DELETE").Name("team.hero.remove")
+}
+
+func teamList(w http.ResponseWriter, r *http.Request) {
+	teams, err := app.Teams(newContext(r))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeToClient(w, r, teams)
+}
+
+func addTeam(w http.ResponseWriter, r *http.Request) {
+	b, err := body.ReadAll(r)
+	if err != nil {
+		http.Error(w, err.Error(), body.Status(err))
+		return
+	}
+
+	t, err := app.AddTeam(newContext(r), string(b))
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	writeToClient(w, r, t)
+}
+
+func updateTeam(w http.ResponseWriter, r *http.Request) {
+	team := service.Team{}
+	err := body.DecodeJSON(r, &team)
+	if err != nil {
+		http.Error(w, err.Error(), body.Status(err))
+		return
+	}
+
+	t, err := app.UpdateTeam(newContext(r), team)
+	if err != nil {
+		http.Error(w, err.Error(), teamErrStatus(err))
+		return
+	}
+
+	writeToClient(w, r, t)
+}
+
+func getTeam(w http.ResponseWriter, r *http.Request) {
+	teamID, _, err := teamVars(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	t, err := app.GetTeam(newContext(r), teamID)
+	if err != nil {
+		http.Error(w, err.Error(), teamErrStatus(err))
+		return
+	}
+
+	writeToClient(w, r, t)
+}
+
+func deleteTeam(w http.ResponseWriter, r *http.Request) {
+	teamID, _, err := teamVars(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	t, err := app.DeleteTeam(newContext(r), teamID)
+	if err != nil {
+		http.Error(w, err.Error(), teamErrStatus(err))
+		return
+	}
+
+	writeToClient(w, r, t)
+}
+
+func teamHeroList(w http.ResponseWriter, r *http.Request) {
+	teamID, _, err := teamVars(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	heroes, err := app.TeamHeroes(newContext(r), teamID)
+	if err != nil {
+		http.Error(w, err.Error(), teamErrStatus(err))
+		return
+	}
+
+	writeToClient(w, r, heroes)
+}
+
+func addTeamHero(w http.ResponseWriter, r *http.Request) {
+	teamID, heroID, err := teamVars(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h, err := app.AddTeamHero(newContext(r), teamID, heroID)
+	if err != nil {
+		http.Error(w, err.Error(), teamErrStatus(err))
+		return
+	}
+
+	writeHeroToClient(w, r, h)
+}
+
+func switchTeamHero(w http.ResponseWriter, r *http.Request) {
+	teamID, heroID, err := teamVars(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	pos, err := strconv.Atoi(r.FormValue("pos"))
+	if err != nil {
+		http.Error(w, fmt.Sprintf("invalid pos: %v", r.FormValue("pos")), http.StatusBadRequest)
+		return
+	}
+
+	h, err := app.UpdateTeamHeroPosition(newContext(r), teamID, heroID, int64(pos))
+	if err != nil {
+		http.Error(w, err.Error(), teamErrStatus(err))
+		return
+	}
+
+	writeHeroToClient(w, r, h)
+}
+
+func removeTeamHero(w http.ResponseWriter, r *http.Request) {
+	teamID, heroID, err := teamVars(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	h, err := app.RemoveTeamHero(newContext(r), teamID, heroID)
+	if err != nil {
+		http.Error(w, err.Error(), teamErrStatus(err))
+		return
+	}
+
+	writeHeroToClient(w, r, h)
+}
+
+// teamVars returns the teamID and the (optional) Hero id from the URL
+func teamVars(r *http.Request) (int64, int64, error) {
+	vars := mux.Vars(r)
+
+	teamID, err := strconv.Atoi(vars["teamID"])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid teamID: %v", vars["teamID"])
+	}
+
+	if _, ok := vars["id"]; !ok {
+		return int64(teamID), 0, nil
+	}
+	id, err := strconv.Atoi(vars["id"])
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid id: %v", vars["id"])
+	}
+
+	return int64(teamID), int64(id), nil
+}
+
+func teamErrStatus(err error) int {
+	switch err {
+	case service.ErrTeamNotFound, service.ErrHeroNotFound:
+		return http.StatusNotFound
+	case service.ErrHeroInTeam, service.ErrPosNotFound:
+		return http.StatusBadRequest
+	}
+	return http.StatusInternalServerError
+}
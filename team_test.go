@@ -0,0 +1,78 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestTeamHeroes(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+	app.TeamService = db.NewTeamService(app.ProtocolHeroService)
+
+	resp, err := http.Post(fmt.Sprintf("%s/api/teams", server.URL), "", strings.NewReader("Boulder"))
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	team := service.Team{}
+	body, _ := ioutil.ReadAll(resp.Body)
+	err = json.Unmarshal(body, &team)
+	if err != nil {
+		t.Errorf("No err expected: %v (%v)", err, string(body))
+	}
+
+	for _, id := range []int{4, 2} {
+		req, _ := http.NewRequest("PUT", fmt.Sprintf("%s/api/teams/%d/heroes/%d", server.URL, team.ID, id), nil)
+		resp, err = http.DefaultClient.Do(req)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+		}
+		if resp.StatusCode != http.StatusOK {
+			t.Errorf("expected status ok (200), but is: %v", resp.StatusCode)
+		}
+	}
+
+	// move Hero 2 to the first position
+	req, _ := http.NewRequest("PUT", fmt.Sprintf("%s/api/teams/%d/heroes/2?pos=0", server.URL, team.ID), nil)
+	resp, err = http.DefaultClient.Do(req)
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		t.Errorf("expected status ok (200), but is: %v", resp.StatusCode)
+	}
+
+	resp, err = http.Get(fmt.Sprintf("%s/api/teams/%d/heroes", server.URL, team.ID))
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	heroes := make([]service.Hero, 0)
+	body, _ = ioutil.ReadAll(resp.Body)
+	err = json.Unmarshal(body, &heroes)
+	if err != nil {
+		t.Errorf("No err expected: %v (%v)", err, string(body))
+	}
+	if len(heroes) != 2 || heroes[0].ID != 2 || heroes[1].ID != 4 {
+		t.Errorf("expect Heroes 2 and 4, got: %v", heroes)
+	}
+}
+
+func TestTeamNotFound(t *testing.T) {
+	resetTestHeroes(t)
+
+	app.TeamService = db.NewTeamService(app.ProtocolHeroService)
+
+	resp, err := http.Get(fmt.Sprintf("%s/api/teams/99/heroes", server.URL))
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+	if resp.StatusCode != http.StatusNotFound {
+		t.Errorf("expected status not found (404), but is: %v", resp.StatusCode)
+	}
+}
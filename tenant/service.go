@@ -0,0 +1,223 @@
+package tenant
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// HeroService dispatch every call to the ProtocolHeroService of the tenant (from the context),
+// the services are created on the first call of a tenant
+type HeroService struct {
+	create func() service.ProtocolHeroService
+
+	mu       sync.Mutex
+	services map[string]service.ProtocolHeroService
+}
+
+// NewHeroService create a new instance, create is called once for every tenant
+func NewHeroService(create func() service.ProtocolHeroService) *HeroService {
+	return &HeroService{create: create, services: map[string]service.ProtocolHeroService{}}
+}
+
+// For returns the ProtocolHeroService of the tenant from the context
+func (hs *HeroService) For(c context.Context) service.ProtocolHeroService {
+	t := service.TenantFromContext(c)
+
+	hs.mu.Lock()
+	defer hs.mu.Unlock()
+
+	svc, ok := hs.services[t]
+	if !ok {
+		svc = hs.create()
+		hs.services[t] = svc
+	}
+	return svc
+}
+
+// Protocols delegate to the tenant ProtocolService
+func (hs *HeroService) Protocols(c context.Context) ([]service.Protocol, error) {
+	return hs.For(c).Protocols(c)
+}
+
+// QueryProtocols delegate to the tenant ProtocolService
+func (hs *HeroService) QueryProtocols(c context.Context, q service.ProtocolQuery) ([]service.Protocol, error) {
+	return hs.For(c).QueryProtocols(c, q)
+}
+
+// CountProtocols delegate to the tenant ProtocolService
+func (hs *HeroService) CountProtocols(c context.Context, q service.ProtocolQuery) (int, error) {
+	return hs.For(c).CountProtocols(c, q)
+}
+
+// List delegate to the tenant HeroService
+func (hs *HeroService) List(c context.Context, name string) ([]service.Hero, error) {
+	return hs.For(c).List(c, name)
+}
+
+// GetByID delegate to the tenant HeroService
+func (hs *HeroService) GetByID(c context.Context, id int64) (*service.Hero, error) {
+	return hs.For(c).GetByID(c, id)
+}
+
+// Add delegate to the tenant HeroService
+func (hs *HeroService) Add(c context.Context, n string) (*service.Hero, error) {
+	return hs.For(c).Add(c, n)
+}
+
+// Update delegate to the tenant HeroService
+func (hs *HeroService) Update(c context.Context, h service.Hero) (*service.Hero, error) {
+	return hs.For(c).Update(c, h)
+}
+
+// UpdatePosition delegate to the tenant HeroService
+func (hs *HeroService) UpdatePosition(c context.Context, h service.Hero, pos int64) (*service.Hero, error) {
+	return hs.For(c).UpdatePosition(c, h, pos)
+}
+
+// Delete delegate to the tenant HeroService
+func (hs *HeroService) Delete(c context.Context, id int64) (*service.Hero, error) {
+	return hs.For(c).Delete(c, id)
+}
+
+// Trash delegate to the tenant HeroService, if it is a TrashService
+func (hs *HeroService) Trash(c context.Context) ([]service.DeletedHero, error) {
+	ts, err := hs.trashService(c)
+	if err != nil {
+		return nil, err
+	}
+	return ts.Trash(c)
+}
+
+// Restore delegate to the tenant HeroService, if it is a TrashService
+func (hs *HeroService) Restore(c context.Context, id int64) (*service.Hero, error) {
+	ts, err := hs.trashService(c)
+	if err != nil {
+		return nil, err
+	}
+	return ts.Restore(c, id)
+}
+
+// Purge delegate to the tenant HeroService, if it is a TrashService
+func (hs *HeroService) Purge(c context.Context, before time.Time) (int, error) {
+	ts, err := hs.trashService(c)
+	if err != nil {
+		return 0, err
+	}
+	return ts.Purge(c, before)
+}
+
+// History delegate to the tenant HeroService, if it is a HistoryService
+func (hs *HeroService) History(c context.Context, id int64) ([]service.Revision, error) {
+	svc := hs.For(c)
+	h, ok := svc.(service.HistoryService)
+	if !ok {
+		return nil, fmt.Errorf("history is not supported by: %T", svc)
+	}
+	return h.History(c, id)
+}
+
+// Revert delegate to the tenant HeroService, if it is a HistoryService
+func (hs *HeroService) Revert(c context.Context, id int64, rev int) (*service.Hero, error) {
+	svc := hs.For(c)
+	h, ok := svc.(service.HistoryService)
+	if !ok {
+		return nil, fmt.Errorf("history is not supported by: %T", svc)
+	}
+	return h.Revert(c, id, rev)
+}
+
+// Reset delegate to the tenant HeroService, if it is a ResetService
+func (hs *HeroService) Reset(c context.Context) error {
+	svc := hs.For(c)
+	rs, ok := svc.(service.ResetService)
+	if !ok {
+		return fmt.Errorf("reset is not supported by: %T", svc)
+	}
+	return rs.Reset(c)
+}
+
+func (hs *HeroService) trashService(c context.Context) (service.TrashService, error) {
+	svc := hs.For(c)
+	ts, ok := svc.(service.TrashService)
+	if !ok {
+		return nil, fmt.Errorf("trash is not supported by: %T", svc)
+	}
+	return ts, nil
+}
+
+// TeamService dispatch every call to the TeamService of the tenant (from the context)
+type TeamService struct {
+	create func() service.TeamService
+
+	mu       sync.Mutex
+	services map[string]service.TeamService
+}
+
+// NewTeamService create a new instance, create is called once for every tenant
+func NewTeamService(create func() service.TeamService) *TeamService {
+	return &TeamService{create: create, services: map[string]service.TeamService{}}
+}
+
+// For returns the TeamService of the tenant from the context
+func (ts *TeamService) For(c context.Context) service.TeamService {
+	t := service.TenantFromContext(c)
+
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+
+	svc, ok := ts.services[t]
+	if !ok {
+		svc = ts.create()
+		ts.services[t] = svc
+	}
+	return svc
+}
+
+// Teams delegate to the tenant TeamService
+func (ts *TeamService) Teams(c context.Context) ([]service.Team, error) {
+	return ts.For(c).Teams(c)
+}
+
+// GetTeam delegate to the tenant TeamService
+func (ts *TeamService) GetTeam(c context.Context, id int64) (*service.Team, error) {
+	return ts.For(c).GetTeam(c, id)
+}
+
+// AddTeam delegate to the tenant TeamService
+func (ts *TeamService) AddTeam(c context.Context, n string) (*service.Team, error) {
+	return ts.For(c).AddTeam(c, n)
+}
+
+// UpdateTeam delegate to the tenant TeamService
+func (ts *TeamService) UpdateTeam(c context.Context, t service.Team) (*service.Team, error) {
+	return ts.For(c).UpdateTeam(c, t)
+}
+
+// DeleteTeam delegate to the tenant TeamService
+func (ts *TeamService) DeleteTeam(c context.Context, id int64) (*service.Team, error) {
+	return ts.For(c).DeleteTeam(c, id)
+}
+
+// TeamHeroes delegate to the tenant TeamService
+func (ts *TeamService) TeamHeroes(c context.Context, teamID int64) ([]service.Hero, error) {
+	return ts.For(c).TeamHeroes(c, teamID)
+}
+
+// AddTeamHero delegate to the tenant TeamService
+func (ts *TeamService) AddTeamHero(c context.Context, teamID, heroID int64) (*service.Hero, error) {
+	return ts.For(c).AddTeamHero(c, teamID, heroID)
+}
+
+// UpdateTeamHeroPosition delegate to the tenant TeamService
+func (ts *TeamService) UpdateTeamHeroPosition(c context.Context, teamID, heroID, pos int64) (*service.Hero, error) {
+	return ts.For(c).UpdateTeamHeroPosition(c, teamID, heroID, pos)
+}
+
+// RemoveTeamHero delegate to the tenant TeamService
+func (ts *TeamService) RemoveTeamHero(c context.Context, teamID, heroID int64) (*service.Hero, error) {
+	return ts.For(c).RemoveTeamHero(c, teamID, heroID)
+}
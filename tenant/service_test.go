@@ -0,0 +1,53 @@
+package tenant
+
+import (
+	"context"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestHeroServiceIsolation(t *testing.T) {
+	hs := NewHeroService(func() service.ProtocolHeroService { return db.NewMemService() })
+	c1 := service.WithTenant(context.TODO(), "club")
+	c2 := service.WithTenant(context.TODO(), "gym")
+
+	_, _ = hs.Delete(c1, 1)
+	_, _ = hs.Add(c1, "Test")
+
+	h1, _ := hs.List(c1, "")
+	h2, _ := hs.List(c2, "")
+	if len(h1) != 7 || len(h2) != 7 {
+		t.Errorf("expect 7 heroes for both tenants, got: %v and %v", len(h1), len(h2))
+	}
+
+	_, err := hs.GetByID(c2, 1)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	_, err = hs.GetByID(c1, 1)
+	if err != service.ErrHeroNotFound {
+		t.Errorf("expect ErrHeroNotFound, got: %v", err)
+	}
+
+	trash, _ := hs.Trash(c2)
+	if len(trash) != 0 {
+		t.Errorf("0 != %v", len(trash))
+	}
+}
+
+func TestTeamServiceIsolation(t *testing.T) {
+	hs := NewHeroService(func() service.ProtocolHeroService { return db.NewMemService() })
+	ts := NewTeamService(func() service.TeamService { return db.NewTeamService(hs) })
+	c1 := service.WithTenant(context.TODO(), "club")
+	c2 := service.WithTenant(context.TODO(), "gym")
+
+	_, _ = ts.AddTeam(c1, "Boulder")
+
+	t1, _ := ts.Teams(c1)
+	t2, _ := ts.Teams(c2)
+	if len(t1) != 1 || len(t2) != 0 {
+		t.Errorf("expect 1 and 0 teams, got: %v and %v", len(t1), len(t2))
+	}
+}
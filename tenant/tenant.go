@@ -0,0 +1,199 @@
+// Package tenant scope the Hero data by tenant: a http middleware resolve the tenant
+// of the request (by header, API key or subdomain) and put it in the context,
+// the HeroService and TeamService dispatch every call to the services of this tenant.
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+const (
+	// EnvMode is the Env-Variable for the tenant resolution: header, apikey or subdomain (empty: no tenants)
+	EnvMode = "HEROES_TENANT_MODE"
+	// EnvKeys is the Env-Variable with the API keys for the mode apikey: key1=tenant1,key2=tenant2
+	EnvKeys = "HEROES_TENANT_KEYS"
+	// EnvDomain is the Env-Variable with the base domain for the mode subdomain, e.g. heroes.example.com
+	EnvDomain = "HEROES_TENANT_DOMAIN"
+	// EnvTenants is the Env-Variable with the allowed tenants for the modes header and subdomain: tenant1,tenant2
+	// (every tenant gets his own services, so the tenants of the requests must be limited)
+	EnvTenants = "HEROES_TENANTS"
+
+	// HeaderTenant is the header with the tenant name (mode: header)
+	HeaderTenant = "X-Tenant"
+	// HeaderAPIKey is the header with the API key (mode: apikey)
+	HeaderAPIKey = "X-API-Key"
+)
+
+var (
+	// ErrNoTenant if the request contains no tenant
+	ErrNoTenant = errors.New("No tenant found")
+	// ErrInvalidTenant if the tenant name is not valid
+	ErrInvalidTenant = errors.New("Invalid tenant")
+
+	// the tenant is part of the datastore namespace
+	validTenant = regexp.MustCompile(`^[0-9A-Za-z._-]{1,63}$`)
+)
+
+// Resolver find the tenant of the request
+type Resolver func(r *http.Request) (string, error)
+
+// Header resolve the tenant from the header
+func Header(name string) Resolver {
+	return func(r *http.Request) (string, error) {
+		t := r.Header.Get(name)
+		if t == "" {
+			return "", ErrNoTenant
+		}
+		return t, nil
+	}
+}
+
+// APIKey resolve the tenant with the API key from the header, keys is a map from API key to tenant
+func APIKey(header string, keys map[string]string) Resolver {
+	return func(r *http.Request) (string, error) {
+		k := r.Header.Get(header)
+		if k == "" {
+			return "", ErrNoTenant
+		}
+		t, ok := keys[k]
+		if !ok {
+			return "", ErrInvalidTenant
+		}
+		return t, nil
+	}
+}
+
+// Subdomain resolve the tenant from the host: tenant.domain
+func Subdomain(domain string) Resolver {
+	suffix := "." + strings.ToLower(strings.Trim(domain, "."))
+	return func(r *http.Request) (string, error) {
+		host := r.Host
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		host = strings.ToLower(host)
+
+		if !strings.HasSuffix(host, suffix) {
+			return "", ErrNoTenant
+		}
+		return strings.TrimSuffix(host, suffix), nil
+	}
+}
+
+// Allowed resolve the tenant with the Resolver, only the tenants are accepted (other: ErrInvalidTenant)
+func Allowed(resolve Resolver, tenants map[string]bool) Resolver {
+	return func(r *http.Request) (string, error) {
+		t, err := resolve(r)
+		if err != nil {
+			return "", err
+		}
+		if !tenants[t] {
+			return "", ErrInvalidTenant
+		}
+		return t, nil
+	}
+}
+
+// ResolverFromEnv create the Resolver, which is configured by the Env-Variables (nil: no tenants)
+func ResolverFromEnv() (Resolver, error) {
+	switch mode := os.Getenv(EnvMode); mode {
+	case "":
+		return nil, nil
+	case "header":
+		tenants, err := ParseTenants(os.Getenv(EnvTenants))
+		if err != nil {
+			return nil, err
+		}
+		return Allowed(Header(HeaderTenant), tenants), nil
+	case "apikey":
+		keys, err := ParseKeys(os.Getenv(EnvKeys))
+		if err != nil {
+			return nil, err
+		}
+		return APIKey(HeaderAPIKey, keys), nil
+	case "subdomain":
+		domain := os.Getenv(EnvDomain)
+		if domain == "" {
+			return nil, fmt.Errorf("%s is required for the tenant mode subdomain", EnvDomain)
+		}
+		tenants, err := ParseTenants(os.Getenv(EnvTenants))
+		if err != nil {
+			return nil, err
+		}
+		return Allowed(Subdomain(domain), tenants), nil
+	default:
+		return nil, fmt.Errorf("invalid tenant mode: %s=%s", EnvMode, mode)
+	}
+}
+
+// ParseKeys parse the API keys in the format: key1=tenant1,key2=tenant2
+func ParseKeys(s string) (map[string]string, error) {
+	keys := map[string]string{}
+	for _, kv := range strings.Split(s, ",") {
+		if strings.TrimSpace(kv) == "" {
+			continue
+		}
+		p := strings.SplitN(kv, "=", 2)
+		if len(p) != 2 || strings.TrimSpace(p[0]) == "" || !validTenant.MatchString(strings.TrimSpace(p[1])) {
+			return nil, fmt.Errorf("invalid API key definition: %s", kv)
+		}
+		keys[strings.TrimSpace(p[0])] = strings.TrimSpace(p[1])
+	}
+	if len(keys) == 0 {
+		return nil, fmt.Errorf("no API keys defined in: %s", EnvKeys)
+	}
+	return keys, nil
+}
+
+// ParseTenants parse the allowed tenants in the format: tenant1,tenant2
+func ParseTenants(s string) (map[string]bool, error) {
+	tenants := map[string]bool{}
+	for _, t := range strings.Split(s, ",") {
+		t = strings.TrimSpace(t)
+		if t == "" {
+			continue
+		}
+		if !validTenant.MatchString(t) {
+			return nil, fmt.Errorf("invalid tenant: %s in: %s", t, EnvTenants)
+		}
+		tenants[t] = true
+	}
+	if len(tenants) == 0 {
+		return nil, fmt.Errorf("%s is required for the tenant modes header and subdomain", EnvTenants)
+	}
+	return tenants, nil
+}
+
+// Valid is true, if the tenant name can be used (as part of the datastore namespace)
+func Valid(t string) bool {
+	return validTenant.MatchString(t)
+}
+
+// Handler resolve the tenant and put it in the context of the request,
+// requests without valid tenant get: 401 (Unauthorized) or 400 (BadRequest)
+func Handler(h http.Handler, resolve Resolver) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t, err := resolve(r)
+		if err == ErrNoTenant {
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		} else if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if !Valid(t) {
+			http.Error(w, fmt.Sprintf("%v: %s", ErrInvalidTenant, t), http.StatusBadRequest)
+			return
+		}
+
+		h.ServeHTTP(w, r.WithContext(service.WithTenant(r.Context(), t)))
+	})
+}
@@ -0,0 +1,143 @@
+package tenant
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestHeader(t *testing.T) {
+	r := httptest.NewRequest("GET", "http://localhost:8080/api/heroes", nil)
+	_, err := Header(HeaderTenant)(r)
+	if err != ErrNoTenant {
+		t.Errorf("expect ErrNoTenant, got: %v", err)
+	}
+
+	r.Header.Set(HeaderTenant, "club")
+	tn, err := Header(HeaderTenant)(r)
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if tn != "club" {
+		t.Errorf("club != %v", tn)
+	}
+}
+
+func TestAPIKey(t *testing.T) {
+	resolve := APIKey(HeaderAPIKey, map[string]string{"secret": "club"})
+
+	r := httptest.NewRequest("GET", "http://localhost:8080/api/heroes", nil)
+	r.Header.Set(HeaderAPIKey, "secret")
+	tn, _ := resolve(r)
+	if tn != "club" {
+		t.Errorf("club != %v", tn)
+	}
+
+	r.Header.Set(HeaderAPIKey, "wrong")
+	_, err := resolve(r)
+	if err != ErrInvalidTenant {
+		t.Errorf("expect ErrInvalidTenant, got: %v", err)
+	}
+}
+
+func TestSubdomain(t *testing.T) {
+	resolve := Subdomain("heroes.example.com")
+
+	r := httptest.NewRequest("GET", "http://Club.heroes.example.com:8080/api/heroes", nil)
+	tn, _ := resolve(r)
+	if tn != "club" {
+		t.Errorf("club != %v", tn)
+	}
+
+	r = httptest.NewRequest("GET", "http://heroes.example.com/api/heroes", nil)
+	_, err := resolve(r)
+	if err != ErrNoTenant {
+		t.Errorf("expect ErrNoTenant, got: %v", err)
+	}
+}
+
+func TestParseKeys(t *testing.T) {
+	keys, err := ParseKeys("k1=club, k2=gym")
+	if err != nil {
+		t.Errorf("no err expected: %v", err)
+	}
+	if keys["k1"] != "club" || keys["k2"] != "gym" {
+		t.Errorf("unexpected keys: %v", keys)
+	}
+
+	for _, s := range []string{"", "k1", "k1=", "k1=no/valid"} {
+		if _, err := ParseKeys(s); err == nil {
+			t.Errorf("err expected for: %v", s)
+		}
+	}
+}
+
+func TestResolverFromEnv(t *testing.T) {
+	defer os.Unsetenv(EnvMode)
+
+	r, err := ResolverFromEnv()
+	if r != nil || err != nil {
+		t.Errorf("expect no Resolver and no err, got: %v", err)
+	}
+
+	os.Setenv(EnvMode, "subdomain")
+	_, err = ResolverFromEnv()
+	if err == nil {
+		t.Errorf("expect err without domain")
+	}
+
+	// the allowed tenants are required
+	os.Setenv(EnvMode, "header")
+	if _, err = ResolverFromEnv(); err == nil {
+		t.Errorf("expect err without %s", EnvTenants)
+	}
+	os.Setenv(EnvTenants, "club, gym")
+	defer os.Unsetenv(EnvTenants)
+	r, err = ResolverFromEnv()
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	for tn, expected := range map[string]error{"gym": nil, "random": ErrInvalidTenant} {
+		req := httptest.NewRequest("GET", "http://localhost:8080/api/heroes", nil)
+		req.Header.Set(HeaderTenant, tn)
+		if _, err := r(req); err != expected {
+			t.Errorf("%s: expect %v, got: %v", tn, expected, err)
+		}
+	}
+
+	os.Setenv(EnvMode, "unknown")
+	_, err = ResolverFromEnv()
+	if err == nil {
+		t.Errorf("expect err for unknown mode")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	h := Handler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(service.TenantFromContext(r.Context())))
+	}), Header(HeaderTenant))
+
+	for _, tc := range []struct {
+		tenant string
+		status int
+	}{
+		{"", http.StatusUnauthorized},
+		{"no valid/tenant", http.StatusBadRequest},
+		{"club", http.StatusOK},
+	} {
+		r := httptest.NewRequest("GET", "http://localhost:8080/api/heroes", nil)
+		r.Header.Set(HeaderTenant, tc.tenant)
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+
+		if w.Code != tc.status {
+			t.Errorf("%v != %v for tenant: %v", tc.status, w.Code, tc.tenant)
+		}
+		if tc.status == http.StatusOK && w.Body.String() != tc.tenant {
+			t.Errorf("%v != %v", tc.tenant, w.Body.String())
+		}
+	}
+}
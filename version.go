@@ -0,0 +1,140 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+	"github.com/lima1909/goheroes-appengine/events"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// the API versions
+const (
+	V1 = "v1"
+	V2 = "v2"
+)
+
+// EnvV1Sunset is the Env-Variable with the date (2006-01-02), after which the API v1 is removed
+const EnvV1Sunset = "HEROES_API_V1_SUNSET"
+
+// apiPrefix is the prefix of all API routes with the optional version: /api (v1), /api/v1 or /api/v2
+const apiPrefix = "/api{version:(?:/v[12])?}"
+
+func sunsetFromEnv() (time.Time, error) {
+	s := os.Getenv(EnvV1Sunset)
+	if s == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse("2006-01-02", s)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %s (expected: 2006-01-02)", EnvV1Sunset, s)
+	}
+	return t, nil
+}
+
+// apiVersion of the request, without version in the URL: V1
+func apiVersion(r *http.Request) string {
+	if v := strings.TrimPrefix(mux.Vars(r)["version"], "/"); v != "" {
+		return v
+	}
+	return V1
+}
+
+// deprecationHandler set the headers Deprecation, Sunset (if not zero) and the Link to the successor version
+// for all requests of the API v1
+func deprecationHandler(sunset time.Time) mux.MiddlewareFunc {
+	return func(h http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if _, ok := mux.Vars(r)["version"]; ok && apiVersion(r) == V1 {
+				path := strings.TrimPrefix(strings.TrimPrefix(r.URL.Path, "/api"), "/"+V1)
+				w.Header().Set("Deprecation", "true")
+				w.Header().Add("Link", fmt.Sprintf(`</api/%s%s>; rel="successor-version"`, V2, path))
+				if !sunset.IsZero() {
+					w.Header().Set("Sunset", sunset.UTC().Format(http.TimeFormat))
+				}
+			}
+			h.ServeHTTP(w, r)
+		})
+	}
+}
+
+// heroV2 is the Hero of the API v2 with the ScoreData
+type heroV2 struct {
+	ID        int64       `json:"id"`
+	Name      string      `json:"name"`
+	ScoreData scoreDataV2 `json:"scoreData"`
+}
+
+type scoreDataV2 struct {
+	Name    string `json:"name"`
+	City    string `json:"city"`
+	Country string `json:"country"`
+}
+
+type deletedHeroV2 struct {
+	heroV2
+	Pos       int64     `json:"pos"`
+	DeletedAt time.Time `json:"deletedAt"`
+}
+
+type revisionV2 struct {
+	service.Revision
+	Hero *heroV2 `json:"hero,omitempty"`
+}
+
+func newHeroV2(h service.Hero) heroV2 {
+	return heroV2{ID: h.ID, Name: h.Name, ScoreData: scoreDataV2(h.ScoreData)}
+}
+
+// Hero convert the heroV2 in a service.Hero
+func (h heroV2) Hero() service.Hero {
+	return service.Hero{ID: h.ID, Name: h.Name, ScoreData: service.ScoreData(h.ScoreData)}
+}
+
+// versioned convert the Heroes in v in the representation of the API version of the request
+func versioned(r *http.Request, v interface{}) interface{} {
+	if apiVersion(r) != V2 {
+		return v
+	}
+
+	switch t := v.(type) {
+	case *service.Hero:
+		if t != nil {
+			return newHeroV2(*t)
+		}
+	case []service.Hero:
+		heroes := make([]heroV2, len(t))
+		for i, h := range t {
+			heroes[i] = newHeroV2(h)
+		}
+		return heroes
+	case []service.DeletedHero:
+		deleted := make([]deletedHeroV2, len(t))
+		for i, d := range t {
+			deleted[i] = deletedHeroV2{heroV2: newHeroV2(d.Hero), Pos: d.Pos, DeletedAt: d.DeletedAt}
+		}
+		return deleted
+	case events.Event:
+		e := eventV2{Event: t}
+		if t.Hero != nil {
+			h := newHeroV2(*t.Hero)
+			e.Hero = &h
+		}
+		return e
+	case []service.Revision:
+		revs := make([]revisionV2, len(t))
+		for i, rev := range t {
+			revs[i] = revisionV2{Revision: rev}
+			if rev.Hero != nil {
+				h := newHeroV2(*rev.Hero)
+				revs[i].Hero = &h
+			}
+		}
+		return revs
+	}
+	return v
+}
@@ -0,0 +1,108 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+func TestVersionedHero(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+
+	for _, tc := range []struct {
+		url, expect string
+	}{
+		{"/api/heroes/1", `{"id":1,"name":"Jasmin"}`},
+		{"/api/v1/heroes/1", `{"id":1,"name":"Jasmin"}`},
+		{"/api/v2/heroes/1", `{"id":1,"name":"Jasmin","scoreData":{"name":"jasmin-roeper","city":"Nuremberg","country":"de"}}`},
+	} {
+		resp, err := http.Get(server.URL + tc.url)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+			continue
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		if string(body) != tc.expect {
+			t.Errorf("%v:\n%v\n!=\n%v", tc.url, tc.expect, string(body))
+		}
+	}
+}
+
+func TestVersionedHeroList(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+
+	resp, err := http.Get(server.URL + "/api/v2/heroes")
+	if err != nil {
+		t.Errorf("No err expected: %v", err)
+		return
+	}
+	heroes := []heroV2{}
+	json.NewDecoder(resp.Body).Decode(&heroes)
+	if len(heroes) != 7 || heroes[0].ScoreData.Name == "" {
+		t.Errorf("expect 7 Heroes with ScoreData: %v", heroes)
+	}
+}
+
+func TestUpdateHeroV2(t *testing.T) {
+	app.ProtocolHeroService = db.NewMemService()
+
+	body := `{"id": 1, "name": "Jasmin", "scoreData": {"name": "jasmin", "city": "Munich", "country": "DE"}}`
+	req, _ := http.NewRequest("PUT", server.URL+"/api/v2/heroes", strings.NewReader(body))
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Errorf("expect 200, got: %v %v", resp.StatusCode, err)
+		return
+	}
+
+	h, _ := app.GetByID(newContext(httptest.NewRequest("GET", "/", nil)), 1)
+	if h.ScoreData != (service.ScoreData{Name: "jasmin", City: "Munich", Country: "DE"}) {
+		t.Errorf("expect updated ScoreData: %v", h.ScoreData)
+	}
+
+	// v1 knows no scoreData
+	req, _ = http.NewRequest("PUT", server.URL+"/api/v1/heroes", strings.NewReader(body))
+	resp, _ = http.DefaultClient.Do(req)
+	if resp.StatusCode != http.StatusBadRequest {
+		t.Errorf("expect 400 for scoreData in v1, got: %v", resp.StatusCode)
+	}
+}
+
+func TestDeprecationHeaders(t *testing.T) {
+	sunset := app.v1Sunset
+	app.v1Sunset = time.Date(2019, 6, 30, 0, 0, 0, 0, time.UTC)
+	defer func() { app.v1Sunset = sunset }()
+
+	versionServer := httptest.NewServer(handler())
+	defer versionServer.Close()
+
+	for _, tc := range []struct {
+		url, link string
+	}{
+		{"/api/heroes/1", `</api/v2/heroes/1>; rel="successor-version"`},
+		{"/api/v1/teams", `</api/v2/teams>; rel="successor-version"`},
+		{"/api/v2/heroes/1", ""},
+	} {
+		resp, err := http.Get(versionServer.URL + tc.url)
+		if err != nil {
+			t.Errorf("No err expected: %v", err)
+			continue
+		}
+		if resp.Header.Get("Link") != tc.link {
+			t.Errorf("%v: %v != %v", tc.url, tc.link, resp.Header.Get("Link"))
+		}
+		deprecated := tc.link != ""
+		if (resp.Header.Get("Deprecation") == "true") != deprecated {
+			t.Errorf("%v: expect deprecation: %v", tc.url, deprecated)
+		}
+		if deprecated && resp.Header.Get("Sunset") != "Sun, 30 Jun 2019 00:00:00 GMT" {
+			t.Errorf("%v: unexpected Sunset: %v", tc.url, resp.Header.Get("Sunset"))
+		}
+	}
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+
+	"github.com/gorilla/mux"
+	"github.com/lima1909/goheroes-appengine/body"
+	"github.com/lima1909/goheroes-appengine/webhook"
+)
+
+// webhookBody is the request body to create or update a webhook.Subscription
+type webhookBody struct {
+	URL string `json:"url"`
+	// Secret for the signature (empty: generated by create, unchanged by update)
+	Secret string   `json:"secret,omitempty"`
+	Events []string `json:"events,omitempty"`
+}
+
+// SchemaName impl from openapi.Namer
+func (webhookBody) SchemaName() string { return "WebhookRequest" }
+
+// register all Webhook Handler
+func webhookRoutes(router *mux.Router) {
+	url := apiPrefix + "/webhooks"
+	router.HandleFunc(url, webhooksEnabled(webhookList)).Methods("GET").Name("webhooks.list")
+	router.HandleFunc(url, webhooksEnabled(addWebhook)).Methods("POST").Name("webhooks.add")
+
+	urlWithID := apiPrefix + "/webhooks/{webhookID:[0-9]+}"
+	router.HandleFunc(urlWithID, webhooksEnabled(getWebhook)).Methods("GET").Name("webhook.get")
+	router.HandleFunc(urlWithID, webhooksEnabled(updateWebhook)).Methods("PUT").Name("webhook.update")
+	router.HandleFunc(urlWithID, webhooksEnabled(deleteWebhook)).Methods("DELETE").Name("webhook.delete")
+
+	admin := apiPrefix + "/admin/webhooks"
+	router.HandleFunc(admin+"/deliveries", webhooksEnabled(webhookDeliveries)).Methods("GET").Name("admin.webhooks.deliveries")
+	router.HandleFunc(admin+"/dead-letters", webhooksEnabled(webhookDeadLetters)).Methods("GET").Name("admin.webhooks.deadletters")
+	router.HandleFunc(admin+"/dead-letters/{deliveryID:[0-9]+}", webhooksEnabled(redeliverWebhook)).Methods("POST").Name("admin.webhooks.redeliver")
+}
+
+// webhooksEnabled returns 501, if the webhooks are disabled (in the App Engine runtime)
+func webhooksEnabled(h http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if app.webhooks == nil {
+			http.Error(w, "webhooks are not supported in the App Engine runtime", http.StatusNotImplemented)
+			return
+		}
+		h(w, r)
+	}
+}
+
+func webhookList(w http.ResponseWriter, r *http.Request) {
+	writeToClient(w, r, app.webhooks.Subscriptions(newContext(r)))
+}
+
+func addWebhook(w http.ResponseWriter, r *http.Request) {
+	wb := webhookBody{}
+	err := body.DecodeJSON(r, &wb)
+	if err != nil {
+		http.Error(w, err.Error(), body.Status(err))
+		return
+	}
+
+	s, err := app.webhooks.Subscribe(newContext(r), webhook.Subscription{URL: wb.URL, Secret: wb.Secret, Events: wb.Events})
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	writeToClient(w, r, s)
+}
+
+func getWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := webhookVar(r, "webhookID")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s, err := app.webhooks.Subscription(newContext(r), id)
+	if err != nil {
+		http.Error(w, err.Error(), webhookErrStatus(err))
+		return
+	}
+
+	writeToClient(w, r, s)
+}
+
+func updateWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := webhookVar(r, "webhookID")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	wb := webhookBody{}
+	err = body.DecodeJSON(r, &wb)
+	if err != nil {
+		http.Error(w, err.Error(), body.Status(err))
+		return
+	}
+
+	s, err := app.webhooks.UpdateSubscription(newContext(r), webhook.Subscription{ID: id, URL: wb.URL, Secret: wb.Secret, Events: wb.Events})
+	if err != nil {
+		http.Error(w, err.Error(), webhookErrStatus(err))
+		return
+	}
+
+	writeToClient(w, r, s)
+}
+
+func deleteWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := webhookVar(r, "webhookID")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	s, err := app.webhooks.Unsubscribe(newContext(r), id)
+	if err != nil {
+		http.Error(w, err.Error(), webhookErrStatus(err))
+		return
+	}
+
+	writeToClient(w, r, s)
+}
+
+func webhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	writeToClient(w, r, app.webhooks.Deliveries(newContext(r)))
+}
+
+func webhookDeadLetters(w http.ResponseWriter, r *http.Request) {
+	writeToClient(w, r, app.webhooks.DeadLetters(newContext(r)))
+}
+
+func redeliverWebhook(w http.ResponseWriter, r *http.Request) {
+	id, err := webhookVar(r, "deliveryID")
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	d, err := app.webhooks.Redeliver(newContext(r), id)
+	if err != nil {
+		http.Error(w, err.Error(), webhookErrStatus(err))
+		return
+	}
+
+	writeToClient(w, r, d)
+}
+
+// webhookVar returns the ID of the URL variable
+func webhookVar(r *http.Request, name string) (int64, error) {
+	v := mux.Vars(r)[name]
+	id, err := strconv.ParseInt(v, 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid %s: %v", name, v)
+	}
+	return id, nil
+}
+
+func webhookErrStatus(err error) int {
+	switch err {
+	case webhook.ErrSubscriptionNotFound, webhook.ErrDeliveryNotFound:
+		return http.StatusNotFound
+	}
+	// the validation of the Subscription
+	return http.StatusBadRequest
+}
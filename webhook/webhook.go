@@ -0,0 +1,582 @@
+// Package webhook deliver the changes (service.Protocol) to the subscribed URLs of the tenant.
+//
+// A delivery is a POST with the JSON Payload, it is signed with the secret of the Subscription:
+//
+//	X-Heroes-Signature: sha256=<hex(HMAC-SHA256(secret, body))>
+//
+// A delivery is successful with a 2xx status, otherwise it is retried with exponential backoff.
+// After MaxAttempts the delivery is moved to the dead letters, from where it can be redelivered.
+//
+// The URLs to loopback, link-local (e.g. the metadata server 169.254.169.254) and private addresses are rejected
+// by the Subscribe and by the dial of a delivery (see: AllowPrivate).
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// the headers of a delivery
+const (
+	HeaderSignature = "X-Heroes-Signature"
+	HeaderEvent     = "X-Heroes-Event"
+	HeaderDelivery  = "X-Heroes-Delivery"
+)
+
+// EnvMaxAttempts is the Env-Variable with the attempts of a delivery (default: DefaultMaxAttempts)
+const EnvMaxAttempts = "HEROES_WEBHOOK_ATTEMPTS"
+
+// EnvBackoff is the Env-Variable with the wait before the first retry, e.g. 1s (default: DefaultBackoff)
+const EnvBackoff = "HEROES_WEBHOOK_BACKOFF"
+
+// EnvAllowPrivate is the Env-Variable to allow the private and loopback addresses, e.g. for the development
+const EnvAllowPrivate = "HEROES_WEBHOOK_ALLOW_PRIVATE"
+
+// the defaults of the Dispatcher
+const (
+	DefaultMaxAttempts = 5
+	DefaultBackoff     = time.Second
+	DefaultTimeout     = 10 * time.Second
+)
+
+// the states of a Delivery
+const (
+	Pending   = "pending"
+	Delivered = "delivered"
+	Failed    = "failed"
+)
+
+// the number of the logged Deliveries and dead letters per tenant (the oldest are removed)
+const logSize = 100
+
+// ErrSubscriptionNotFound is the error for an unknown Subscription ID
+var ErrSubscriptionNotFound = errors.New("Webhook not Found")
+
+// ErrDeliveryNotFound is the error for an unknown dead letter
+var ErrDeliveryNotFound = errors.New("Delivery not Found")
+
+// blocked are the loopback, link-local, private and reserved networks, which are not allowed as webhook address
+var blocked = networks(
+	"0.0.0.0/8", "10.0.0.0/8", "100.64.0.0/10", "127.0.0.0/8", "169.254.0.0/16", "172.16.0.0/12",
+	"192.0.0.0/24", "192.168.0.0/16", "198.18.0.0/15", "224.0.0.0/4", "240.0.0.0/4",
+	"::/128", "::1/128", "fc00::/7", "fe80::/10", "ff00::/8",
+)
+
+func networks(cidrs ...string) []*net.IPNet {
+	nets := make([]*net.IPNet, len(cidrs))
+	for i, cidr := range cidrs {
+		_, n, err := net.ParseCIDR(cidr)
+		if err != nil {
+			panic(err)
+		}
+		nets[i] = n
+	}
+	return nets
+}
+
+// Blocked is true for a loopback, link-local, private or reserved address
+func Blocked(ip net.IP) bool {
+	for _, n := range blocked {
+		if n.Contains(ip) {
+			return true
+		}
+	}
+	return false
+}
+
+// Subscription of a URL for the events (the actions of the Protocols)
+type Subscription struct {
+	ID  int64  `json:"id"`
+	URL string `json:"url"`
+	// Secret for the signature, it is only in the response of the create (a generated Secret)
+	Secret string `json:"secret,omitempty"`
+	// Events are the actions, e.g. Add, Delete, Score (empty: all events)
+	Events  []string  `json:"events,omitempty"`
+	Created time.Time `json:"created"`
+}
+
+// Validate the URL (http or https) and the Events of the Subscription
+func (s Subscription) Validate() error {
+	u, err := url.Parse(s.URL)
+	if err != nil || !u.IsAbs() || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return fmt.Errorf("invalid webhook url (expect an absolute http or https url): %q", s.URL)
+	}
+	for _, e := range s.Events {
+		if e == "" {
+			return fmt.Errorf("invalid webhook event: %q", e)
+		}
+	}
+	return nil
+}
+
+// wants the Subscription the event
+func (s Subscription) wants(event string) bool {
+	if len(s.Events) == 0 {
+		return true
+	}
+	for _, e := range s.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Payload is the body of a delivery
+type Payload struct {
+	Delivery int64            `json:"delivery"`
+	Event    string           `json:"event"`
+	Protocol service.Protocol `json:"protocol"`
+	// Hero is the state after the change (nil after Delete, Reset and for the Scores)
+	Hero *service.Hero `json:"hero,omitempty"`
+}
+
+// Delivery of an event to a Subscription with all attempts
+type Delivery struct {
+	ID       int64     `json:"id"`
+	Webhook  int64     `json:"webhook"`
+	URL      string    `json:"url"`
+	Event    string    `json:"event"`
+	State    string    `json:"state"`
+	Attempts []Attempt `json:"attempts"`
+
+	tenant string
+	secret string
+	body   []byte
+}
+
+// Attempt of a Delivery, with the status of the response or the error
+type Attempt struct {
+	Time   time.Time `json:"time"`
+	Status int       `json:"status,omitempty"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// Sign returns the signature of the body (the value of the header X-Heroes-Signature)
+func Sign(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify the signature of the body, e.g. for the receiver
+func Verify(secret string, body []byte, signature string) bool {
+	return hmac.Equal([]byte(Sign(secret, body)), []byte(signature))
+}
+
+// the Subscriptions and Deliveries of a tenant
+type tenant struct {
+	lastID     int64
+	subs       []*Subscription
+	deliveries []*Delivery
+	dead       []*Delivery
+}
+
+// Dispatcher impl from service.Publisher, it contains the Subscriptions (per tenant)
+// and deliver the events asynchronous
+type Dispatcher struct {
+	Client      *http.Client
+	MaxAttempts int
+	// Backoff is the wait before the first retry, it is doubled for every next retry
+	Backoff time.Duration
+	// AllowPrivate allow the Blocked addresses (the Client of New check it by the dial)
+	AllowPrivate bool
+
+	mu       sync.Mutex
+	lastID   int64
+	tenants  map[string]*tenant
+	inflight sync.WaitGroup
+}
+
+// New create a Dispatcher with the defaults
+func New() *Dispatcher {
+	d := &Dispatcher{
+		MaxAttempts: DefaultMaxAttempts,
+		Backoff:     DefaultBackoff,
+		tenants:     map[string]*tenant{},
+	}
+	// without proxy, every connection (the redirects too) is checked by the dial
+	d.Client = &http.Client{Timeout: DefaultTimeout, Transport: &http.Transport{DialContext: d.dial}}
+	return d
+}
+
+// FromEnv create a Dispatcher with the Env-Variables EnvMaxAttempts and EnvBackoff
+func FromEnv() (*Dispatcher, error) {
+	d := New()
+	if s := os.Getenv(EnvMaxAttempts); s != "" {
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 {
+			return nil, fmt.Errorf("invalid %s: %s", EnvMaxAttempts, s)
+		}
+		d.MaxAttempts = n
+	}
+	if s := os.Getenv(EnvBackoff); s != "" {
+		b, err := time.ParseDuration(s)
+		if err != nil || b < 0 {
+			return nil, fmt.Errorf("invalid %s: %s", EnvBackoff, s)
+		}
+		d.Backoff = b
+	}
+	if s := os.Getenv(EnvAllowPrivate); s != "" {
+		allow, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %s", EnvAllowPrivate, s)
+		}
+		d.AllowPrivate = allow
+	}
+	return d, nil
+}
+
+// checkURL reject the URL of the Subscription, if the host is (or is resolved to) a Blocked address
+func (d *Dispatcher) checkURL(c context.Context, s string) error {
+	if d.AllowPrivate {
+		return nil
+	}
+	u, err := url.Parse(s)
+	if err != nil {
+		return err
+	}
+	host := strings.ToLower(u.Hostname())
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("invalid webhook url (the address is not allowed): %q", s)
+	}
+	_, err = d.lookup(c, host)
+	return err
+}
+
+// lookup the addresses of the host, an error if one of them is Blocked
+func (d *Dispatcher) lookup(c context.Context, host string) ([]net.IPAddr, error) {
+	ips, err := net.DefaultResolver.LookupIPAddr(c, host)
+	if err != nil {
+		return nil, fmt.Errorf("invalid webhook url (can not resolve the host): %v", err)
+	}
+	if d.AllowPrivate {
+		return ips, nil
+	}
+	for _, ip := range ips {
+		if Blocked(ip.IP) {
+			return nil, fmt.Errorf("invalid webhook url (the address %v of %s is not allowed)", ip.IP, host)
+		}
+	}
+	return ips, nil
+}
+
+// dial the checked address of the host (the address can change after the Subscribe)
+func (d *Dispatcher) dial(c context.Context, network, addr string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return nil, err
+	}
+	ips, err := d.lookup(c, host)
+	if err != nil {
+		return nil, err
+	}
+	dialer := &net.Dialer{Timeout: DefaultTimeout}
+	err = fmt.Errorf("no address of the host: %s", host)
+	for _, ip := range ips {
+		var conn net.Conn
+		conn, err = dialer.DialContext(c, network, net.JoinHostPort(ip.IP.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+	}
+	return nil, err
+}
+
+// tenant of the context (d.mu must be locked)
+func (d *Dispatcher) tenant(c context.Context) *tenant {
+	return d.tenantByName(service.TenantFromContext(c))
+}
+
+func (d *Dispatcher) tenantByName(name string) *tenant {
+	t, ok := d.tenants[name]
+	if !ok {
+		t = &tenant{}
+		d.tenants[name] = t
+	}
+	return t
+}
+
+// Subscriptions of the tenant (without the Secrets)
+func (d *Dispatcher) Subscriptions(c context.Context) []Subscription {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	subs := make([]Subscription, 0)
+	for _, s := range d.tenant(c).subs {
+		subs = append(subs, s.public())
+	}
+	return subs
+}
+
+// Subscription of the tenant by ID (without the Secret)
+func (d *Dispatcher) Subscription(c context.Context, id int64) (*Subscription, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	i := d.tenant(c).index(id)
+	if i == -1 {
+		return nil, ErrSubscriptionNotFound
+	}
+	s := d.tenant(c).subs[i].public()
+	return &s, nil
+}
+
+// Subscribe add the Subscription (without Secret: a Secret is generated), the result contains the Secret
+func (d *Dispatcher) Subscribe(c context.Context, s Subscription) (*Subscription, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	if err := d.checkURL(c, s.URL); err != nil {
+		return nil, err
+	}
+	if s.Secret == "" {
+		secret, err := newSecret()
+		if err != nil {
+			return nil, err
+		}
+		s.Secret = secret
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t := d.tenant(c)
+	t.lastID++
+	s.ID = t.lastID
+	s.Created = time.Now()
+	s.Events = append([]string{}, s.Events...)
+	stored := s
+	t.subs = append(t.subs, &stored)
+	return &s, nil
+}
+
+// UpdateSubscription change the URL and the Events (and the Secret, if it is not empty)
+func (d *Dispatcher) UpdateSubscription(c context.Context, s Subscription) (*Subscription, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	if err := d.checkURL(c, s.URL); err != nil {
+		return nil, err
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t := d.tenant(c)
+	i := t.index(s.ID)
+	if i == -1 {
+		return nil, ErrSubscriptionNotFound
+	}
+	stored := t.subs[i]
+	stored.URL = s.URL
+	stored.Events = append([]string{}, s.Events...)
+	if s.Secret != "" {
+		stored.Secret = s.Secret
+	}
+	result := stored.public()
+	return &result, nil
+}
+
+// Unsubscribe remove the Subscription, the running deliveries are not canceled
+func (d *Dispatcher) Unsubscribe(c context.Context, id int64) (*Subscription, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t := d.tenant(c)
+	i := t.index(id)
+	if i == -1 {
+		return nil, ErrSubscriptionNotFound
+	}
+	s := t.subs[i].public()
+	t.subs = append(t.subs[:i], t.subs[i+1:]...)
+	return &s, nil
+}
+
+// Deliveries of the tenant (the last first)
+func (d *Dispatcher) Deliveries(c context.Context) []Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return copies(d.tenant(c).deliveries)
+}
+
+// DeadLetters of the tenant, the failed Deliveries after all attempts (the last first)
+func (d *Dispatcher) DeadLetters(c context.Context) []Delivery {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return copies(d.tenant(c).dead)
+}
+
+// Redeliver the dead letter, it is removed from the dead letters and get new attempts
+func (d *Dispatcher) Redeliver(c context.Context, id int64) (*Delivery, error) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t := d.tenant(c)
+	for i, del := range t.dead {
+		if del.ID == id {
+			t.dead = append(t.dead[:i], t.dead[i+1:]...)
+			del.State = Pending
+			d.start(del)
+			result := del.copy()
+			return &result, nil
+		}
+	}
+	return nil, ErrDeliveryNotFound
+}
+
+// Publish impl from service.Publisher, a Delivery is created for every Subscription of the event
+func (d *Dispatcher) Publish(c context.Context, p service.Protocol, h *service.Hero) {
+	p.Tenant = service.TenantFromContext(c)
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	t := d.tenant(c)
+	for _, s := range t.subs {
+		if !s.wants(p.Action) {
+			continue
+		}
+		d.lastID++
+		b, err := json.Marshal(Payload{Delivery: d.lastID, Event: p.Action, Protocol: p, Hero: h})
+		if err != nil {
+			continue
+		}
+		del := &Delivery{ID: d.lastID, Webhook: s.ID, URL: s.URL, Event: p.Action, State: Pending,
+			tenant: p.Tenant, secret: s.Secret, body: b}
+		t.deliveries = prepend(t.deliveries, del)
+		d.start(del)
+	}
+}
+
+// Wait for the running Deliveries (with the retries)
+func (d *Dispatcher) Wait() {
+	d.inflight.Wait()
+}
+
+// start the Delivery (d.mu must be locked)
+func (d *Dispatcher) start(del *Delivery) {
+	d.inflight.Add(1)
+	go func() {
+		defer d.inflight.Done()
+		d.deliver(del)
+	}()
+}
+
+// deliver with the retries, the failed Delivery is moved to the dead letters of the tenant
+func (d *Dispatcher) deliver(del *Delivery) {
+	wait := d.Backoff
+	for i := 0; i < d.MaxAttempts; i++ {
+		if i > 0 {
+			time.Sleep(wait)
+			wait *= 2
+		}
+
+		a := d.send(del)
+		d.mu.Lock()
+		del.Attempts = append(del.Attempts, a)
+		if a.Error == "" && a.Status >= 200 && a.Status < 300 {
+			del.State = Delivered
+			d.mu.Unlock()
+			return
+		}
+		d.mu.Unlock()
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	del.State = Failed
+	t := d.tenantByName(del.tenant)
+	t.dead = prepend(t.dead, del)
+}
+
+// send one attempt of the Delivery
+func (d *Dispatcher) send(del *Delivery) Attempt {
+	a := Attempt{Time: time.Now()}
+	req, err := http.NewRequest(http.MethodPost, del.URL, bytes.NewReader(del.body))
+	if err != nil {
+		a.Error = err.Error()
+		return a
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, del.Event)
+	req.Header.Set(HeaderDelivery, strconv.FormatInt(del.ID, 10))
+	req.Header.Set(HeaderSignature, Sign(del.secret, del.body))
+
+	resp, err := d.Client.Do(req)
+	if err != nil {
+		a.Error = err.Error()
+		return a
+	}
+	resp.Body.Close()
+	a.Status = resp.StatusCode
+	if a.Status < 200 || a.Status >= 300 {
+		a.Error = resp.Status
+	}
+	return a
+}
+
+func (t *tenant) index(id int64) int {
+	for i, s := range t.subs {
+		if s.ID == id {
+			return i
+		}
+	}
+	return -1
+}
+
+// public is a copy without the Secret
+func (s *Subscription) public() Subscription {
+	p := *s
+	p.Secret = ""
+	p.Events = append([]string{}, s.Events...)
+	return p
+}
+
+func (del *Delivery) copy() Delivery {
+	c := *del
+	c.Attempts = append([]Attempt{}, del.Attempts...)
+	return c
+}
+
+func copies(dels []*Delivery) []Delivery {
+	result := make([]Delivery, len(dels))
+	for i, del := range dels {
+		result[i] = del.copy()
+	}
+	return result
+}
+
+// prepend the Delivery, the list contains max logSize Deliveries
+func prepend(dels []*Delivery, del *Delivery) []*Delivery {
+	dels = append([]*Delivery{del}, dels...)
+	if len(dels) > logSize {
+		dels = dels[:logSize]
+	}
+	return dels
+}
+
+func newSecret() (string, error) {
+	b := make([]byte, 20)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,226 @@
+package webhook
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/lima1909/goheroes-appengine/service"
+)
+
+// receiver record the deliveries, the first fails requests are answered with 500
+type receiver struct {
+	mu       sync.Mutex
+	fails    int
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (rc *receiver) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	b, _ := ioutil.ReadAll(r.Body)
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	rc.requests = append(rc.requests, r)
+	rc.bodies = append(rc.bodies, b)
+	if len(rc.requests) <= rc.fails {
+		w.WriteHeader(http.StatusInternalServerError)
+	}
+}
+
+func newDispatcher() *Dispatcher {
+	d := New()
+	d.MaxAttempts = 3
+	d.Backoff = time.Millisecond
+	// the test receivers are on localhost
+	d.AllowPrivate = true
+	return d
+}
+
+func TestValidate(t *testing.T) {
+	for _, u := range []string{"", "localhost:8080/hook", "/hook", "ftp://example.com/hook", "http://"} {
+		if err := (Subscription{URL: u}).Validate(); err == nil {
+			t.Errorf("expect an error for: %q", u)
+		}
+	}
+	if err := (Subscription{URL: "https://example.com/hook", Events: []string{"Add"}}).Validate(); err != nil {
+		t.Errorf("No err expected: %v", err)
+	}
+}
+
+func TestBlocked(t *testing.T) {
+	d := New()
+	c := context.Background()
+	for _, u := range []string{"http://127.0.0.1:8080/hook", "http://localhost/hook", "http://169.254.169.254/computeMetadata",
+		"http://10.1.2.3/hook", "http://[::1]/hook", "http://[fd00::1]/hook", "http://[::ffff:192.168.0.1]/hook"} {
+		if _, err := d.Subscribe(c, Subscription{URL: u}); err == nil {
+			t.Errorf("expect an error for: %q", u)
+		}
+	}
+	if Blocked(net.ParseIP("93.184.216.34")) || Blocked(net.ParseIP("2606:2800:220:1::")) {
+		t.Errorf("expect the public addresses are not blocked")
+	}
+
+	// the address is checked by the dial too
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	d = newDispatcher()
+	if _, err := d.Subscribe(c, Subscription{URL: server.URL}); err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	d.AllowPrivate = false
+	d.Publish(c, service.NewProtocol("Add", 1, "add"), nil)
+	d.Wait()
+	dead := d.DeadLetters(c)
+	if len(dead) != 1 || !strings.Contains(dead[0].Attempts[0].Error, "is not allowed") {
+		t.Errorf("expect the dead letter with the not allowed address, got: %v", dead)
+	}
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if len(rc.requests) != 0 {
+		t.Errorf("expect no request, got: %v", len(rc.requests))
+	}
+}
+
+func TestSignedDelivery(t *testing.T) {
+	rc := &receiver{}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	d := newDispatcher()
+	c := service.WithTenant(context.Background(), "acme")
+	s, err := d.Subscribe(c, Subscription{URL: server.URL, Events: []string{"Add"}})
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if s.Secret == "" || s.ID != 1 {
+		t.Errorf("expect a generated Secret, got: %v", s)
+	}
+
+	d.Publish(c, service.NewProtocol("Add", 1, "add"), &service.Hero{ID: 1, Name: "Jasmin"})
+	// not subscribed event and other tenant
+	d.Publish(c, service.NewProtocol("Delete", 1, "delete"), nil)
+	d.Publish(context.Background(), service.NewProtocol("Add", 2, "add"), nil)
+	d.Wait()
+
+	rc.mu.Lock()
+	defer rc.mu.Unlock()
+	if len(rc.requests) != 1 {
+		t.Fatalf("expect one delivery, got: %v", len(rc.requests))
+	}
+	r, body := rc.requests[0], rc.bodies[0]
+	if !Verify(s.Secret, body, r.Header.Get(HeaderSignature)) || Verify("wrong", body, r.Header.Get(HeaderSignature)) {
+		t.Errorf("invalid signature: %v", r.Header.Get(HeaderSignature))
+	}
+	if r.Header.Get(HeaderEvent) != "Add" || r.Header.Get(HeaderDelivery) != "1" {
+		t.Errorf("unexpected headers: %v", r.Header)
+	}
+	p := Payload{}
+	if err := json.Unmarshal(body, &p); err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if p.Protocol.Tenant != "acme" || p.Hero == nil || p.Hero.Name != "Jasmin" {
+		t.Errorf("unexpected payload: %v", p)
+	}
+
+	dels := d.Deliveries(c)
+	if len(dels) != 1 || dels[0].State != Delivered || len(dels[0].Attempts) != 1 || dels[0].Attempts[0].Status != http.StatusOK {
+		t.Errorf("unexpected deliveries: %v", dels)
+	}
+	if subs := d.Subscriptions(c); len(subs) != 1 || subs[0].Secret != "" {
+		t.Errorf("expect the Subscription without Secret, got: %v", subs)
+	}
+	if subs := d.Subscriptions(context.Background()); len(subs) != 0 {
+		t.Errorf("expect no Subscriptions of the other tenant, got: %v", subs)
+	}
+}
+
+func TestRetryAndDeadLetter(t *testing.T) {
+	rc := &receiver{fails: 4}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	d := newDispatcher()
+	c := context.Background()
+	d.Subscribe(c, Subscription{URL: server.URL, Secret: "secret"})
+
+	// the first delivery fails after 3 attempts
+	d.Publish(c, service.NewProtocol("Update", 1, "update"), nil)
+	d.Wait()
+	dead := d.DeadLetters(c)
+	if len(dead) != 1 || dead[0].State != Failed || len(dead[0].Attempts) != 3 || dead[0].Attempts[2].Status != http.StatusInternalServerError {
+		t.Fatalf("expect the dead letter after 3 attempts, got: %v", dead)
+	}
+
+	// redeliver: the 4. request fails, the 5. is successful
+	del, err := d.Redeliver(c, dead[0].ID)
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	d.Wait()
+	if len(d.DeadLetters(c)) != 0 {
+		t.Errorf("expect no dead letters, got: %v", d.DeadLetters(c))
+	}
+	dels := d.Deliveries(c)
+	if len(dels) != 1 || dels[0].ID != del.ID || dels[0].State != Delivered || len(dels[0].Attempts) != 5 {
+		t.Errorf("unexpected deliveries: %v", dels)
+	}
+
+	if _, err := d.Redeliver(c, 99); err != ErrDeliveryNotFound {
+		t.Errorf("expect ErrDeliveryNotFound, got: %v", err)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	rc := &receiver{fails: 3}
+	server := httptest.NewServer(rc)
+	defer server.Close()
+
+	d := newDispatcher()
+	d.Backoff = 20 * time.Millisecond
+	c := context.Background()
+	d.Subscribe(c, Subscription{URL: server.URL})
+
+	start := time.Now()
+	d.Publish(c, service.NewProtocol("Update", 1, "update"), nil)
+	d.Wait()
+	// 20ms + 40ms
+	if elapsed := time.Since(start); elapsed < 60*time.Millisecond {
+		t.Errorf("expect the exponential backoff, got: %v", elapsed)
+	}
+}
+
+func TestUpdateAndUnsubscribe(t *testing.T) {
+	d := newDispatcher()
+	c := context.Background()
+	s, _ := d.Subscribe(c, Subscription{URL: "http://localhost/a", Secret: "first"})
+
+	s.URL, s.Secret = "http://localhost/b", ""
+	if _, err := d.UpdateSubscription(c, *s); err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	d.mu.Lock()
+	if stored := d.tenant(c).subs[0]; stored.URL != "http://localhost/b" || stored.Secret != "first" {
+		t.Errorf("expect the new URL with the old Secret, got: %v", stored)
+	}
+	d.mu.Unlock()
+
+	s.URL = "invalid"
+	if _, err := d.UpdateSubscription(c, *s); err == nil {
+		t.Errorf("expect an error for the invalid URL")
+	}
+	if _, err := d.Unsubscribe(c, s.ID); err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	if _, err := d.Subscription(c, s.ID); err != ErrSubscriptionNotFound {
+		t.Errorf("expect ErrSubscriptionNotFound, got: %v", err)
+	}
+}
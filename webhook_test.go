@@ -0,0 +1,128 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lima1909/goheroes-appengine/db"
+	"github.com/lima1909/goheroes-appengine/events"
+	"github.com/lima1909/goheroes-appengine/webhook"
+)
+
+func TestWebhooks(t *testing.T) {
+	svc := app.ProtocolHeroService
+	app.ProtocolHeroService = events.NewHeroService(db.NewMemService(), app.webhooks)
+	// the receiver is on localhost
+	app.webhooks.AllowPrivate = true
+	defer func() {
+		app.ProtocolHeroService = svc
+		app.webhooks.AllowPrivate = false
+	}()
+
+	var mu sync.Mutex
+	received := map[string]string{}
+	receiver := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		mu.Lock()
+		defer mu.Unlock()
+		received[r.Header.Get(webhook.HeaderSignature)] = string(b)
+	}))
+	defer receiver.Close()
+
+	resp, err := http.Post(server.URL+"/api/webhooks", "application/json",
+		strings.NewReader(fmt.Sprintf(`{"url": %q, "events": ["Add"]}`, receiver.URL)))
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	s := webhook.Subscription{}
+	json.NewDecoder(resp.Body).Decode(&s)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || s.Secret == "" {
+		t.Fatalf("expect the Subscription with the Secret, got: %v %v", resp.StatusCode, s)
+	}
+	defer app.webhooks.Unsubscribe(context.TODO(), s.ID)
+
+	h, err := app.Add(context.TODO(), "Webhook")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	app.Delete(context.TODO(), h.ID)
+	app.webhooks.Wait()
+
+	mu.Lock()
+	if len(received) != 1 {
+		t.Errorf("expect only the Add, got: %v", received)
+	}
+	for signature, b := range received {
+		if !webhook.Verify(s.Secret, []byte(b), signature) || !strings.Contains(b, `"event":"Add"`) {
+			t.Errorf("unexpected delivery: %v %v", signature, b)
+		}
+	}
+	mu.Unlock()
+
+	resp, err = http.Get(server.URL + "/api/admin/webhooks/deliveries")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	deliveries := []webhook.Delivery{}
+	json.NewDecoder(resp.Body).Decode(&deliveries)
+	resp.Body.Close()
+	if len(deliveries) == 0 || deliveries[0].Webhook != s.ID || deliveries[0].State != webhook.Delivered {
+		t.Errorf("unexpected deliveries: %v", deliveries)
+	}
+
+	resp, err = http.Get(fmt.Sprintf("%s/api/webhooks/%d", server.URL, s.ID))
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	b, _ := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || strings.Contains(string(b), s.Secret) {
+		t.Errorf("expect the Subscription without the Secret, got: %v %v", resp.StatusCode, string(b))
+	}
+
+	for _, tc := range []struct {
+		method, url, body string
+		status            int
+	}{
+		{"POST", "/api/webhooks", `{"url": "ftp://localhost"}`, http.StatusBadRequest},
+		{"PUT", "/api/webhooks/9999", `{"url": "http://localhost"}`, http.StatusNotFound},
+		{"DELETE", "/api/webhooks/9999", "", http.StatusNotFound},
+		{"POST", "/api/admin/webhooks/dead-letters/9999", "", http.StatusNotFound},
+	} {
+		req, _ := http.NewRequest(tc.method, server.URL+tc.url, strings.NewReader(tc.body))
+		if tc.body != "" {
+			req.Header.Set("Content-Type", "application/json")
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("No err expected: %v", err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != tc.status {
+			t.Errorf("%s %s: expect %v, got: %v", tc.method, tc.url, tc.status, resp.StatusCode)
+		}
+	}
+}
+
+func TestWebhooksDisabled(t *testing.T) {
+	webhooks := app.webhooks
+	app.webhooks = nil
+	defer func() { app.webhooks = webhooks }()
+
+	resp, err := http.Get(server.URL + "/api/webhooks")
+	if err != nil {
+		t.Fatalf("No err expected: %v", err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode != http.StatusNotImplemented {
+		t.Errorf("expect 501 without the Dispatcher, got: %v", resp.StatusCode)
+	}
+}